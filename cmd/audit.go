@@ -0,0 +1,147 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+type auditReport struct {
+	Project         string            `json:"project"`
+	Dir             string            `json:"dir"`
+	Vulnerabilities utils.AuditCounts `json:"vulnerabilities"`
+	Error           string            `json:"error,omitempty"`
+}
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Scan every project's dependencies for known vulnerabilities",
+	Long: `Runs npm, pnpm or yarn audit and composer audit in every project at once
+and prints the vulnerabilities found by severity. Exits with 1 when any
+project has vulnerabilities of the --fail-on severity or worse, or when a
+project couldn't be audited.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		wd, err := os.Getwd()
+		if err != nil {
+			panic(err)
+		}
+
+		failOn, _ := cmd.Flags().GetString("fail-on")
+		if conf := utils.GetConfig(); !cmd.Flags().Changed("fail-on") && conf.AuditLevel != "" {
+			failOn = conf.AuditLevel
+		}
+		if !slices.Contains(utils.SEVERITIES, failOn) {
+			exitWithError(fmt.Errorf("unknown severity %q, expected one of %s", failOn, strings.Join(utils.SEVERITIES, ", ")))
+		}
+
+		asJSON, _ := cmd.Flags().GetBool("json")
+		projects := listProjects(cmd, wd)
+
+		reports := make([]auditReport, len(projects))
+		var wg sync.WaitGroup
+		for i, project := range projects {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				manager := utils.ProjectManager(types.Project{Dir: project.Dir, FS: project.FS, Workspace: project.Workspace})
+				counts, err := utils.Audit(project.Dir, manager)
+				reports[i] = auditReport{Project: project.Name, Dir: project.Dir, Vulnerabilities: counts}
+				if err != nil {
+					reports[i].Error = err.Error()
+				}
+			}()
+		}
+		wg.Wait()
+
+		failing, broken := 0, 0
+		for _, report := range reports {
+			if report.Error != "" {
+				broken++
+			}
+			if report.Vulnerabilities.AtLeast(failOn) > 0 {
+				failing++
+			}
+		}
+
+		if asJSON {
+			out, err := json.MarshalIndent(reports, "", "  ")
+			if err != nil {
+				panic(err)
+			}
+			fmt.Println(string(out))
+		} else {
+			printAudit(reports, failOn, failing)
+		}
+
+		if failing > 0 || broken > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func printAudit(reports []auditReport, failOn string, failing int) {
+	severities := slices.Clone(utils.SEVERITIES)
+	slices.Reverse(severities)
+
+	rows := [][]string{}
+	for _, report := range reports {
+		if report.Error != "" {
+			rows = append(rows, []string{report.Project, errorText.Render(fmt.Sprintf("Error: %s", report.Error)), "", "", "", ""})
+			continue
+		}
+
+		row := []string{report.Project}
+		for _, severity := range severities {
+			count := strconv.Itoa(report.Vulnerabilities[severity])
+			if report.Vulnerabilities[severity] > 0 && slices.Index(utils.SEVERITIES, severity) >= slices.Index(utils.SEVERITIES, failOn) {
+				count = errorText.Render(count)
+			}
+			row = append(row, count)
+		}
+		rows = append(rows, row)
+	}
+
+	headers := []string{"Project"}
+	for _, severity := range severities {
+		headers = append(headers, strings.ToUpper(severity[:1])+severity[1:])
+	}
+
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(borderColor)).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			switch {
+			case row == table.HeaderRow:
+				return headerStyle
+			case row%2 == 0:
+				return evenRowStyle
+			default:
+				return oddRowStyle
+			}
+		}).
+		Headers(headers...).
+		Rows(rows...)
+
+	fmt.Println(t)
+	fmt.Println(subtleText.Render(fmt.Sprintf("%d of %d projects have %s or worse vulnerabilities", failing, len(reports), failOn)))
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.Flags().Bool("json", false, "Print the vulnerabilities as JSON")
+	auditCmd.Flags().String("fail-on", "high", "lowest severity failing the audit: info, low, moderate, high or critical")
+}
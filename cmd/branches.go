@@ -0,0 +1,125 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/utils"
+)
+
+// formatAge renders how long ago something happened in the largest sensible
+// unit.
+func formatAge(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	switch {
+	case days < 1:
+		return "today"
+	case days < 14:
+		return fmt.Sprintf("%dd", days)
+	case days < 60:
+		return fmt.Sprintf("%dw", days/7)
+	default:
+		return fmt.Sprintf("%dmo", days/30)
+	}
+}
+
+// branchesCmd represents the branches command
+var branchesCmd = &cobra.Command{
+	Use:   "branches",
+	Short: "List local branches of every project repository and flag stale ones",
+	Long: `Lists the local branches of every repository containing a project with the
+age of their last commit and whether they are merged into the default
+branch. Merged branches and branches older than --stale-after are stale.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		wd, err := os.Getwd()
+		if err != nil {
+			panic(err)
+		}
+
+		staleAfter, _ := cmd.Flags().GetDuration("stale-after")
+		onlyStale, _ := cmd.Flags().GetBool("stale")
+
+		repos := []string{}
+		for _, project := range listProjects(cmd, wd) {
+			if root, ok := utils.GitRoot(project.Dir); ok && !slices.Contains(repos, root) {
+				repos = append(repos, root)
+			}
+		}
+
+		rows := [][]string{}
+		stale := 0
+		for _, repo := range repos {
+			name, err := filepath.Rel(wd, repo)
+			if err != nil || name == "." {
+				name = filepath.Base(repo)
+			}
+
+			branches, err := utils.Branches(repo)
+			if err != nil {
+				rows = append(rows, []string{name, errorText.Render(fmt.Sprintf("Error: %s", err)), "", "", ""})
+				continue
+			}
+
+			base := utils.DefaultBranch(repo)
+			for _, branch := range branches {
+				isStale := branch.Stale(base, staleAfter)
+				if isStale {
+					stale++
+				} else if onlyStale {
+					continue
+				}
+
+				branchName := branch.Name
+				if branch.Current {
+					branchName = highlightText.Render("* " + branch.Name)
+				}
+
+				merged := ""
+				if branch.Merged && branch.Name != base {
+					merged = "merged"
+				}
+
+				flag := ""
+				if isStale {
+					flag = errorText.Render("stale")
+				}
+
+				rows = append(rows, []string{name, branchName, formatAge(time.Since(branch.LastCommit)), merged, flag})
+			}
+		}
+
+		t := table.New().
+			Border(lipgloss.NormalBorder()).
+			BorderStyle(lipgloss.NewStyle().Foreground(borderColor)).
+			StyleFunc(func(row, col int) lipgloss.Style {
+				switch {
+				case row == table.HeaderRow:
+					return headerStyle
+				case row%2 == 0:
+					return evenRowStyle
+				default:
+					return oddRowStyle
+				}
+			}).
+			Headers("Repository", "Branch", "Last commit", "Merged", "").
+			Rows(rows...)
+
+		fmt.Println(t)
+		fmt.Println(subtleText.Render(fmt.Sprintf("%d stale branches across %d repositories", stale, len(repos))))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(branchesCmd)
+	branchesCmd.Flags().Duration("stale-after", 30*24*time.Hour, "Age after which an unmerged branch is stale")
+	branchesCmd.Flags().Bool("stale", false, "Only list stale branches")
+}
@@ -5,7 +5,7 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
-	"jrmd.dev/qk/utils"
+	"jrmd.dev/qk/tooling"
 	"jrmd.dev/qk/views"
 )
 
@@ -13,14 +13,27 @@ import (
 var buildCmd = &cobra.Command{
 	Use:     "build",
 	Aliases: []string{"b"},
-	Short:   "Runs yarn build:prod across all projects",
+	Short:   "Runs build:prod across all projects",
+	Long: `Runs build:prod across all projects, go build ./... in go modules,
+cargo build in rust crates, the build target of Makefile and Taskfile
+projects and the Build of configured ProjectTypes, after the projects they
+depend on.
+Projects whose files, lockfiles and commands haven't changed since their
+last successful build, nor those of the projects they depend on, are
+skipped. Pass --force to build them anyway, see qk cache to inspect the
+recorded builds.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		depth, _ := cmd.Flags().GetInt("depth");
-		joined, _ := cmd.Flags().GetBool("joined");
-		m := views.CreateCommandRunner(depth, joined)
+		m := views.CreateCommandRunner(runnerOptions(cmd))
+		if ignoreDeps, _ := cmd.Flags().GetBool("ignore-deps"); !ignoreDeps {
+			m.OrderByDependencies()
+		}
+		m.AddPhase(tooling.Build, RenderCommand)
+		force, _ := cmd.Flags().GetBool("force")
+		autoInstall, _ := cmd.Flags().GetBool("auto-install")
 		m.
-			AddOptionalCommand(utils.HasYarn, RenderCommand("yarn"), "yarn", "build:prod").
-			AddOptionalCommand(utils.Not(utils.HasYarn), RenderCommand("npm"), "npm", "run", "build:prod").
+			UseBuildCache(force).
+			AddHooks("build", RenderCommand).
+			CheckDependencies(autoInstall, RenderCommand).
 			Run()
 	},
 }
@@ -28,6 +41,9 @@ var buildCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(buildCmd)
 	buildCmd.Flags().BoolP("joined", "j", false, "Joined output")
+	buildCmd.Flags().Bool("auto-install", false, "Install dependencies first in projects whose lockfile changed since the last install")
+	buildCmd.Flags().BoolP("force", "f", false, "Build projects even when nothing changed since their last successful build")
+	buildCmd.Flags().Bool("ignore-deps", false, "Build every project at once instead of after its dependencies")
 
 	// Here you will define your flags and configuration settings.
 
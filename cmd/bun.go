@@ -0,0 +1,45 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/views"
+	"os"
+)
+
+// bunCmd represents the bun command
+var bunCmd = &cobra.Command{
+	Use:     "bun",
+	Aliases: []string{"bn"},
+	Short:   "run a bun command across all projects",
+	Long:    `This command runs your bun command in all project folders`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			fmt.Println("Provide a command...")
+			os.Exit(1)
+		}
+
+		m := views.CreateCommandRunner(runnerOptions(cmd))
+		m.
+			AddCommand(RenderCommand("bun"), "bun", args...).
+			Run()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bunCmd)
+	bunCmd.Flags().BoolP("joined", "j", false, "Joined output")
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// cmdCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// cmdCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -0,0 +1,119 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/utils"
+)
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the cached project list and recorded builds",
+	Long: `Projects found under a directory are cached in ~/.qk/discovery.json and
+reused until one of the walked directories changes. Use --no-cache to skip the
+cache for a single run.
+
+qk build records the inputs of every project it built successfully in
+~/.qk/builds.json and skips the project until they change. Use qk build
+--force to skip the recorded builds for a single run.`,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Forget the cached project lists and recorded builds",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := utils.ClearDiscoveryCache(); err != nil {
+			exitWithError(err)
+		}
+		if err := utils.ClearBuildCache(); err != nil {
+			exitWithError(err)
+		}
+		fmt.Println("Discovery cache and recorded builds cleared")
+	},
+}
+
+var cacheLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List the recorded builds",
+	Run: func(cmd *cobra.Command, args []string) {
+		cache := utils.ReadBuildCache()
+		if len(cache) == 0 {
+			fmt.Println(subtleText.Render("No recorded builds"))
+			return
+		}
+
+		entries := []utils.BuildCacheEntry{}
+		for _, entry := range cache {
+			entries = append(entries, entry)
+		}
+		slices.SortFunc(entries, func(a, b utils.BuildCacheEntry) int { return b.Time.Compare(a.Time) })
+
+		rows := [][]string{}
+		for _, entry := range entries {
+			age := formatAge(time.Since(entry.Time))
+			if exists, _ := utils.FileExists(entry.Dir); !exists {
+				age = errorText.Render("missing")
+			}
+			rows = append(rows, []string{entry.Dir, entry.Hash[:12], entry.Time.Format("2006-01-02 15:04"), age})
+		}
+
+		t := table.New().
+			Border(lipgloss.NormalBorder()).
+			BorderStyle(lipgloss.NewStyle().Foreground(borderColor)).
+			StyleFunc(func(row, col int) lipgloss.Style {
+				switch {
+				case row == table.HeaderRow:
+					return headerStyle
+				case row%2 == 0:
+					return evenRowStyle
+				default:
+					return oddRowStyle
+				}
+			}).
+			Headers("Project", "Inputs", "Built", "Age").
+			Rows(rows...)
+
+		fmt.Println(t)
+	},
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Forget recorded builds of missing projects or older than --older-than",
+	Run: func(cmd *cobra.Command, args []string) {
+		olderThan, _ := cmd.Flags().GetDuration("older-than")
+
+		cache := utils.ReadBuildCache()
+		pruned := 0
+		for dir, entry := range cache {
+			exists, _ := utils.FileExists(dir)
+			if exists && (olderThan == 0 || time.Since(entry.Time) < olderThan) {
+				continue
+			}
+			delete(cache, dir)
+			pruned++
+		}
+
+		if err := utils.WriteBuildCache(cache); err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("Pruned %d of %d recorded builds\n", pruned, pruned+len(cache))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	cacheCmd.AddCommand(cacheLsCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+	cachePruneCmd.Flags().Duration("older-than", 0, "Also forget builds recorded longer ago than this, e.g. 720h")
+}
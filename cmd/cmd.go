@@ -6,36 +6,79 @@ package cmd
 import (
 	"fmt"
 	"github.com/spf13/cobra"
+	"jrmd.dev/qk/utils"
 	"jrmd.dev/qk/views"
 	"os"
+	"strings"
 )
 
 // cmdCmd represents the cmd command
 var cmdCmd = &cobra.Command{
 	Use:   "cmd",
 	Short: "run a custom command across all projects",
-	Long:  `This command runs your custom command in all project folders`,
+	Long: `This command runs your custom command in all project folders.
+
+The command can use the project's {{.Name}}, {{.Dir}}, {{.Path}} relative to
+the current directory, {{.Manager}}, {{.Type}} and {{.Group}}, e.g.
+
+  qk cmd 'echo {{.Name}} in {{.Dir}}'
+
+A single quoted argument is split into words after substitution, and the
+substituted values are quoted so a path with spaces stays one word. With
+--shell the arguments are joined and run through $SHELL -c instead, so pipes,
+&& and redirects work.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if useShell, _ := cmd.Flags().GetBool("shell"); useShell && len(args) > 0 {
+			shell := os.Getenv("SHELL")
+			if shell == "" {
+				shell = "sh"
+			}
+
+			line := strings.Join(args, " ")
+			m := views.CreateCommandRunner(runnerOptions(cmd))
+			if pattern, ok := dangerousPattern(cmd, line); ok {
+				m.ConfirmDangerous(line, pattern)
+			}
+			m.
+				AddTemplateLine(RenderCommand(line), line, shell).
+				Run()
+			return
+		}
+
 		if len(args) == 0 {
 			fmt.Println("Provide a command...")
 			os.Exit(1)
 		}
 
-		c := args[0]
-		arg := args[1:]
-
-		depth, _ := cmd.Flags().GetInt("depth");
-		joined, _ := cmd.Flags().GetBool("joined");
-		m := views.CreateCommandRunner(depth, joined)
+		m := views.CreateCommandRunner(runnerOptions(cmd))
+		if pattern, ok := dangerousPattern(cmd, strings.Join(args, " ")); ok {
+			m.ConfirmDangerous(strings.Join(args, " "), pattern)
+		}
+		if len(args) == 1 {
+			m.AddTemplateLine(RenderCommand(args[0]), args[0], "").Run()
+			return
+		}
 		m.
-			AddCommand(RenderCommand(c), c, arg...).
+			AddTemplateCommand(RenderCommand(args[0]), args[0], args[1:]...).
 			Run()
 	},
 }
 
+// dangerousPattern returns the dangerous pattern line matches, unless --yes
+// was passed.
+func dangerousPattern(cmd *cobra.Command, line string) (string, bool) {
+	if yes, _ := cmd.Flags().GetBool("yes"); yes {
+		return "", false
+	}
+
+	return utils.DangerousMatch(utils.GetConfig(), line)
+}
+
 func init() {
 	rootCmd.AddCommand(cmdCmd)
 	cmdCmd.Flags().BoolP("joined", "j", false, "Joined output")
+	cmdCmd.Flags().Bool("shell", false, "Run the command through $SHELL -c")
+	cmdCmd.Flags().BoolP("yes", "y", false, "Run commands matching a dangerous pattern without asking")
 
 	// Here you will define your flags and configuration settings.
 
@@ -0,0 +1,122 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/utils"
+)
+
+// completionProjects discovers the projects completions offer. Discovery
+// goes through the same cache as commands, so completing stays quick in
+// large trees once qk has run there.
+func completionProjects(cmd *cobra.Command) []utils.File {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+
+	depth, _ := cmd.Flags().GetInt("depth")
+	return utils.GetAllProjects(wd, depth, 0)
+}
+
+// completeProjectNames completes the first argument with a project name.
+func completeProjectNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names := []string{}
+	for _, project := range completionProjects(cmd) {
+		if strings.HasPrefix(project.Name, toComplete) {
+			names = append(names, project.Name)
+		}
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeOnly completes the last of the comma separated project names
+// given to --only, leaving out the ones already listed.
+func completeOnly(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	listed := strings.Split(toComplete, ",")
+	prefix := strings.Join(listed[:len(listed)-1], ",")
+	if prefix != "" {
+		prefix += ","
+	}
+	partial := listed[len(listed)-1]
+
+	names := []string{}
+	for _, project := range completionProjects(cmd) {
+		if strings.HasPrefix(project.Name, partial) && !slices.Contains(listed[:len(listed)-1], project.Name) {
+			names = append(names, prefix+project.Name)
+		}
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTags completes the last of the comma separated tags given to
+// --tag with the configured ones.
+func completeTags(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	listed := strings.Split(toComplete, ",")
+	prefix := strings.Join(listed[:len(listed)-1], ",")
+	if prefix != "" {
+		prefix += ","
+	}
+	partial := listed[len(listed)-1]
+
+	tags := []string{}
+	for _, tag := range slices.Sorted(maps.Keys(utils.GetConfig().Tags)) {
+		if strings.HasPrefix(tag, partial) && !slices.Contains(listed[:len(listed)-1], tag) {
+			tags = append(tags, prefix+tag)
+		}
+	}
+
+	return tags, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeScripts completes the script qk run runs with the package.json
+// and composer.json scripts of every project, described by how many
+// projects define them.
+func completeScripts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+
+	counts := map[string]int{}
+	for _, project := range completionProjects(cmd) {
+		scripts := slices.Concat(utils.GetScripts(project.FS, project.Dir), utils.GetComposerScripts(project.FS, project.Dir), utils.Tasks(project.FS, project.Dir), utils.MakeTargets(project.FS, project.Dir))
+		slices.Sort(scripts)
+		for _, script := range slices.Compact(scripts) {
+			counts[script]++
+		}
+	}
+
+	scripts := []string{}
+	for script, n := range counts {
+		if !strings.HasPrefix(script, toComplete) {
+			continue
+		}
+		if n == 1 {
+			scripts = append(scripts, fmt.Sprintf("%s\tin 1 project", script))
+		} else {
+			scripts = append(scripts, fmt.Sprintf("%s\tin %d projects", script, n))
+		}
+	}
+	slices.Sort(scripts)
+
+	return scripts, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	runCmd.ValidArgsFunction = completeScripts
+	inCmd.ValidArgsFunction = completeProjectNames
+}
@@ -0,0 +1,80 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/utils"
+	"jrmd.dev/qk/views"
+)
+
+// runCompose runs docker compose with args in every project with a compose
+// file, showing the state of its containers next to it.
+func runCompose(cmd *cobra.Command, args ...string) {
+	bin, args := utils.ComposeArgs(args...)
+
+	m := views.CreateCommandRunner(runnerOptions(cmd))
+	m.
+		TrackContainers().
+		AddOptionalCommand(utils.HasComposeFile, RenderCommand("compose"), bin, args...).
+		Run()
+}
+
+// upCmd represents the up command
+var upCmd = &cobra.Command{
+	Use:   "up [services...]",
+	Short: "Start the docker compose services of every project with a compose file",
+	Long: `Runs docker compose up in every project with a compose.yaml or
+docker-compose.yml, showing how many of its containers are up and healthy next
+to it. With --detach the services are started in the background and qk waits
+until they are running, or healthy when they have a health check.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if detach, _ := cmd.Flags().GetBool("detach"); detach {
+			args = append([]string{"--detach", "--wait"}, args...)
+		}
+
+		runCompose(cmd, append([]string{"up"}, args...)...)
+	},
+}
+
+// downCmd represents the down command
+var downCmd = &cobra.Command{
+	Use:   "down [args...]",
+	Short: "Stop and remove the docker compose services of every project",
+	Long: `Runs docker compose down in every project with a compose file, extra
+arguments are passed on after --, e.g. qk down -- --volumes.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runCompose(cmd, append([]string{"down"}, args...)...)
+	},
+}
+
+// composeCmd represents the compose command
+var composeCmd = &cobra.Command{
+	Use:   "compose <args...>",
+	Short: "Run a docker compose command in every project with a compose file",
+	Long: `Runs docker compose with the given arguments in every project with a
+compose file, e.g. qk compose pull or qk compose -- logs --tail 20.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			fmt.Println("Provide a command...")
+			os.Exit(1)
+		}
+
+		runCompose(cmd, args...)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(upCmd)
+	rootCmd.AddCommand(downCmd)
+	rootCmd.AddCommand(composeCmd)
+
+	upCmd.Flags().BoolP("detach", "d", false, "start the services in the background and wait until they are up")
+	for _, c := range []*cobra.Command{upCmd, downCmd, composeCmd} {
+		c.Flags().BoolP("joined", "j", false, "Joined output")
+	}
+}
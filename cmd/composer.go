@@ -22,11 +22,10 @@ var composerCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		depth, _ := cmd.Flags().GetInt("depth");
-		joined, _ := cmd.Flags().GetBool("joined");
-		m := views.CreateCommandRunner(depth, joined)
+		m := views.CreateCommandRunner(runnerOptions(cmd))
 		m.
 			AddCommand(RenderCommand("composer"), "composer", args...).
+			CheckPhp().
 			Run()
 	},
 }
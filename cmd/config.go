@@ -0,0 +1,216 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/utils"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and edit qk settings",
+	Long: `Reads and writes ~/.qk.json, or with --local the repo-level .qk.json
+nearest to the current directory, which is applied on top of it.
+
+Keys are the setting names, e.g. GracePeriod. Map settings take a sub key:
+
+  qk config set Ports.app 3000
+  qk config set --local Detect any`,
+}
+
+func exitWithError(err error) {
+	fmt.Println(errorText.Render(fmt.Sprintf("Error: %s", err)))
+	os.Exit(1)
+}
+
+// configTarget returns the config file a command reads and writes.
+func configTarget(cmd *cobra.Command) string {
+	if local, _ := cmd.Flags().GetBool("local"); local {
+		return localConfigTarget()
+	}
+
+	return utils.GlobalConfigPath()
+}
+
+// localConfigTarget returns the nearest repo-level .qk.json, or a new one
+// at the root of the current repository, in the current directory outside
+// of one.
+func localConfigTarget() string {
+	if file := utils.LocalConfigPath(); file != "" {
+		return file
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		exitWithError(err)
+	}
+	if root, ok := utils.GitRoot(wd); ok {
+		wd = root
+	}
+	return path.Join(wd, utils.ConfigFile)
+}
+
+// splitConfigKey resolves a key such as ports.app into Ports and app.
+func splitConfigKey(raw string) (string, string) {
+	name, sub, _ := strings.Cut(raw, ".")
+	key, ok := utils.ConfigKey(name)
+	if !ok {
+		exitWithError(fmt.Errorf("unknown setting %q, expected one of %s", name, strings.Join(utils.ConfigKeys(), ", ")))
+	}
+
+	return key, sub
+}
+
+func printJSON(value any) {
+	out, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		exitWithError(err)
+	}
+	fmt.Println(string(out))
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print the settings in effect, or those of one file with --global or --local",
+	Run: func(cmd *cobra.Command, args []string) {
+		global, _ := cmd.Flags().GetBool("global")
+		local, _ := cmd.Flags().GetBool("local")
+		if !global && !local {
+			printJSON(utils.GetConfig())
+			return
+		}
+
+		values, err := utils.ReadConfigFile(configTarget(cmd))
+		if err != nil {
+			exitWithError(err)
+		}
+		printJSON(values)
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a setting in effect",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		key, sub := splitConfigKey(args[0])
+
+		data, err := json.Marshal(utils.GetConfig())
+		if err != nil {
+			exitWithError(err)
+		}
+		values := map[string]any{}
+		_ = json.Unmarshal(data, &values)
+
+		value := values[key]
+		if sub != "" {
+			entries, _ := value.(map[string]any)
+			value = entries[sub]
+		}
+		printJSON(value)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		key, sub := splitConfigKey(args[0])
+		file := configTarget(cmd)
+		values, err := utils.ReadConfigFile(file)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		if sub == "" {
+			value, err := utils.ParseConfigValue(key, args[1])
+			if err != nil {
+				exitWithError(err)
+			}
+			values[key] = value
+		} else {
+			var value any
+			if err := json.Unmarshal([]byte(args[1]), &value); err != nil {
+				value = args[1]
+			}
+			entries, _ := values[key].(map[string]any)
+			if entries == nil {
+				entries = map[string]any{}
+			}
+			entries[sub] = value
+			values[key] = entries
+		}
+
+		if err := utils.WriteConfigFile(file, values); err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("%s set in %s\n", highlightText.Render(args[0]), subtleText.Render(file))
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a setting so its default applies",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		key, sub := splitConfigKey(args[0])
+		file := configTarget(cmd)
+		values, err := utils.ReadConfigFile(file)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		if sub == "" {
+			delete(values, key)
+		} else if entries, ok := values[key].(map[string]any); ok {
+			delete(entries, sub)
+		}
+
+		if err := utils.WriteConfigFile(file, values); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a repo-level .qk.json in the current directory",
+	Long: `Writes the .qk.json qk init writes, suited to the projects under the
+current directory, without its suggestions of what to run.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		wd, err := os.Getwd()
+		if err != nil {
+			exitWithError(err)
+		}
+
+		file := path.Join(wd, utils.ConfigFile)
+		if force, _ := cmd.Flags().GetBool("force"); !force {
+			if _, err := os.Stat(file); err == nil {
+				exitWithError(fmt.Errorf("%s already exists, use --force to overwrite it", file))
+			}
+		}
+
+		if err := utils.WriteConfigFile(file, suggestStarter(cmd, wd).Config); err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("Created %s\n", highlightText.Render(file))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configListCmd, configGetCmd, configSetCmd, configUnsetCmd, configInitCmd)
+	configCmd.PersistentFlags().Bool("local", false, "use the repo-level .qk.json instead of ~/.qk.json")
+	configListCmd.Flags().Bool("global", false, "only print ~/.qk.json")
+	configInitCmd.Flags().Bool("force", false, "overwrite an existing .qk.json")
+}
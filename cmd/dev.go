@@ -28,6 +28,7 @@ func init() {
 	rootCmd.AddCommand(devCmd)
 
 	devCmd.Flags().BoolP("joined", "j", true, "Joined output")
+	devCmd.Flags().Bool("free-ports", false, "Offer to kill processes already listening on configured or last assigned ports")
 
 	// Here you will define your flags and configuration settings.
 
@@ -0,0 +1,105 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/utils"
+)
+
+func renderDocs(wd string, projects []utils.File) string {
+	conf := utils.GetConfig()
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Workspace overview\n\n")
+	fmt.Fprintf(&b, "%d projects found in `%s`.\n\n", len(projects), wd)
+
+	fmt.Fprintf(&b, "| Project | Type | Manager | Last run |\n")
+	fmt.Fprintf(&b, "| --- | --- | --- | --- |\n")
+	for _, project := range projects {
+		info := utils.GetProjectInfo(conf, project)
+		fmt.Fprintf(&b, "| [%s](#%s) | %s | %s | %s |\n", info.Name, strings.ToLower(info.Name), info.Type, info.PackageManager, lastRunHealth(project))
+	}
+
+	for _, project := range projects {
+		info := utils.GetProjectInfo(conf, project)
+		rel, err := filepath.Rel(wd, project.Dir)
+		if err != nil {
+			rel = project.Dir
+		}
+
+		fmt.Fprintf(&b, "\n## %s\n\n", info.Name)
+		fmt.Fprintf(&b, "- Path: `%s`\n", rel)
+		fmt.Fprintf(&b, "- Type: %s\n", info.Type)
+		if info.PackageManager != "" {
+			fmt.Fprintf(&b, "- Package manager: %s\n", info.PackageManager)
+		}
+		if len(info.Lockfiles) > 0 {
+			fmt.Fprintf(&b, "- Lockfiles: %s\n", strings.Join(info.Lockfiles, ", "))
+		}
+		fmt.Fprintf(&b, "- Last run: %s\n", lastRunHealth(project))
+
+		if deps := utils.LocalDependencies(project, projects); len(deps) > 0 {
+			fmt.Fprintf(&b, "- Depends on: %s\n", strings.Join(deps, ", "))
+		}
+
+		if len(info.Scripts) > 0 || len(info.ComposerScripts) > 0 {
+			fmt.Fprintf(&b, "\n| Script | Runs |\n| --- | --- |\n")
+			for _, name := range info.Scripts {
+				body, _ := utils.GetScript(project.FS, project.Dir, name)
+				fmt.Fprintf(&b, "| `%s` | `%s` |\n", name, strings.ReplaceAll(body, "|", "\\|"))
+			}
+			for _, name := range info.ComposerScripts {
+				body, _ := utils.GetComposerScript(project.FS, project.Dir, name)
+				fmt.Fprintf(&b, "| `composer %s` | `%s` |\n", name, strings.ReplaceAll(body, "|", "\\|"))
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func lastRunHealth(project utils.File) string {
+	result, ok := utils.LastRun(project.Dir)
+	if !ok {
+		return "never"
+	}
+
+	return fmt.Sprintf("%s (%s)", result.Status(), result.Time.Format("2006-01-02 15:04"))
+}
+
+// docsCmd represents the docs command
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generate a Markdown overview of the workspace",
+	Run: func(cmd *cobra.Command, args []string) {
+		wd, err := os.Getwd()
+		if err != nil {
+			panic(err)
+		}
+
+		out, _ := cmd.Flags().GetString("file")
+		docs := renderDocs(wd, listProjects(cmd, wd))
+
+		if out == "" {
+			fmt.Print(docs)
+			return
+		}
+
+		if err := os.WriteFile(out, []byte(docs), 0o644); err != nil {
+			fmt.Println(errorText.Render(fmt.Sprintf("Error: %s", err)))
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(docsCmd)
+	docsCmd.Flags().StringP("file", "f", "", "Write the overview to a file instead of stdout")
+}
@@ -0,0 +1,86 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/utils"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check every project's PHP against what its composer.json requires",
+	Long: `Compares require.php and config.platform.php in the composer.json of every
+project with the version of the php binary it runs, which is php on the PATH
+unless the Php config option selects another one. Exits with 1 when a
+project doesn't fit.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		wd, err := os.Getwd()
+		if err != nil {
+			panic(err)
+		}
+
+		conf := utils.GetConfig()
+
+		rows := [][]string{}
+		problems := 0
+		for _, project := range listProjects(cmd, wd) {
+			req, ok := utils.ReadPhpRequirement(project.FS, project.Dir)
+			if !ok {
+				continue
+			}
+
+			binary := utils.PhpBinary(conf, project.Name)
+			version, err := utils.PhpVersion(binary)
+			status := highlightText.Render("ok")
+			if err != nil {
+				version = ""
+				status = errorText.Render(fmt.Sprintf("Error: %s", err))
+				problems++
+			} else if reason := req.Mismatch(version); reason != "" {
+				status = errorText.Render(reason)
+				problems++
+			}
+
+			rows = append(rows, []string{project.Name, req.Constraint, req.Platform, binary, version, status})
+		}
+
+		if len(rows) == 0 {
+			fmt.Println(subtleText.Render("No project states a PHP requirement"))
+			return
+		}
+
+		t := table.New().
+			Border(lipgloss.NormalBorder()).
+			BorderStyle(lipgloss.NewStyle().Foreground(borderColor)).
+			StyleFunc(func(row, col int) lipgloss.Style {
+				switch {
+				case row == table.HeaderRow:
+					return headerStyle
+				case row%2 == 0:
+					return evenRowStyle
+				default:
+					return oddRowStyle
+				}
+			}).
+			Headers("Project", "Requires", "Platform", "Binary", "PHP", "").
+			Rows(rows...)
+
+		fmt.Println(t)
+		if problems > 0 {
+			fmt.Println(subtleText.Render(fmt.Sprintf("%d of %d projects don't fit their PHP, see the Php config option to pick a binary per project", problems, len(rows))))
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,40 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/utils"
+	"jrmd.dev/qk/views"
+)
+
+// gitCmd represents the git command
+var gitCmd = &cobra.Command{
+	Use:     "git",
+	Aliases: []string{"g"},
+	Short:   "run a git command across all project repositories",
+	Long: `This command runs your git command once in every repository containing
+a project, found by walking up to the nearest .git`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			fmt.Println("Provide a command...")
+			os.Exit(1)
+		}
+
+		m := views.CreateCommandRunner(runnerOptions(cmd))
+		m.
+			AddOptionalCommand(utils.FirstInRepo(), RenderCommand("git"), "git", args...).
+			Run()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(gitCmd)
+	gitCmd.Flags().BoolP("joined", "j", false, "Joined output")
+	// stop parsing qk flags at the git subcommand so its own flags pass through
+	gitCmd.Flags().SetInterspersed(false)
+}
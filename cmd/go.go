@@ -0,0 +1,40 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/utils"
+	"jrmd.dev/qk/views"
+)
+
+// goCmd represents the go command
+var goCmd = &cobra.Command{
+	Use:   "go <args...>",
+	Short: "run a go command in every go module",
+	Long: `This command runs your go command in every project with a go.mod, e.g.
+qk go mod tidy or qk go -- vet ./...
+Go modules are found whatever --detect says, other commands only find the
+ones without a package.json or composer.json with --detect any.`,
+	Annotations: map[string]string{markersAnnotation: "go.mod"},
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			fmt.Println("Provide a command...")
+			os.Exit(1)
+		}
+
+		m := views.CreateCommandRunner(runnerOptions(cmd))
+		m.
+			AddOptionalCommand(utils.HasGoMod, RenderCommand("go"), "go", args...).
+			Run()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(goCmd)
+	goCmd.Flags().BoolP("joined", "j", false, "Joined output")
+}
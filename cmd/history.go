@@ -0,0 +1,211 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/utils"
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List past runs, newest first",
+	Long: `Every run is recorded in ~/.qk/history.jsonl with the command, the
+directory it ran in and how each project's commands went. The last 1000 runs
+are kept. qk history show <id> lists the results of a single run.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("limit")
+		project, _ := cmd.Flags().GetString("project")
+		failed, _ := cmd.Flags().GetBool("failed")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		entries := []utils.HistoryEntry{}
+		history := utils.ReadHistory()
+		slices.Reverse(history)
+		for _, entry := range history {
+			status := entry.Status
+			if project != "" {
+				i := slices.IndexFunc(entry.Projects, func(p utils.HistoryProject) bool { return p.Project == project })
+				if i == -1 {
+					continue
+				}
+				status = entry.Projects[i].Status()
+			}
+			if failed && status != "failed" {
+				continue
+			}
+			if limit > 0 && len(entries) == limit {
+				break
+			}
+			entries = append(entries, entry)
+		}
+
+		if asJSON {
+			printJSON(entries)
+			return
+		}
+		if len(entries) == 0 {
+			fmt.Println(subtleText.Render("No recorded runs"))
+			return
+		}
+
+		rows := [][]string{}
+		for _, entry := range entries {
+			projects := fmt.Sprintf("%d", len(entry.Projects))
+			status := entry.Status
+			if project != "" {
+				// the project asked about matters more than the whole run
+				i := slices.IndexFunc(entry.Projects, func(p utils.HistoryProject) bool { return p.Project == project })
+				projects = project
+				status = entry.Projects[i].Status()
+			}
+			rows = append(rows, []string{
+				strconv.Itoa(entry.ID),
+				entry.Time.Format("2006-01-02 15:04"),
+				entry.Command,
+				shortenHome(entry.Dir),
+				projects,
+				renderHistoryStatus(status),
+				historyDuration(entry.Duration),
+			})
+		}
+
+		fmt.Println(historyTable([]string{"ID", "When", "Command", "Directory", "Projects", "Status", "Duration"}, rows))
+	},
+}
+
+var historyShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "List the results of every project in a past run",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil {
+			exitWithError(fmt.Errorf("run ids are numbers, got %q", args[0]))
+		}
+		entry, ok := utils.FindHistory(id)
+		if !ok {
+			exitWithError(fmt.Errorf("no recorded run %d, qk history lists them", id))
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			printJSON(entry)
+			return
+		}
+
+		fmt.Printf("%s %s\n", highlightText.Render(fmt.Sprintf("#%d", entry.ID)), entry.Command)
+		fmt.Println(subtleText.Render(fmt.Sprintf("%s in %s, %s after %s", entry.Time.Format("2006-01-02 15:04:05"), shortenHome(entry.Dir), entry.Status, historyDuration(entry.Duration))))
+		if entry.LogDir != "" {
+			fmt.Println(subtleText.Render("logs in " + entry.LogDir))
+		}
+
+		rows := [][]string{}
+		for _, project := range entry.Projects {
+			for _, command := range project.Commands {
+				rows = append(rows, []string{
+					project.Project,
+					command.Command,
+					renderHistoryStatus(command.Status),
+					strconv.Itoa(command.ExitCode),
+					historyDuration(command.Duration),
+				})
+			}
+		}
+		fmt.Println(historyTable([]string{"Project", "Command", "Status", "Exit", "Duration"}, rows))
+
+		if logs, _ := cmd.Flags().GetBool("logs"); !logs {
+			return
+		}
+		for _, project := range entry.Projects {
+			for _, command := range project.Commands {
+				if command.Status != "failed" || command.LogFile == "" {
+					continue
+				}
+
+				fmt.Printf("\n%s %s\n", highlightText.Render(project.Project), subtleText.Render(command.Command))
+				file, err := os.Open(command.LogFile)
+				if err != nil {
+					fmt.Println(errorText.Render("log file is gone, it may have been pruned"))
+					continue
+				}
+				_, _ = io.Copy(os.Stdout, file)
+				file.Close()
+			}
+		}
+	},
+}
+
+func renderHistoryStatus(status string) string {
+	switch status {
+	case "finished":
+		return lipgloss.NewStyle().Foreground(theme.Success).Render(status)
+	case "failed", "skipped":
+		return errorText.Render(status)
+	default:
+		return subtleText.Render(status)
+	}
+}
+
+func historyDuration(seconds float64) string {
+	d := time.Duration(seconds * float64(time.Second))
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// shortenHome writes dir relative to the home directory as ~/dir.
+func shortenHome(dir string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return dir
+	}
+	if rest, ok := strings.CutPrefix(dir, home); ok && (rest == "" || strings.HasPrefix(rest, "/")) {
+		return "~" + rest
+	}
+
+	return dir
+}
+
+func historyTable(headers []string, rows [][]string) *table.Table {
+	return table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(borderColor)).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			switch {
+			case row == table.HeaderRow:
+				return headerStyle
+			case row%2 == 0:
+				return evenRowStyle
+			default:
+				return oddRowStyle
+			}
+		}).
+		Headers(headers...).
+		Rows(rows...)
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historyShowCmd)
+
+	historyCmd.Flags().IntP("limit", "n", 20, "number of runs to list, 0 for all")
+	historyCmd.Flags().String("project", "", "only list runs involving this project, with its own status")
+	historyCmd.Flags().Bool("failed", false, "only list failed runs, or the ones in which --project failed")
+	historyCmd.Flags().Bool("json", false, "print the runs as JSON")
+	historyShowCmd.Flags().Bool("logs", false, "print the logs of the failed commands")
+	historyShowCmd.Flags().Bool("json", false, "print the run as JSON")
+}
@@ -0,0 +1,59 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/utils"
+)
+
+// inCmd represents the in command
+var inCmd = &cobra.Command{
+	Use:     "in <project> -- <command...>",
+	Aliases: []string{"exec"},
+	Short:   "run a command inside a single project",
+	Long: `This command resolves a project by (fuzzy) name and runs your command
+in its folder, attached directly to the terminal, e.g.
+
+  qk exec api -- composer test`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		wd, err := os.Getwd()
+		if err != nil {
+			panic(err)
+		}
+
+		project, err := utils.FindProject(listProjects(cmd, wd), args[0])
+		if err != nil {
+			fmt.Println(errorText.Render(fmt.Sprintf("Error: %s", err)))
+			os.Exit(1)
+		}
+
+		fmt.Println(subtleText.Render(fmt.Sprintf("→ %s", project.Dir)))
+
+		c := exec.Command(args[1], args[2:]...)
+		c.Dir = project.Dir
+		c.Stdin = os.Stdin
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+
+		if err := c.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.ExitCode())
+			}
+			fmt.Println(errorText.Render(fmt.Sprintf("Error: %s", err)))
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(inCmd)
+}
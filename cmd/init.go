@@ -0,0 +1,162 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/discovery"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a starter .qk.json for the projects under the current directory",
+	Long: `Looks for projects with any manifest under the current directory and writes
+a repo-level .qk.json suited to them: which manifests make a project, groups
+of projects sharing a top-level directory and defaults such as GracePeriod,
+the same qk config init writes.
+With --ignore it also writes a .qkignore skipping the directories, such as
+fixtures or build output, whose manifests aren't likely real projects.
+
+Prints the commands that apply to the projects found afterwards.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		wd, err := os.Getwd()
+		if err != nil {
+			panic(err)
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+		ignore, _ := cmd.Flags().GetBool("ignore")
+		configFile := path.Join(wd, utils.ConfigFile)
+		ignoreFile := path.Join(wd, utils.IgnoreFile)
+		for _, file := range []string{configFile, ignoreFile} {
+			if file == ignoreFile && !ignore {
+				continue
+			}
+			if exists, _ := utils.FileExists(file); exists && !force {
+				exitWithError(fmt.Errorf("%s already exists, --force overwrites it", file))
+			}
+		}
+
+		starter := suggestStarter(cmd, wd)
+
+		if err := utils.WriteConfigFile(configFile, starter.Config); err != nil {
+			exitWithError(err)
+		}
+		fmt.Println(subtleText.Render("Wrote " + configFile))
+
+		if ignore {
+			lines := []string{
+				"# Directories qk skips while looking for projects, like .gitignore",
+				"# e.g. fixtures/ or /packages/legacy",
+			}
+			lines = append(lines, starter.Ignore...)
+			if err := os.WriteFile(ignoreFile, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+				exitWithError(err)
+			}
+			fmt.Println(subtleText.Render("Wrote " + ignoreFile))
+		} else if len(starter.Ignore) > 0 {
+			fmt.Println(subtleText.Render(fmt.Sprintf("Some projects are under %s, qk init --ignore skips them", strings.Join(starter.Ignore, ", "))))
+		}
+
+		if len(starter.Projects) == 0 {
+			fmt.Println(subtleText.Render("No projects found yet"))
+			return
+		}
+
+		fmt.Printf("\nFound %d projects, try:\n", len(starter.Projects))
+		for _, task := range suggestTasks(starter.Projects) {
+			fmt.Printf("  %s %s\n", highlightText.Render(fmt.Sprintf("%-16s", task[0])), subtleText.Render(task[1]))
+		}
+	},
+}
+
+// suggestStarter looks at every kind of project under wd, not only the ones
+// the current settings detect, for qk init and qk config init.
+func suggestStarter(cmd *cobra.Command, wd string) utils.Starter {
+	utils.UseDiscoveryCache(false, "")
+	if err := discovery.SetMode(discovery.ModeAny); err != nil {
+		exitWithError(err)
+	}
+	depth, _ := cmd.Flags().GetInt("depth")
+
+	return utils.SuggestStarter(wd, utils.GetAllProjects(wd, depth, 0))
+}
+
+// suggestTasks lists the qk commands which apply to projects, with what
+// they would do.
+func suggestTasks(projects []utils.File) [][2]string {
+	count := func(matches func(types.Project) bool) int {
+		n := 0
+		for _, project := range projects {
+			if matches(types.Project{Name: project.Name, Dir: project.Dir, FS: project.FS, Workspace: project.Workspace}) {
+				n++
+			}
+		}
+		return n
+	}
+	inProjects := func(n int) string {
+		if n == 1 {
+			return "in 1 project"
+		}
+		return fmt.Sprintf("in %d projects", n)
+	}
+	configures := func(tools []utils.Tool) func(types.Project) bool {
+		return func(project types.Project) bool {
+			return slices.ContainsFunc(tools, func(tool utils.Tool) bool { return utils.ConfiguresTool(tool)(project) })
+		}
+	}
+
+	tasks := [][2]string{}
+	if n := count(utils.Or(utils.HasScript("start"), utils.HasScript("watch:dev"), utils.HasScript("dev"))); n > 0 {
+		tasks = append(tasks, [2]string{"qk watch", "runs start, watch:dev or dev " + inProjects(n)})
+	}
+	if n := count(utils.HasScript("build:prod")); n > 0 {
+		tasks = append(tasks, [2]string{"qk build", "runs build:prod " + inProjects(n)})
+	}
+	tasks = append(tasks, [2]string{"qk install", "installs node and composer dependencies " + inProjects(len(projects))})
+	if n := count(configures(utils.LINTERS)); n > 0 {
+		tasks = append(tasks, [2]string{"qk lint", "runs the configured linters " + inProjects(n)})
+	}
+	if n := count(configures(utils.FORMATTERS)); n > 0 {
+		tasks = append(tasks, [2]string{"qk format", "runs the configured formatters " + inProjects(n)})
+	}
+
+	// the most widely shared script not covered above
+	scripts := map[string]int{}
+	for _, project := range projects {
+		for _, script := range append(utils.GetScripts(project.FS, project.Dir), utils.GetComposerScripts(project.FS, project.Dir)...) {
+			scripts[script]++
+		}
+	}
+	common := ""
+	for script, n := range scripts {
+		if slices.Contains([]string{"start", "watch:dev", "dev", "build:prod"}, script) {
+			continue
+		}
+		if n > scripts[common] || n == scripts[common] && script < common {
+			common = script
+		}
+	}
+	if common != "" {
+		tasks = append(tasks, [2]string{"qk run " + common, "runs the " + common + " script " + inProjects(scripts[common])})
+	}
+
+	return tasks
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+
+	initCmd.Flags().Bool("ignore", false, "also write a .qkignore")
+	initCmd.Flags().BoolP("force", "f", false, "overwrite existing files")
+}
@@ -4,60 +4,33 @@ Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
+	"jrmd.dev/qk/tooling"
 	"jrmd.dev/qk/types"
-	"jrmd.dev/qk/utils"
 	"jrmd.dev/qk/views"
 )
 
 var (
-	subtle    = lipgloss.AdaptiveColor{Light: "#969B86", Dark: "#696969"}
-	highlight = lipgloss.AdaptiveColor{Light: "#dc8a78", Dark: "#dc8a78"}
-	special   = lipgloss.AdaptiveColor{Light: "#43BF6D", Dark: "#73F59F"}
-	errColor  = lipgloss.AdaptiveColor{Light: "#FF5555", Dark: "#FF5555"}
+	theme = types.DefaultTheme()
 
-	subtleText    = lipgloss.NewStyle().Foreground(subtle)
-	highlightText = lipgloss.NewStyle().Foreground(highlight)
-	successText   = lipgloss.NewStyle().Foreground(special)
-	errorText     = lipgloss.NewStyle().Foreground(errColor)
+	subtleText    = lipgloss.NewStyle().Foreground(theme.Subtle)
+	highlightText = lipgloss.NewStyle().Foreground(theme.Highlight)
+	errorText     = lipgloss.NewStyle().Foreground(theme.Error)
 )
 
-func RenderCommand(name string) func(*types.Command, bool) string {
-	return func(c *types.Command, showStatus bool) string {
-		if !showStatus {
-			return highlightText.Render(name)
-		}
-
-		stat := c.Status
-		status := stat
-		switch stat {
-		case "finished":
-			status = successText.Render(stat)
-		case "failed":
-			status = errorText.Render(stat)
-		}
-
-		return fmt.Sprintf("%s %s", highlightText.Render(name), status)
-	}
-}
-
 // installCmd represents the install command
 var installCmd = &cobra.Command{
 	Use:     "install",
 	Aliases: []string{"i"},
-	Short:   "runs yarn and composer install across all projects",
+	Short:   "runs bun, yarn or npm and composer install, and cargo fetch, across all projects",
 	Run: func(cmd *cobra.Command, args []string) {
-		depth, _ := cmd.Flags().GetInt("depth");
-		joined, _ := cmd.Flags().GetBool("joined");
-
-		m := views.CreateCommandRunner(depth, joined)
+		m := views.CreateCommandRunner(runnerOptions(cmd))
 		m.
-			AddOptionalCommand(utils.HasYarn, RenderCommand("yarn"), "yarn").
-			AddOptionalCommand(utils.Not(utils.HasYarn), RenderCommand("npm"), "npm", "install").
-			AddCommand(RenderCommand("composer"), "composer", "install").
+			TrackLockfiles().
+			AddPhase(tooling.Install, RenderCommand).
+			AddHooks("install", RenderCommand).
+			CheckPhp().
 			Run()
 	},
 }
@@ -0,0 +1,39 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// killCmd represents the kill command
+var killCmd = &cobra.Command{
+	Use:   "kill <project...>",
+	Short: "Stop the processes qk runs in a project, whoever started them",
+	Long: `Stops every process listed by qk ps for the given projects, including
+ones left behind by a runner which was killed. They get --grace to shut down
+before they are killed.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		processes, err := findProcesses(args)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		grace, _ := cmd.Flags().GetDuration("grace")
+		for _, p := range processes {
+			if err := p.Stop(grace); err != nil {
+				fmt.Println(errorText.Render(fmt.Sprintf("Error: could not stop %s in %s: %s", p.Command, p.Project, err)))
+				continue
+			}
+			fmt.Printf("Stopped %s in %s\n", p.Command, p.Project)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(killCmd)
+}
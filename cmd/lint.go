@@ -0,0 +1,53 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/utils"
+	"jrmd.dev/qk/views"
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Run eslint and phpcs in the projects which configure them",
+	Long: `Runs eslint in the projects with an eslint config, or an eslintConfig key in
+package.json, through their package manager, and phpcs in the projects with a
+phpcs.xml, from vendor/bin when composer installed it. --fix fixes what the
+tools can, with phpcbf in place of phpcs.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fix, _ := cmd.Flags().GetBool("fix")
+		m := views.CreateCommandRunner(runnerOptions(cmd))
+		m.
+			AddTools(utils.LINTERS, fix, RenderCommand).
+			Run()
+	},
+}
+
+// formatCmd represents the format command
+var formatCmd = &cobra.Command{
+	Use:     "format",
+	Aliases: []string{"fmt"},
+	Short:   "Check formatting with prettier, php-cs-fixer and pint where configured",
+	Long: `Checks the formatting of the projects which configure prettier, php-cs-fixer
+or pint, the way lint runs linters. --fix rewrites the files instead.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fix, _ := cmd.Flags().GetBool("fix")
+		m := views.CreateCommandRunner(runnerOptions(cmd))
+		m.
+			AddTools(utils.FORMATTERS, fix, RenderCommand).
+			Run()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().BoolP("joined", "j", false, "Joined output")
+	lintCmd.Flags().Bool("fix", false, "Fix the problems the linters can fix")
+
+	rootCmd.AddCommand(formatCmd)
+	formatCmd.Flags().BoolP("joined", "j", false, "Joined output")
+	formatCmd.Flags().Bool("fix", false, "Rewrite files instead of only checking them")
+}
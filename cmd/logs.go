@@ -0,0 +1,62 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// logsCmd represents the logs command
+var logsCmd = &cobra.Command{
+	Use:   "logs <project>",
+	Short: "Print the output of a project's process listed by qk ps",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		processes, err := findProcesses(args)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		logFile := ""
+		for _, p := range processes {
+			if p.LogFile != "" {
+				logFile = p.LogFile
+				break
+			}
+		}
+		if logFile == "" {
+			exitWithError(fmt.Errorf("%s has no logged process", args[0]))
+		}
+
+		file, err := os.Open(logFile)
+		if err != nil {
+			exitWithError(err)
+		}
+		defer file.Close()
+
+		if _, err := io.Copy(os.Stdout, file); err != nil {
+			exitWithError(err)
+		}
+
+		// keep printing what is appended until interrupted
+		if follow, _ := cmd.Flags().GetBool("follow"); follow {
+			for {
+				time.Sleep(250 * time.Millisecond)
+				if _, err := io.Copy(os.Stdout, file); err != nil {
+					exitWithError(err)
+				}
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+	logsCmd.Flags().BoolP("follow", "f", false, "Keep printing new output")
+}
@@ -4,8 +4,11 @@ Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
@@ -14,14 +17,14 @@ import (
 )
 
 var (
-	purple    = lipgloss.Color("99")
-	gray      = lipgloss.Color("245")
-	lightGray = lipgloss.Color("241")
+	borderColor = theme.Border
 
-	headerStyle  = lipgloss.NewStyle().Foreground(purple).Bold(true).Align(lipgloss.Center)
+	headerStyle  = lipgloss.NewStyle().Foreground(borderColor).Bold(true).Align(lipgloss.Center)
 	cellStyle    = lipgloss.NewStyle().Padding(0, 1)
-	oddRowStyle  = cellStyle.Foreground(gray)
-	evenRowStyle = cellStyle.Foreground(lightGray)
+	oddRowStyle  = cellStyle.Foreground(theme.Row)
+	evenRowStyle = cellStyle.Foreground(theme.AltRow)
+	// skippedRowStyle greys out projects in the skip list
+	skippedRowStyle = cellStyle.Foreground(theme.Subtle)
 )
 
 // lsCmd represents the ls command
@@ -34,34 +37,142 @@ var lsCmd = &cobra.Command{
 		if err != nil {
 			panic(err)
 		}
-		depth, _ := cmd.Flags().GetInt("depth");
-		projects := utils.GetAllProjects(wd, depth, 0)
-		rows := [][]string{}
+		asJSON, _ := cmd.Flags().GetBool("json")
+		conf := utils.GetConfig()
+		projects := listProjects(cmd, wd)
+
+		infos := []utils.ProjectInfo{}
 		for _, project := range projects {
-			rows = append(rows, []string{project.Name})
+			infos = append(infos, utils.GetProjectInfo(conf, project))
+		}
+
+		if asJSON {
+			out, err := json.MarshalIndent(infos, "", "  ")
+			if err != nil {
+				panic(err)
+			}
+			fmt.Println(string(out))
+			return
+		}
+
+		fold, _ := cmd.Flags().GetBool("fold")
+		groups := []string{}
+		for _, info := range infos {
+			if !slices.Contains(groups, info.Group) {
+				groups = append(groups, info.Group)
+			}
+		}
+
+		if len(groups) == 1 && groups[0] == "" {
+			fmt.Println(projectTable(infos))
+			return
 		}
-		t := table.New().
-			Border(lipgloss.NormalBorder()).
-			BorderStyle(lipgloss.NewStyle().Foreground(purple)).
-			StyleFunc(func(row, col int) lipgloss.Style {
-				switch {
-				case row == table.HeaderRow:
-					return headerStyle
-				case row%2 == 0:
-					return evenRowStyle
-				default:
-					return oddRowStyle
+
+		for _, group := range groups {
+			members := []utils.ProjectInfo{}
+			for _, info := range infos {
+				if info.Group == group {
+					members = append(members, info)
 				}
-			}).
-			Headers("Targets").
-			Rows(rows...)
+			}
 
-		fmt.Println(t)
+			fmt.Println(groupHeader(group, members))
+			if !fold {
+				fmt.Println(projectTable(members))
+			}
+		}
 	},
 }
 
+// projectTable renders one row per project.
+func projectTable(infos []utils.ProjectInfo) *table.Table {
+	rows := [][]string{}
+	for _, info := range infos {
+		lockfiles := strings.Join(info.Lockfiles, ", ")
+		if lockfiles == "" && info.Workspace != "" && info.Workspace != info.Dir {
+			lockfiles = "workspace"
+		} else if lockfiles == "" {
+			lockfiles = "missing"
+		}
+		branch := ""
+		if info.Git != nil {
+			branch = info.Git.String()
+		}
+		scripts := slices.Clone(info.Scripts)
+		for _, name := range info.ComposerScripts {
+			scripts = append(scripts, "composer "+name)
+		}
+		for _, name := range info.Tasks {
+			scripts = append(scripts, "task "+name)
+		}
+		for _, name := range info.MakeTargets {
+			scripts = append(scripts, "make "+name)
+		}
+		name := info.Name
+		if info.Skipped {
+			name += " (skipped)"
+		}
+		rows = append(rows, []string{
+			name,
+			info.Type,
+			info.PackageManager,
+			branch,
+			strings.Join(scripts, ", "),
+			lockfiles,
+		})
+	}
+
+	return table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(borderColor)).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			switch {
+			case row == table.HeaderRow:
+				return headerStyle
+			case infos[row].Skipped:
+				return skippedRowStyle
+			case row%2 == 0:
+				return evenRowStyle
+			default:
+				return oddRowStyle
+			}
+		}).
+		Headers("Targets", "Type", "Manager", "Branch", "Scripts", "Lockfiles").
+		Rows(rows...)
+}
+
+// groupHeader titles a group of projects with a rollup of their last runs.
+func groupHeader(group string, members []utils.ProjectInfo) string {
+	name := group
+	if name == "" {
+		name = "ungrouped"
+	}
+
+	failed, ran := 0, 0
+	for _, info := range members {
+		if result, ok := utils.LastRun(info.Dir); ok {
+			ran++
+			if result.Status() != "finished" {
+				failed++
+			}
+		}
+	}
+
+	rollup := fmt.Sprintf("%d projects", len(members))
+	switch {
+	case failed > 0:
+		rollup += " • " + errorText.Render(fmt.Sprintf("%d failed", failed))
+	case ran == len(members):
+		rollup += " • " + lipgloss.NewStyle().Foreground(theme.Success).Render("all green")
+	}
+
+	return fmt.Sprintf("%s %s", headerStyle.Render(name), subtleText.Render(rollup))
+}
+
 func init() {
 	rootCmd.AddCommand(lsCmd)
+	lsCmd.Flags().Bool("json", false, "Print the project list as JSON")
+	lsCmd.Flags().Bool("fold", false, "Only print a summary line per project group")
 
 	// Here you will define your flags and configuration settings.
 
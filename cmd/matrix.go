@@ -0,0 +1,53 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+	"jrmd.dev/qk/views"
+)
+
+// matrixCmd represents the matrix command
+var matrixCmd = &cobra.Command{
+	Use:   "matrix --axis KEY=a,b -- <command>",
+	Short: "Run a command once per combination of environment values",
+	Long: `Runs the command in every project once per combination of the --axis
+values and ends with a grid of the results, e.g.
+
+  qk matrix --axis NODE_ENV=dev,production -- yarn build
+
+Several --axis flags multiply: two values each make four runs per project.
+The runs of a project go one at a time as they share its directory, the
+root --env still sets variables for all of them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			fmt.Println("Provide a command...")
+			os.Exit(1)
+		}
+
+		pairs, _ := cmd.Flags().GetStringArray("axis")
+		combinations, err := utils.MatrixCombinations(pairs)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		m := views.CreateCommandRunner(runnerOptions(cmd))
+		m.
+			AddMatrixCommand(combinations, func(label string) types.CommandRenderer {
+				return RenderCommand(fmt.Sprintf("%s [%s]", args[0], label))
+			}, args[0], args[1:]...).
+			Run()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(matrixCmd)
+	matrixCmd.Flags().BoolP("joined", "j", false, "Joined output")
+	matrixCmd.Flags().StringArray("axis", []string{}, "matrix values as KEY=VALUE,VALUE (repeatable)")
+}
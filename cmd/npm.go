@@ -22,9 +22,7 @@ var npmCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		depth, _ := cmd.Flags().GetInt("depth");
-		joined, _ := cmd.Flags().GetBool("joined");
-		m := views.CreateCommandRunner(depth, joined)
+		m := views.CreateCommandRunner(runnerOptions(cmd))
 		m.
 			AddCommand(RenderCommand("npm"), "npm", args...).
 			Run()
@@ -0,0 +1,140 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/utils"
+	"jrmd.dev/qk/views"
+)
+
+// defaultBranchRef is what --changed holds when given without a ref, it
+// compares with the default branch of each repository.
+const defaultBranchRef = "default-branch"
+
+// runnerOptions collects the flags shared by every run-style command.
+func runnerOptions(cmd *cobra.Command) views.Options {
+	depth, _ := cmd.Flags().GetInt("depth")
+	joined, _ := cmd.Flags().GetBool("joined")
+	output, _ := cmd.Flags().GetString("output")
+	only, _ := cmd.Flags().GetStringSlice("only")
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	last, _ := cmd.Flags().GetBool("last")
+	pick, _ := cmd.Flags().GetBool("pick")
+	hold, _ := cmd.Flags().GetBool("hold")
+	sharedCache, _ := cmd.Flags().GetBool("shared-cache")
+	conf := utils.GetConfig()
+	grace, _ := cmd.Flags().GetDuration("grace")
+	if !cmd.Flags().Changed("grace") && conf.GracePeriod != "" {
+		if configured, err := time.ParseDuration(conf.GracePeriod); err == nil {
+			grace = configured
+		}
+	}
+
+	env, _ := cmd.Flags().GetStringArray("env")
+	for _, pair := range env {
+		if !strings.Contains(pair, "=") || strings.HasPrefix(pair, "=") {
+			fmt.Printf("Invalid --env %q, expected KEY=VALUE\n", pair)
+			os.Exit(1)
+		}
+	}
+
+	dotenv, _ := cmd.Flags().GetBool("dotenv")
+	tuned, _ := cmd.Flags().GetBool("tuned")
+	triage, _ := cmd.Flags().GetBool("triage")
+	interactive, _ := cmd.Flags().GetBool("interactive")
+	usePty, _ := cmd.Flags().GetBool("pty")
+	events, _ := cmd.Flags().GetString("events")
+	failureLines, _ := cmd.Flags().GetInt("failure-lines")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	inContainer, _ := cmd.Flags().GetString("in-container")
+	changedSince, _ := cmd.Flags().GetString("changed")
+	if changedSince == defaultBranchRef {
+		changedSince = ""
+	}
+
+	maxRefresh := time.Second
+	if conf.MaxRefresh != "" {
+		if configured, err := time.ParseDuration(conf.MaxRefresh); err == nil {
+			maxRefresh = configured
+		}
+	}
+
+	reports := map[string]string{}
+	flags, _ := cmd.Flags().GetStringArray("report")
+	for _, flag := range flags {
+		format, file, ok := strings.Cut(flag, "=")
+		if !ok || file == "" || !slices.Contains(views.REPORT_FORMATS, format) {
+			fmt.Printf("Invalid --report %q, expected FORMAT=PATH with FORMAT one of %s\n", flag, strings.Join(views.REPORT_FORMATS, ", "))
+			os.Exit(1)
+		}
+		reports[format] = file
+	}
+
+	if output != views.OutputText && output != views.OutputJSON && output != views.OutputGitHub {
+		fmt.Printf("Unknown output format %q, expected text, json or github\n", output)
+		os.Exit(1)
+	}
+	if events == "-" && output == views.OutputText {
+		fmt.Println("--events - needs --output json or github, the TUI draws on stdout")
+		os.Exit(1)
+	}
+
+	return views.Options{
+		Depth:        depth,
+		Joined:       joined,
+		Output:       output,
+		Only:         only,
+		Tags:         tags,
+		Last:         last,
+		Pick:         pick,
+		Hold:         hold,
+		SharedCache:  sharedCache,
+		Grace:        grace,
+		MaxRefresh:   maxRefresh,
+		Env:          env,
+		Dotenv:       dotenv,
+		Tuned:        tuned,
+		Triage:       triage,
+		Reports:      reports,
+		Interactive:  interactive,
+		Pty:          usePty,
+		Events:       events,
+		Changed:      cmd.Flags().Changed("changed"),
+		ChangedSince: changedSince,
+		FailureLines: failureLines,
+		Concurrency:  concurrency,
+		InContainer:  inContainer,
+	}
+}
+
+// listProjects discovers the projects below wd for commands working on them
+// without the runner, such as qk ls, applying --depth, --only and --tag the
+// way the runner does.
+func listProjects(cmd *cobra.Command, wd string) []utils.File {
+	depth, _ := cmd.Flags().GetInt("depth")
+	projects := utils.GetAllProjects(wd, depth, 0)
+
+	if only, _ := cmd.Flags().GetStringSlice("only"); len(only) > 0 {
+		projects = utils.FilterProjects(projects, only)
+	}
+
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	if len(tags) == 0 {
+		return projects
+	}
+
+	tagged, err := utils.FilterTagged(utils.GetConfig(), projects, tags)
+	if err != nil {
+		exitWithError(fmt.Errorf("--tag: %w", err))
+	}
+
+	return tagged
+}
@@ -0,0 +1,116 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+type outdatedReport struct {
+	Project  string                  `json:"project"`
+	Dir      string                  `json:"dir"`
+	Packages []utils.OutdatedPackage `json:"packages"`
+	Error    string                  `json:"error,omitempty"`
+}
+
+// outdatedCmd represents the outdated command
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "List outdated node and composer dependencies across all projects",
+	Long: `Runs npm, pnpm or yarn outdated and composer outdated --direct in every
+project at once and prints one table of the packages needing updates, grouped
+by project. Wanted is the newest version the declared constraint allows.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		wd, err := os.Getwd()
+		if err != nil {
+			panic(err)
+		}
+
+		asJSON, _ := cmd.Flags().GetBool("json")
+		projects := listProjects(cmd, wd)
+
+		reports := make([]outdatedReport, len(projects))
+		var wg sync.WaitGroup
+		for i, project := range projects {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				manager := utils.ProjectManager(types.Project{Dir: project.Dir, FS: project.FS, Workspace: project.Workspace})
+				packages, err := utils.Outdated(project.Dir, manager)
+				reports[i] = outdatedReport{Project: project.Name, Dir: project.Dir, Packages: packages}
+				if err != nil {
+					reports[i].Error = err.Error()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if asJSON {
+			out, err := json.MarshalIndent(reports, "", "  ")
+			if err != nil {
+				panic(err)
+			}
+			fmt.Println(string(out))
+			return
+		}
+
+		rows := [][]string{}
+		outdated := 0
+		for _, report := range reports {
+			name := report.Project
+			if report.Error != "" {
+				rows = append(rows, []string{name, errorText.Render(fmt.Sprintf("Error: %s", report.Error)), "", "", "", ""})
+				name = ""
+			}
+
+			for _, pkg := range report.Packages {
+				outdated++
+				latest := pkg.Latest
+				if pkg.Wanted != pkg.Latest {
+					latest = errorText.Render(pkg.Latest)
+				}
+				rows = append(rows, []string{name, pkg.Name, pkg.Manager, pkg.Current, highlightText.Render(pkg.Wanted), latest})
+				name = ""
+			}
+		}
+
+		if len(rows) == 0 {
+			fmt.Println(subtleText.Render(fmt.Sprintf("All dependencies are up to date across %d projects", len(projects))))
+			return
+		}
+
+		t := table.New().
+			Border(lipgloss.NormalBorder()).
+			BorderStyle(lipgloss.NewStyle().Foreground(borderColor)).
+			StyleFunc(func(row, col int) lipgloss.Style {
+				switch {
+				case row == table.HeaderRow:
+					return headerStyle
+				case row%2 == 0:
+					return evenRowStyle
+				default:
+					return oddRowStyle
+				}
+			}).
+			Headers("Project", "Package", "Manager", "Current", "Wanted", "Latest").
+			Rows(rows...)
+
+		fmt.Println(t)
+		fmt.Println(subtleText.Render(fmt.Sprintf("%d outdated packages across %d projects", outdated, len(projects))))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(outdatedCmd)
+	outdatedCmd.Flags().Bool("json", false, "Print the outdated packages as JSON")
+}
@@ -0,0 +1,99 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/pipeline"
+	"jrmd.dev/qk/views"
+)
+
+// loadPipelines loads the nearest .qk.star, exiting when there is none or
+// it fails.
+func loadPipelines() *pipeline.Script {
+	file := pipeline.Path()
+	if file == "" {
+		fmt.Println(errorText.Render(fmt.Sprintf("Error: no %s found here or above", pipeline.FILE)))
+		os.Exit(1)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	script, err := pipeline.Load(file, wd)
+	if err != nil {
+		fmt.Println(errorText.Render(fmt.Sprintf("Error: %s", err)))
+		os.Exit(1)
+	}
+
+	return script
+}
+
+// pipelineCmd represents the pipeline command
+var pipelineCmd = &cobra.Command{
+	Use:     "pipeline [name] [args...]",
+	Aliases: []string{"pl"},
+	Short:   "Run a pipeline defined in .qk.star",
+	Long: `Runs a pipeline of the nearest .qk.star, a Starlark script whose top
+level functions are pipelines. Each is called with every project and returns
+the commands to run in it, as shell snippets or lists of arguments, or none
+to leave the project out. A second parameter receives the extra arguments:
+
+  def migrate(project, args):
+      """Run doctrine migrations where doctrine is installed."""
+      composer = project.read("composer.json")
+      if not composer or "doctrine/orm" not in json.decode(composer).get("require", {}):
+          return []
+      return [["php", "bin/console", "doctrine:migrations:migrate", "-n"] + args]
+
+Projects have name, dir, path, manager, type, group, scripts and
+composer_scripts, and read(file), exists(file) and has_script(name). Without
+a name the pipelines are listed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		script := loadPipelines()
+		if len(args) == 0 {
+			for _, name := range script.Names() {
+				fmt.Printf("%s %s\n", highlightText.Render(name), subtleText.Render(script.Doc(name)))
+			}
+			return
+		}
+		if !slices.Contains(script.Names(), args[0]) {
+			fmt.Println(errorText.Render(fmt.Sprintf("Error: %s defines no pipeline %s", pipeline.Path(), args[0])))
+			os.Exit(1)
+		}
+
+		m := views.CreateCommandRunner(runnerOptions(cmd))
+		m.
+			AddPipeline(script, args[0], RenderCommand(args[0]), args[1:]...).
+			Run()
+	},
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		file := pipeline.Path()
+		if len(args) > 0 || file == "" {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+
+		script, err := pipeline.Load(file, "")
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		names := []string{}
+		for _, name := range script.Names() {
+			names = append(names, name+"\t"+script.Doc(name))
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pipelineCmd)
+	pipelineCmd.Flags().BoolP("joined", "j", false, "Joined output")
+}
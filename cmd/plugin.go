@@ -0,0 +1,145 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"jrmd.dev/qk/utils"
+	"jrmd.dev/qk/version"
+)
+
+// reservedCommands are added by cobra and fang once the root command runs,
+// so they can't be found before.
+var reservedCommands = []string{"help", "completion", "man"}
+
+// addPlugin adds a subcommand running the qk-<name> executable on the PATH
+// when args call a command qk doesn't have. Only then the PATH is searched,
+// and only for that name.
+func addPlugin(root *cobra.Command, args []string) {
+	i, ok := commandIndex(root, args)
+	if !ok {
+		return
+	}
+
+	name := args[i]
+	if slices.Contains(reservedCommands, name) || strings.HasPrefix(name, "__") {
+		return
+	}
+	if existing, _, err := root.Find([]string{name}); err == nil && existing != root {
+		return
+	}
+
+	if plugin, ok := utils.FindPlugin(name); ok {
+		root.AddCommand(pluginCommand(plugin, i))
+	}
+}
+
+// commandIndex finds the command name in args, skipping the root's
+// persistent flags and their values before it. ok is false without one, or
+// when an unknown flag comes first.
+func commandIndex(root *cobra.Command, args []string) (int, bool) {
+	flags := root.PersistentFlags()
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var flag *pflag.Flag
+		inline := false
+		switch {
+		case arg == "--" || arg == "-":
+			return 0, false
+		case strings.HasPrefix(arg, "--"):
+			name, _, hasValue := strings.Cut(arg[2:], "=")
+			flag, inline = flags.Lookup(name), hasValue
+		case strings.HasPrefix(arg, "-"):
+			flag, inline = flags.ShorthandLookup(arg[1:2]), len(arg) > 2
+		default:
+			return i, true
+		}
+
+		if flag == nil {
+			return 0, false
+		}
+		if !inline && flag.NoOptDefVal == "" {
+			i++
+		}
+	}
+
+	return 0, false
+}
+
+// pluginCommand runs plugin, with qk's own flags in the first flagArgs
+// arguments, which come before the plugin's name.
+func pluginCommand(plugin utils.Plugin, flagArgs int) *cobra.Command {
+	return &cobra.Command{
+		Use:   plugin.Name + " [args...]",
+		Short: fmt.Sprintf("Plugin %s", plugin.Path),
+		Long: fmt.Sprintf(`Runs the plugin %s with the given arguments, attached to the
+terminal. The projects qk discovers are written to its stdin as the JSON of
+qk ls --json, their directories are in QK_PROJECTS separated like the PATH,
+and QK_ROOT, QK_DEPTH, QK_BIN and QK_VERSION describe the qk running it.
+qk's own flags go before the plugin's name, as in qk --tag apis %s.`, plugin.Path, plugin.Name),
+		// every argument after the name, --help included, is the plugin's
+		DisableFlagParsing: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := cmd.InheritedFlags().Parse(args[:flagArgs]); err != nil {
+				return err
+			}
+
+			return cmd.Root().PersistentPreRunE(cmd, args[flagArgs:])
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			args = args[flagArgs:]
+			wd, err := os.Getwd()
+			if err != nil {
+				panic(err)
+			}
+
+			depth, _ := cmd.Flags().GetInt("depth")
+			conf := utils.GetConfig()
+			infos := []utils.ProjectInfo{}
+			dirs := []string{}
+			for _, project := range listProjects(cmd, wd) {
+				infos = append(infos, utils.GetProjectInfo(conf, project))
+				dirs = append(dirs, project.Dir)
+			}
+			projects, err := json.Marshal(infos)
+			if err != nil {
+				panic(err)
+			}
+
+			bin, _ := os.Executable()
+			c := exec.Command(plugin.Path, args...)
+			c.Env = append(os.Environ(),
+				"QK_ROOT="+wd,
+				"QK_DEPTH="+strconv.Itoa(depth),
+				"QK_BIN="+bin,
+				"QK_VERSION="+version.Get().Version,
+				"QK_PROJECTS="+strings.Join(dirs, string(filepath.ListSeparator)),
+			)
+			c.Stdin = bytes.NewReader(projects)
+			c.Stdout = os.Stdout
+			c.Stderr = os.Stderr
+
+			if err := c.Run(); err != nil {
+				var exitErr *exec.ExitError
+				if errors.As(err, &exitErr) {
+					os.Exit(exitErr.ExitCode())
+				}
+				fmt.Println(errorText.Render(fmt.Sprintf("Error: %s", err)))
+				os.Exit(1)
+			}
+		},
+	}
+}
@@ -0,0 +1,31 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import "testing"
+
+func TestCommandIndex(t *testing.T) {
+	tests := []struct {
+		args  []string
+		index int
+		ok    bool
+	}{
+		{[]string{"deploy", "staging"}, 0, true},
+		{[]string{"--depth", "2", "deploy", "--depth", "9"}, 2, true},
+		{[]string{"--depth=2", "deploy"}, 1, true},
+		{[]string{"--only", "a,b", "-q", "deploy"}, 3, true},
+		{[]string{"--changed", "deploy"}, 1, true},
+		{[]string{"--no-such-flag", "deploy"}, 0, false},
+		{[]string{"--", "deploy"}, 0, false},
+		{[]string{"--depth", "2"}, 0, false},
+		{[]string{}, 0, false},
+	}
+
+	for _, test := range tests {
+		index, ok := commandIndex(rootCmd, test.args)
+		if index != test.index || ok != test.ok {
+			t.Errorf("commandIndex(%q) = %d, %v, want %d, %v", test.args, index, ok, test.index, test.ok)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/utils"
+)
+
+// psCmd represents the ps command
+var psCmd = &cobra.Command{
+	Use:   "ps",
+	Short: "List the processes qk started which are still running",
+	Long: `Lists the commands started by qk, the ones running detached after
+qk watch --detach as well as the ones of runners which are open, or were
+killed without stopping them (orphaned). Stop them with qk kill or qk stop.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		processes, err := runningProcesses()
+		if err != nil {
+			exitWithError(err)
+		}
+
+		if len(processes) == 0 {
+			fmt.Println(subtleText.Render("qk is not running anything"))
+			return
+		}
+
+		memory := utils.GroupMemory()
+		rows := [][]string{}
+		for _, p := range processes {
+			owner := fmt.Sprintf("qk %d", p.Owner)
+			switch {
+			case p.Detached():
+				owner = "detached"
+			case p.Orphaned():
+				owner = errorText.Render("orphaned")
+			}
+
+			status := "running"
+			if !p.Running() {
+				status = errorText.Render("exited")
+			}
+
+			rows = append(rows, []string{p.Project, p.Command, strconv.Itoa(p.Pid), status, owner, time.Since(p.Started).Round(time.Second).String(), formatMemory(memory[p.Pid])})
+		}
+
+		t := table.New().
+			Border(lipgloss.NormalBorder()).
+			BorderStyle(lipgloss.NewStyle().Foreground(borderColor)).
+			StyleFunc(func(row, col int) lipgloss.Style {
+				switch {
+				case row == table.HeaderRow:
+					return headerStyle
+				case row%2 == 0:
+					return evenRowStyle
+				default:
+					return oddRowStyle
+				}
+			}).
+			Headers("Project", "Command", "Pid", "Status", "Started by", "Up", "Memory").
+			Rows(rows...)
+
+		fmt.Println(t)
+	},
+}
+
+// runningProcesses lists the recorded processes, forgetting the ones whose
+// runner cleaned up after them no more. Detached processes which exited
+// are kept so their logs can still be found.
+func runningProcesses() ([]utils.Process, error) {
+	processes, err := utils.Processes()
+	if err != nil {
+		return nil, err
+	}
+
+	kept := []utils.Process{}
+	for _, p := range processes {
+		if !p.Detached() && !p.Running() {
+			_ = p.Forget()
+			continue
+		}
+		kept = append(kept, p)
+	}
+
+	return kept, nil
+}
+
+// formatMemory renders KiB as a short human readable size.
+func formatMemory(kib int) string {
+	switch {
+	case kib == 0:
+		return "-"
+	case kib < 1024:
+		return fmt.Sprintf("%dK", kib)
+	case kib < 1024*1024:
+		return fmt.Sprintf("%.1fM", float64(kib)/1024)
+	default:
+		return fmt.Sprintf("%.1fG", float64(kib)/1024/1024)
+	}
+}
+
+// findProcesses resolves project names, fuzzy matched like qk in, to the
+// recorded processes. Without names every process is returned.
+func findProcesses(names []string) ([]utils.Process, error) {
+	processes, err := runningProcesses()
+	if err != nil || len(names) == 0 {
+		return processes, err
+	}
+
+	files := []utils.File{}
+	for _, p := range processes {
+		if !slices.ContainsFunc(files, func(file utils.File) bool { return file.Dir == p.Dir }) {
+			files = append(files, utils.File{Name: p.Project, Dir: p.Dir})
+		}
+	}
+
+	found := []utils.Process{}
+	for _, name := range names {
+		file, err := utils.FindProject(files, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range processes {
+			if p.Dir == file.Dir {
+				found = append(found, p)
+			}
+		}
+	}
+
+	return found, nil
+}
+
+func init() {
+	rootCmd.AddCommand(psCmd)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"jrmd.dev/qk/types"
+)
+
+type commandRenderer struct {
+	name  string
+	theme types.Theme
+}
+
+// RenderCommand returns a renderer which labels a command with name and
+// colours its status using the configured theme.
+func RenderCommand(name string) types.CommandRenderer {
+	return commandRenderer{name: name, theme: theme}
+}
+
+func (r commandRenderer) Theme() types.Theme {
+	return r.theme
+}
+
+func (r commandRenderer) Render(c *types.Command, opts types.RenderOptions) string {
+	name := lipgloss.NewStyle().Foreground(r.theme.Highlight).Render(r.name)
+	s := name
+
+	if opts.ShowStatus {
+		stat := c.Status
+		if c.Cached {
+			stat = "cached"
+		}
+		status := stat
+		switch stat {
+		case "finished", "cached":
+			status = lipgloss.NewStyle().Foreground(r.theme.Success).Render(stat)
+		case "failed":
+			status = lipgloss.NewStyle().Foreground(r.theme.Error).Render(stat)
+		case "terminating", "exited", "waiting", "skipped", "restarting", "remediating":
+			status = lipgloss.NewStyle().Foreground(r.theme.Subtle).Render(stat)
+		}
+
+		s = fmt.Sprintf("%s %s", name, status)
+	}
+
+	if opts.Width > 0 {
+		s = ansi.Truncate(s, opts.Width, "…")
+	}
+
+	return s
+}
@@ -5,12 +5,26 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"maps"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/fang"
 	"github.com/spf13/cobra"
+	"jrmd.dev/qk/discovery"
+	"jrmd.dev/qk/tooling"
+	"jrmd.dev/qk/utils"
+	"jrmd.dev/qk/version"
 )
 
+// markersAnnotation lists marker files, separated by commas, which make a
+// project for the annotated command in every --detect mode.
+const markersAnnotation = "qk_markers"
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "qk",
@@ -20,12 +34,115 @@ var rootCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		devCmd.Run(cmd, args)
 	},
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if cmd.Flags().Changed("profile") {
+			profile, _ := cmd.Flags().GetString("profile")
+			utils.UseProfile(profile)
+		}
+		if err := utils.CheckProfile(); err != nil {
+			return err
+		}
+
+		conf := utils.GetConfig()
+		if err := applyFlagDefaults(cmd, conf); err != nil {
+			return err
+		}
+		if err := setLogLevel(cmd); err != nil {
+			return err
+		}
+		applyTheme(conf)
+
+		mode, _ := cmd.Flags().GetString("detect")
+		if !cmd.Flags().Changed("detect") && conf.Detect != "" {
+			mode = conf.Detect
+		}
+
+		markers, _ := cmd.Flags().GetStringSlice("markers")
+		if !cmd.Flags().Changed("markers") {
+			markers = conf.Markers
+		}
+		// commands for one kind of project, such as qk go, find it
+		// whatever --detect says
+		if extra := cmd.Annotations[markersAnnotation]; extra != "" {
+			markers = append(slices.Clone(markers), strings.Split(extra, ",")...)
+		}
+		if len(markers) > 0 {
+			discovery.RegisterDetector(discovery.DetectMarkers(markers))
+		}
+
+		// configured project types run in name order, after the built-in ones
+		toolNames := slices.Sorted(maps.Keys(conf.ProjectTypes))
+		for _, name := range toolNames {
+			tooling.Register(tooling.FromConfig(name, conf.ProjectTypes[name]))
+			discovery.RegisterDetector(discovery.DetectFiles(name, conf.ProjectTypes[name].Files))
+		}
+
+		gitignore, _ := cmd.Flags().GetBool("gitignore")
+		utils.UseGitignore(gitignore || conf.Gitignore)
+
+		noCache, _ := cmd.Flags().GetBool("no-cache")
+		utils.UseDiscoveryCache(!noCache, mode+"|"+strings.Join(markers, ",")+"|"+strings.Join(toolNames, ","))
+
+		return discovery.SetMode(mode)
+	},
+}
+
+// applyFlagDefaults sets the flags configured in Flags which weren't given
+// on the command line. Flags the command doesn't have are skipped, so a
+// profile can set build flags such as force without breaking qk ls.
+func applyFlagDefaults(cmd *cobra.Command, conf utils.Config) error {
+	for name, value := range conf.Flags {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			slog.Debug("configured flag does not apply to command", "flag", name, "command", cmd.Name())
+			continue
+		}
+		if flag.Changed {
+			continue
+		}
+
+		values := []any{value}
+		if list, ok := value.([]any); ok {
+			values = list
+		}
+		for _, v := range values {
+			if err := cmd.Flags().Set(name, fmt.Sprint(v)); err != nil {
+				return fmt.Errorf("Flags: --%s: %w", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// setLogLevel applies --verbose, --quiet and --log-level. --quiet wins over
+// --verbose, and an explicit --log-level over both.
+func setLogLevel(cmd *cobra.Command) error {
+	level := slog.LevelWarn
+	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+		level = slog.LevelDebug
+	}
+	if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+		level = slog.LevelError
+	}
+	if cmd.Flags().Changed("log-level") {
+		name, _ := cmd.Flags().GetString("log-level")
+		parsed, err := utils.ParseLogLevel(name)
+		if err != nil {
+			return err
+		}
+		level = parsed
+	}
+
+	utils.SetLogLevel(level)
+	return nil
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := fang.Execute(context.TODO(), rootCmd)
+	addPlugin(rootCmd, os.Args[1:])
+	err := fang.Execute(context.TODO(), rootCmd, fang.WithVersion(version.Get().Version), fang.WithCommit(version.Get().Commit))
 	if err != nil {
 		os.Exit(1)
 	}
@@ -34,4 +151,35 @@ func Execute() {
 func init() {
 	rootCmd.Flags().BoolP("joined", "j", true, "Joined output")
 	rootCmd.PersistentFlags().Int("depth", 3, "number of directories to traverse")
+	rootCmd.PersistentFlags().String("detect", "both", "which manifests make a project: node, php, both or any, which adds go, rust, Makefile and Taskfile projects; --markers and configured ProjectTypes apply in every mode")
+	rootCmd.PersistentFlags().StringSlice("markers", []string{}, "extra marker files that make a project, e.g. go.mod,Cargo.toml")
+	rootCmd.PersistentFlags().Bool("gitignore", false, "skip directories ignored by .gitignore files during discovery")
+	rootCmd.PersistentFlags().Bool("no-cache", false, "walk the directory tree instead of reusing the cached project list")
+	rootCmd.PersistentFlags().String("output", "text", "output format (text, json or github)")
+	rootCmd.PersistentFlags().StringArray("report", []string{}, "write a report once done: csv=builds.csv appends rows, trace=run.json writes a Chrome/Perfetto trace, otlp=http://localhost:4318 sends spans to a collector")
+	rootCmd.PersistentFlags().String("events", "", "write lifecycle events as JSON lines to a file, fd:N or - for stdout with --output json or github")
+	rootCmd.PersistentFlags().StringSlice("only", []string{}, "only run in the named projects")
+	_ = rootCmd.RegisterFlagCompletionFunc("only", completeOnly)
+	rootCmd.PersistentFlags().StringSlice("tag", []string{}, "only run in the projects with any of these configured Tags")
+	_ = rootCmd.RegisterFlagCompletionFunc("tag", completeTags)
+	rootCmd.PersistentFlags().String("changed", "", "only run in projects with files changed since the merge-base of a ref and HEAD, the default branch without one")
+	rootCmd.PersistentFlags().Lookup("changed").NoOptDefVal = defaultBranchRef
+	rootCmd.PersistentFlags().Bool("last", false, "reuse the last project selection for this directory")
+	rootCmd.PersistentFlags().Bool("pick", false, "interactively pick the projects to run in")
+	rootCmd.PersistentFlags().Bool("hold", false, "keep the runner open after failures so they can be retried")
+	rootCmd.PersistentFlags().BoolP("interactive", "i", false, "run projects one at a time attached to the terminal so commands can prompt for input")
+	rootCmd.PersistentFlags().Bool("pty", false, "run commands under a pseudo-terminal to keep their colours and progress output")
+	rootCmd.PersistentFlags().String("in-container", "", "run commands in this docker compose service, or with docker run of its image from Images without a compose file")
+	rootCmd.PersistentFlags().Int("concurrency", 0, "maximum number of commands running at once, unlimited unless configured")
+	rootCmd.PersistentFlags().String("profile", "", "apply a named profile from the config, QK_PROFILE by default")
+	rootCmd.PersistentFlags().Int("failure-lines", 0, "lines of each failed command's output printed once done, 20 unless configured, -1 for none")
+	rootCmd.PersistentFlags().Bool("triage", false, "walk through failures one by one after the run")
+	rootCmd.PersistentFlags().Bool("shared-cache", false, "share package manager caches between projects")
+	rootCmd.PersistentFlags().StringArray("env", []string{}, "set an environment variable on every command (KEY=VALUE, repeatable)")
+	rootCmd.PersistentFlags().Bool("dotenv", false, "load each project's .env and .env.local into its commands")
+	rootCmd.PersistentFlags().Bool("tuned", false, "add performance flags such as --prefer-dist to known tools")
+	rootCmd.PersistentFlags().Bool("verbose", false, "log qk's own decisions, such as why projects were skipped")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "only log qk's own errors")
+	rootCmd.PersistentFlags().String("log-level", "warn", "level of qk's own diagnostics: "+strings.Join(utils.LOG_LEVELS, ", "))
+	rootCmd.PersistentFlags().Duration("grace", 3*time.Second, "time given to commands to shut down before they are killed")
 }
@@ -0,0 +1,55 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/utils"
+	"jrmd.dev/qk/views"
+)
+
+// runCmd represents the run command
+var runCmd = &cobra.Command{
+	Use:     "run <script> [args...]",
+	Aliases: []string{"r"},
+	Short:   "run a package.json or composer.json script in every project that defines it",
+	Long: `This command runs a package.json script with each project's package
+manager (bun, yarn or npm) and a composer.json script with composer,
+skipping projects without the script. Projects defining it in both run
+the composer one once the package.json one succeeded. Projects with neither run the Taskfile task or
+the Makefile target of that name instead.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			fmt.Println("Provide a script...")
+			os.Exit(1)
+		}
+
+		script := args[0]
+		m := views.CreateCommandRunner(runnerOptions(cmd))
+		for _, manager := range utils.NODE_MANAGERS {
+			m.AddOptionalCommand(
+				utils.And(utils.UsesManager(manager), utils.HasScript(script)),
+				RenderCommand(manager),
+				manager,
+				append(utils.RunArgs(manager, script), args[1:]...)...,
+			)
+		}
+		m.AddOptionalCommandAfter(utils.HasComposerScript(script), RenderCommand("composer"), "composer", utils.ComposerRunArgs(script, args[1:]...)...)
+
+		// make and task only stand in for a missing script
+		noScript := utils.Not(utils.Or(utils.HasScript(script), utils.HasComposerScript(script)))
+		m.
+			AddOptionalCommand(utils.And(noScript, utils.HasTask(script)), RenderCommand("task"), "task", utils.TaskArgs(script, args[1:]...)...).
+			AddOptionalCommand(utils.And(noScript, utils.Not(utils.HasTask(script)), utils.HasMakeTarget(script)), RenderCommand("make"), "make", append([]string{script}, args[1:]...)...).
+			Run()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+	runCmd.Flags().BoolP("joined", "j", false, "Joined output")
+}
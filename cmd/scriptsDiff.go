@@ -0,0 +1,66 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/utils"
+)
+
+// scriptsDiffCmd represents the scripts-diff command
+var scriptsDiffCmd = &cobra.Command{
+	Use:   "scripts-diff <name>",
+	Short: "Compare what a package.json script runs across all projects",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		wd, err := os.Getwd()
+		if err != nil {
+			panic(err)
+		}
+
+		name := args[0]
+		projects := listProjects(cmd, wd)
+
+		// give every distinct script body its own colour so drift stands out
+		variants := map[string]int{}
+		rows := [][]string{}
+		for _, project := range projects {
+			script, ok := utils.GetScript(project.FS, project.Dir, name)
+			if !ok {
+				rows = append(rows, []string{project.Name, subtleText.Render("(missing)")})
+				continue
+			}
+
+			if _, seen := variants[script]; !seen {
+				variants[script] = len(variants)
+			}
+			colour := theme.Projects[variants[script]%len(theme.Projects)]
+			rows = append(rows, []string{project.Name, lipgloss.NewStyle().Foreground(colour).Render(script)})
+		}
+
+		t := table.New().
+			Border(lipgloss.NormalBorder()).
+			BorderStyle(lipgloss.NewStyle().Foreground(borderColor)).
+			StyleFunc(func(row, col int) lipgloss.Style {
+				if row == table.HeaderRow {
+					return headerStyle
+				}
+				return cellStyle
+			}).
+			Headers("Project", name).
+			Rows(rows...)
+
+		fmt.Println(t)
+		fmt.Printf("%d distinct variants of %s\n", len(variants), highlightText.Render(name))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scriptsDiffCmd)
+}
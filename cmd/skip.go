@@ -0,0 +1,154 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/utils"
+)
+
+// readSkipList returns the repo-level config file with its Skip list.
+func readSkipList() (string, map[string]any, []string) {
+	file := localConfigTarget()
+	values, err := utils.ReadConfigFile(file)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	skip := []string{}
+	list, _ := values["Skip"].([]any)
+	for _, name := range list {
+		if s, ok := name.(string); ok {
+			skip = append(skip, s)
+		}
+	}
+
+	return file, values, skip
+}
+
+func writeSkipList(file string, values map[string]any, skip []string) {
+	slices.Sort(skip)
+	if len(skip) == 0 {
+		delete(values, "Skip")
+	} else {
+		values["Skip"] = skip
+	}
+
+	if err := utils.WriteConfigFile(file, values); err != nil {
+		exitWithError(err)
+	}
+}
+
+// resolveProjects fuzzy matches each query against the discovered projects.
+func resolveProjects(cmd *cobra.Command, queries []string) []string {
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+
+	depth, _ := cmd.Flags().GetInt("depth")
+	projects := utils.GetAllProjects(wd, depth, 0)
+	names := []string{}
+	for _, query := range queries {
+		project, err := utils.FindProject(projects, query)
+		if err != nil {
+			exitWithError(err)
+		}
+		names = append(names, project.Name)
+	}
+
+	return names
+}
+
+// skipCmd represents the skip command
+var skipCmd = &cobra.Command{
+	Use:   "skip [projects...]",
+	Short: "Leave projects out of every run until qk unskip",
+	Long: `Adds projects, fuzzy matched by name, to the Skip list of the repo-level
+.qk.json so every run leaves them out, unless they are named with --only.
+qk ls still lists them, greyed out. Without projects the skip list is
+printed.`,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		names := []string{}
+		for _, project := range completionProjects(cmd) {
+			if strings.HasPrefix(project.Name, toComplete) && !slices.Contains(args, project.Name) {
+				names = append(names, project.Name)
+			}
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		file, values, skip := readSkipList()
+		if len(args) == 0 {
+			for _, name := range skip {
+				fmt.Println(name)
+			}
+			return
+		}
+
+		for _, name := range resolveProjects(cmd, args) {
+			if !slices.Contains(skip, name) {
+				skip = append(skip, name)
+			}
+			fmt.Printf("%s skipped in %s\n", highlightText.Render(name), subtleText.Render(file))
+		}
+		writeSkipList(file, values, skip)
+	},
+}
+
+// unskipCmd represents the unskip command
+var unskipCmd = &cobra.Command{
+	Use:   "unskip <projects...>",
+	Short: "Include skipped projects in runs again",
+	Long: `Removes projects from the Skip list of the repo-level .qk.json. Names in
+the list match exactly, so projects which no longer exist can be removed,
+others are fuzzy matched like qk skip.`,
+	Args: cobra.MinimumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		_, _, skip := readSkipList()
+		names := []string{}
+		for _, name := range skip {
+			if strings.HasPrefix(name, toComplete) && !slices.Contains(args, name) {
+				names = append(names, name)
+			}
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		file, values, skip := readSkipList()
+
+		names := []string{}
+		queries := []string{}
+		for _, arg := range args {
+			if slices.Contains(skip, arg) {
+				names = append(names, arg)
+			} else {
+				queries = append(queries, arg)
+			}
+		}
+		if len(queries) > 0 {
+			names = append(names, resolveProjects(cmd, queries)...)
+		}
+
+		for _, name := range names {
+			if !slices.Contains(skip, name) {
+				fmt.Printf("%s is not skipped\n", highlightText.Render(name))
+				continue
+			}
+			skip = slices.DeleteFunc(skip, func(s string) bool { return s == name })
+			fmt.Printf("%s unskipped in %s\n", highlightText.Render(name), subtleText.Render(file))
+		}
+		writeSkipList(file, values, skip)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(skipCmd)
+	rootCmd.AddCommand(unskipCmd)
+}
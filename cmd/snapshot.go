@@ -0,0 +1,146 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/tooling"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+	"jrmd.dev/qk/views"
+)
+
+// snapshotCmd represents the snapshot command
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save, verify and restore the dependency state of every project",
+	Long: `Snapshots record the lockfile hashes and installed state of every project,
+with a copy of each lockfile. Verify reports the projects which drifted since,
+restore puts the lockfiles back and reinstalls those projects.`,
+}
+
+func loadSnapshot(name string) utils.Snapshot {
+	snapshot, err := utils.LoadSnapshot(name)
+	if err != nil {
+		fmt.Println(errorText.Render(fmt.Sprintf("Error: %s", err)))
+		os.Exit(1)
+	}
+
+	return snapshot
+}
+
+func printDrift(drift []utils.SnapshotDrift) {
+	for _, d := range drift {
+		changes := append(append([]string{}, d.Lockfiles...), d.Markers...)
+		fmt.Printf("%s %s\n", highlightText.Render(d.Project.Name), subtleText.Render(strings.Join(changes, ", ")))
+	}
+}
+
+var snapshotSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Record the dependency state of every project",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		wd, err := os.Getwd()
+		if err != nil {
+			panic(err)
+		}
+
+		snapshot, err := utils.SaveSnapshot(args[0], wd, listProjects(cmd, wd))
+		if err != nil {
+			fmt.Println(errorText.Render(fmt.Sprintf("Error: %s", err)))
+			os.Exit(1)
+		}
+
+		fmt.Printf("Saved %s with %d projects\n", highlightText.Render(snapshot.Name), len(snapshot.Projects))
+	},
+}
+
+var snapshotVerifyCmd = &cobra.Command{
+	Use:   "verify <name>",
+	Short: "List the projects whose dependencies changed since the snapshot",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		drift := loadSnapshot(args[0]).Drift()
+		if len(drift) == 0 {
+			fmt.Println("Every project matches the snapshot")
+			return
+		}
+
+		printDrift(drift)
+		os.Exit(1)
+	},
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Restore the snapshot's lockfiles and reinstall the projects which drifted",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		wd, err := os.Getwd()
+		if err != nil {
+			panic(err)
+		}
+
+		snapshot := loadSnapshot(args[0])
+		if snapshot.Root != wd {
+			fmt.Println(errorText.Render(fmt.Sprintf("Error: %s was saved in %s, run restore from there", snapshot.Name, snapshot.Root)))
+			os.Exit(1)
+		}
+
+		drift := snapshot.Drift()
+		if len(drift) == 0 {
+			fmt.Println("Every project matches the snapshot")
+			return
+		}
+
+		opts := runnerOptions(cmd)
+		if opts.Output != views.OutputJSON {
+			printDrift(drift)
+		}
+
+		dirs := []string{}
+		for _, d := range drift {
+			if err := snapshot.RestoreLockfiles(d); err != nil {
+				fmt.Println(errorText.Render(fmt.Sprintf("Error: %s", err)))
+				os.Exit(1)
+			}
+			dirs = append(dirs, d.Project.Dir)
+		}
+
+		drifted := func(project types.Project) bool {
+			return slices.Contains(dirs, project.Dir)
+		}
+
+		m := views.CreateCommandRunner(opts)
+		m.
+			AddOptionalPhase(drifted, tooling.Install, RenderCommand).
+			CheckPhp().
+			Run()
+	},
+}
+
+var snapshotLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List saved snapshots",
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, name := range utils.ListSnapshots() {
+			snapshot, err := utils.LoadSnapshot(name)
+			if err != nil {
+				continue
+			}
+			fmt.Printf("%s %s\n", highlightText.Render(name), subtleText.Render(fmt.Sprintf("%s, %d projects in %s", snapshot.Created.Format("2006-01-02 15:04"), len(snapshot.Projects), snapshot.Root)))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+	snapshotCmd.AddCommand(snapshotSaveCmd, snapshotVerifyCmd, snapshotRestoreCmd, snapshotLsCmd)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/utils"
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show how long each project's commands take, slowest first",
+	Long: `Sums up the runs recorded by qk history: the mean and 95th percentile
+duration of every command in every project, counting only the runs in which
+it finished. The latest --recent runs are compared with the ones before and
+flagged when slower by more than --threshold percent.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		project, _ := cmd.Flags().GetString("project")
+		command, _ := cmd.Flags().GetString("command")
+		recent, _ := cmd.Flags().GetInt("recent")
+		threshold, _ := cmd.Flags().GetFloat64("threshold")
+		regressed, _ := cmd.Flags().GetBool("regressed")
+
+		stats := []utils.DurationStats{}
+		for _, s := range utils.HistoryStats(utils.ReadHistory(), recent, threshold/100) {
+			if project != "" && s.Project != project {
+				continue
+			}
+			if command != "" && !strings.Contains(s.Command, command) {
+				continue
+			}
+			if regressed && !s.Regressed {
+				continue
+			}
+			stats = append(stats, s)
+		}
+		slices.SortStableFunc(stats, func(a, b utils.DurationStats) int {
+			switch {
+			case a.Mean > b.Mean:
+				return -1
+			case a.Mean < b.Mean:
+				return 1
+			}
+			return 0
+		})
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			printJSON(stats)
+			return
+		}
+		if len(stats) == 0 {
+			fmt.Println(subtleText.Render("No recorded runs to sum up"))
+			return
+		}
+
+		rows := [][]string{}
+		regressions := 0
+		for _, s := range stats {
+			change := ""
+			switch {
+			case s.Regressed:
+				regressions++
+				change = errorText.Render(fmt.Sprintf("%+.0f%%", s.Change()*100))
+			case s.Baseline > 0:
+				change = fmt.Sprintf("%+.0f%%", s.Change()*100)
+			}
+			rows = append(rows, []string{
+				s.Project,
+				s.Command,
+				strconv.Itoa(s.Runs),
+				historyDuration(s.Mean),
+				historyDuration(s.P95),
+				change,
+			})
+		}
+
+		fmt.Println(historyTable([]string{"Project", "Command", "Runs", "Mean", "P95", "Recent"}, rows))
+		switch {
+		case regressions == 1:
+			fmt.Println(errorText.Render(fmt.Sprintf("1 command got more than %.0f%% slower over its last %d runs", threshold, recent)))
+		case regressions > 1:
+			fmt.Println(errorText.Render(fmt.Sprintf("%d commands got more than %.0f%% slower over their last %d runs", regressions, threshold, recent)))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().String("project", "", "only show this project")
+	statsCmd.Flags().String("command", "", "only show commands containing this, e.g. build")
+	statsCmd.Flags().Int("recent", 5, "number of latest runs compared with the ones before")
+	statsCmd.Flags().Float64("threshold", 25, "percent slower the recent runs have to be to flag a regression")
+	statsCmd.Flags().Bool("regressed", false, "only show regressed commands")
+	statsCmd.Flags().Bool("json", false, "print the statistics as JSON")
+}
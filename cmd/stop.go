@@ -0,0 +1,45 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// stopCmd represents the stop command
+var stopCmd = &cobra.Command{
+	Use:   "stop [project...]",
+	Short: "Stop dev servers started with qk watch --detach, all of them without arguments",
+	Run: func(cmd *cobra.Command, args []string) {
+		processes, err := findProcesses(args)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		grace, _ := cmd.Flags().GetDuration("grace")
+		for _, p := range processes {
+			if !p.Detached() {
+				continue
+			}
+
+			running := p.Running()
+			if err := p.Stop(grace); err != nil {
+				fmt.Println(errorText.Render(fmt.Sprintf("Error: could not stop %s: %s", p.Project, err)))
+				continue
+			}
+
+			if running {
+				fmt.Printf("Stopped %s\n", p.Project)
+			} else {
+				fmt.Println(subtleText.Render(fmt.Sprintf("Forgot %s, it had already exited", p.Project)))
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(stopCmd)
+}
@@ -0,0 +1,33 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/tooling"
+	"jrmd.dev/qk/views"
+)
+
+// testCmd represents the test command
+var testCmd = &cobra.Command{
+	Use:     "test [args...]",
+	Aliases: []string{"t"},
+	Short:   "Runs the tests of every project",
+	Long: `Runs the test script of package.json and composer.json, go test ./... in
+go modules, cargo test in rust crates, the test target of Makefile and
+Taskfile projects and the Test of configured ProjectTypes, in every project.
+Extra arguments are passed on to each of them after --, e.g.
+qk test -- -run TestParse.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		m := views.CreateCommandRunner(runnerOptions(cmd))
+		m.
+			AddPhase(tooling.Test, RenderCommand, args...).
+			Run()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(testCmd)
+	testCmd.Flags().BoolP("joined", "j", false, "Joined output")
+}
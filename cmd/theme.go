@@ -0,0 +1,35 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"log/slog"
+
+	"github.com/charmbracelet/lipgloss"
+	"jrmd.dev/qk/utils"
+	"jrmd.dev/qk/views"
+)
+
+// applyTheme renders commands and the runner with the configured theme,
+// keeping the default one when the config names an unknown theme or
+// colour, which qk config set refuses to write.
+func applyTheme(conf utils.Config) {
+	t, err := utils.ConfiguredTheme(conf)
+	if err != nil {
+		slog.Warn("ignoring theme", "err", err)
+	}
+
+	theme = t
+	views.SetTheme(t)
+
+	subtleText = lipgloss.NewStyle().Foreground(theme.Subtle)
+	highlightText = lipgloss.NewStyle().Foreground(theme.Highlight)
+	errorText = lipgloss.NewStyle().Foreground(theme.Error)
+
+	borderColor = theme.Border
+	headerStyle = lipgloss.NewStyle().Foreground(borderColor).Bold(true).Align(lipgloss.Center)
+	oddRowStyle = cellStyle.Foreground(theme.Row)
+	evenRowStyle = cellStyle.Foreground(theme.AltRow)
+	skippedRowStyle = cellStyle.Foreground(theme.Subtle)
+}
@@ -0,0 +1,43 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"jrmd.dev/qk/version"
+)
+
+// versionCmd represents the version command
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the version of qk and the libraries it was built with",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		info := version.Get()
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			printJSON(info)
+			return
+		}
+
+		fmt.Printf("qk %s\n", highlightText.Render(info.Version))
+		for _, row := range [][2]string{
+			{"commit", info.Commit},
+			{"built", info.Date},
+			{"go", info.Go},
+			{"bubbletea", info.Bubbletea},
+			{"lipgloss", info.Lipgloss},
+		} {
+			if row[1] != "" {
+				fmt.Printf("%s %s\n", subtleText.Render(fmt.Sprintf("%-10s", row[0])), row[1])
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+	versionCmd.Flags().Bool("json", false, "print the versions as JSON")
+}
@@ -4,7 +4,11 @@ Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
 package cmd
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
+	"jrmd.dev/qk/tooling"
+	"jrmd.dev/qk/types"
 	"jrmd.dev/qk/utils"
 	"jrmd.dev/qk/views"
 )
@@ -13,81 +17,61 @@ import (
 var watchCommand = &cobra.Command{
 	Use:     "watch",
 	Aliases: []string{"w"},
-	Short:   "Runs yarn start across all projects",
+	Short:   "Runs start, watch:dev or dev across all projects",
 	Run: func(cmd *cobra.Command, args []string) {
-		depth, _ := cmd.Flags().GetInt("depth");
-		joined, _ := cmd.Flags().GetBool("joined");
-		m := views.CreateCommandRunner(depth, joined)
+		freePorts, _ := cmd.Flags().GetBool("free-ports")
+		opts := runnerOptions(cmd)
+		opts.ProbePorts, _ = cmd.Flags().GetBool("probe-ports")
+		m := views.CreateCommandRunner(opts)
+		if freePorts {
+			m.FreeStalePorts()
+		}
+
+		if assign, _ := cmd.Flags().GetBool("assign-ports"); assign || utils.GetConfig().AssignPorts {
+			m.AssignPorts()
+		}
+
+		if restart, _ := cmd.Flags().GetBool("restart-on-change"); restart {
+			debounce, _ := cmd.Flags().GetDuration("debounce")
+			ignore, _ := cmd.Flags().GetStringSlice("ignore")
+			m.RestartOnChange(debounce, ignore)
+		}
+
+		m.RebuildOnDemand("build", func(manager string) types.CommandRenderer {
+			return RenderCommand(manager)
+		})
+
+		m.AddPhase(tooling.Watch, RenderCommand)
+
+		autoInstall, _ := cmd.Flags().GetBool("auto-install")
+		m.CheckDependencies(autoInstall, RenderCommand)
+
+		if detach, _ := cmd.Flags().GetBool("detach"); detach {
+			m.Detach()
+			return
+		}
+
+		if tmux, _ := cmd.Flags().GetBool("tmux"); tmux {
+			m.RunInTmux()
+			return
+		}
 
-		m.
-			AddOptionalCommand(
-			utils.And(
-				utils.HasYarn,
-					utils.HasScript("start"),
-					utils.Not(utils.HasScript("watch:dev")),
-					utils.Not(utils.HasScript("dev")),
-				),
-				RenderCommand("yarn"),
-				"yarn",
-				"start",
-			).
-			AddOptionalCommand(
-				utils.And(
-					utils.Not(utils.HasYarn),
-					utils.HasScript("start"),
-					utils.Not(utils.HasScript("watch:dev")),
-					utils.Not(utils.HasScript("dev")),
-				),
-				RenderCommand("npm"),
-				"npm",
-				"run",
-				"start",
-			).
-			AddOptionalCommand(
-			utils.And(
-				utils.HasYarn,
-					utils.HasScript("watch:dev"),
-				),
-				RenderCommand("yarn"),
-				"yarn",
-				"watch:dev",
-			).
-			AddOptionalCommand(
-				utils.And(
-					utils.Not(utils.HasYarn),
-					utils.HasScript("watch:dev"),
-				),
-				RenderCommand("npm"),
-				"npm",
-				"run",
-				"watch:dev",
-			).
-			AddOptionalCommand(
-			utils.And(
-				utils.HasYarn,
-					utils.HasScript("dev"),
-				),
-				RenderCommand("yarn"),
-				"yarn",
-				"dev",
-			).
-			AddOptionalCommand(
-				utils.And(
-					utils.Not(utils.HasYarn),
-					utils.HasScript("dev"),
-				),
-				RenderCommand("npm"),
-				"npm",
-				"run",
-				"dev",
-			).
-			Run()
+		m.Run()
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(watchCommand)
 	watchCommand.Flags().BoolP("joined", "j", false, "Joined output")
+	watchCommand.Flags().Bool("free-ports", false, "Offer to kill processes already listening on configured or last assigned ports")
+	watchCommand.Flags().Bool("restart-on-change", false, "Restart a project's commands when its files change")
+	watchCommand.Flags().Duration("debounce", 300*time.Millisecond, "How long files have to stop changing before restarting")
+	watchCommand.Flags().Bool("auto-install", false, "Install dependencies first in projects whose lockfile changed since the last install")
+	watchCommand.Flags().Bool("assign-ports", false, "Give every project its own PORT from PortRange (3000-3999 by default)")
+	watchCommand.Flags().Bool("probe-ports", false, "Find the ports of dev servers which don't print their URL by inspecting their sockets")
+	watchCommand.Flags().Bool("detach", false, "Start the dev servers in the background, see qk ps, qk logs and qk stop")
+	watchCommand.Flags().Bool("tmux", false, "Open a tmux window with a pane per project instead of the TUI")
+	watchCommand.Flags().StringSlice("ignore", []string{}, "Extra patterns to ignore with --restart-on-change, e.g. *.tmp")
 	// Here you will define your flags and configuration settings.
 
 	// Cobra supports Persistent Flags which will work for this command
@@ -22,10 +22,7 @@ var yarnCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		depth, _ := cmd.Flags().GetInt("depth");
-		joined, _ := cmd.Flags().GetBool("joined");
-
-		m := views.CreateCommandRunner(depth, joined)
+		m := views.CreateCommandRunner(runnerOptions(cmd))
 		m.
 			AddCommand(RenderCommand("yarn"), "yarn", args...).
 			Run()
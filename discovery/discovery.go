@@ -0,0 +1,156 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package discovery
+
+import (
+	"fmt"
+	"path"
+	"sync"
+
+	"jrmd.dev/qk/fsys"
+)
+
+// Project is what a detector reports for a directory it recognises.
+type Project struct {
+	Name string
+	Dir  string
+	// Type names the kind of project, e.g. "both" for a composer and node
+	// project.
+	Type string
+}
+
+// Detector inspects dir in fsys and reports whether it is a project.
+type Detector func(fsys fsys.FS, dir string) (*Project, bool)
+
+const (
+	ModeNode = "node"
+	ModePHP  = "php"
+	ModeBoth = "both"
+	ModeAny  = "any"
+)
+
+var (
+	mu        sync.RWMutex
+	builtin   Detector = DetectManifests(ModeBoth)
+	detectors          = []Detector{}
+)
+
+// RegisterDetector adds a detector consulted during discovery. The built-in
+// manifest detector runs first, then detectors are tried in registration
+// order and the first match wins.
+func RegisterDetector(detector Detector) {
+	mu.Lock()
+	defer mu.Unlock()
+	detectors = append(detectors, detector)
+}
+
+// Detect runs the registered detectors against dir in fsys.
+func Detect(fsys fsys.FS, dir string) (*Project, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, detector := range append([]Detector{builtin}, detectors...) {
+		if project, ok := detector(fsys, dir); ok {
+			if project.Name == "" {
+				project.Name = path.Base(dir)
+			}
+			if project.Dir == "" {
+				project.Dir = dir
+			}
+			return project, true
+		}
+	}
+
+	return nil, false
+}
+
+// SetMode switches the built-in detector between node, php, both and any
+// manifests.
+func SetMode(mode string) error {
+	switch mode {
+	case ModeNode, ModePHP, ModeBoth, ModeAny:
+	default:
+		return fmt.Errorf("unknown detection mode %q, expected node, php, both or any", mode)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	builtin = DetectManifests(mode)
+	return nil
+}
+
+// TASKFILES and MAKEFILES are the files task and make read, in the order
+// they look for them.
+var (
+	TASKFILES = []string{"Taskfile.yml", "taskfile.yml", "Taskfile.yaml", "taskfile.yaml"}
+	MAKEFILES = []string{"GNUmakefile", "makefile", "Makefile"}
+)
+
+// buildFiles are the files besides the manifests which make a project in
+// any mode, by project type. A go module with a Makefile is a go project.
+var buildFiles = []struct {
+	projectType string
+	files       []string
+}{
+	{"go", []string{"go.mod"}},
+	{"rust", []string{"Cargo.toml"}},
+	{"task", TASKFILES},
+	{"make", MAKEFILES},
+}
+
+// Orchestrates reports whether projects of projectType usually drive other
+// projects below them, like a Makefile starting the services next to it.
+// Discovery keeps looking for projects below these.
+func Orchestrates(projectType string) bool {
+	return projectType == "make" || projectType == "task"
+}
+
+// DetectManifests returns the built-in detector, matching directories by
+// their composer.json and package.json according to mode. In any mode a
+// go.mod, Cargo.toml, Makefile or Taskfile makes a project too.
+func DetectManifests(mode string) Detector {
+	return func(fsys fsys.FS, dir string) (*Project, bool) {
+		hasComposer := fsys.Exists(path.Join(dir, "composer.json"))
+		hasPackage := fsys.Exists(path.Join(dir, "package.json"))
+
+		projectType := ""
+		switch {
+		case hasComposer && hasPackage:
+			projectType = ModeBoth
+		case hasPackage:
+			projectType = ModeNode
+		case hasComposer:
+			projectType = ModePHP
+		case mode == ModeAny:
+			for _, build := range buildFiles {
+				for _, file := range build.files {
+					if fsys.Exists(path.Join(dir, file)) {
+						return &Project{Dir: dir, Type: build.projectType}, true
+					}
+				}
+			}
+			return nil, false
+		default:
+			return nil, false
+		}
+
+		switch mode {
+		case ModeAny:
+		case ModeNode:
+			if !hasPackage {
+				return nil, false
+			}
+		case ModePHP:
+			if !hasComposer {
+				return nil, false
+			}
+		default:
+			if projectType != ModeBoth {
+				return nil, false
+			}
+		}
+
+		return &Project{Dir: dir, Type: projectType}, true
+	}
+}
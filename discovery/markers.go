@@ -0,0 +1,62 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package discovery
+
+import (
+	"path"
+
+	"jrmd.dev/qk/fsys"
+)
+
+// MARKER_TYPES names the ecosystem of well known marker files.
+var MARKER_TYPES = map[string]string{
+	"go.mod":           "go",
+	"Cargo.toml":       "rust",
+	"pyproject.toml":   "python",
+	"setup.py":         "python",
+	"requirements.txt": "python",
+	"Gemfile":          "ruby",
+	"pom.xml":          "java",
+	"build.gradle":     "java",
+	"Makefile":         "make",
+	"GNUmakefile":      "make",
+	"makefile":         "make",
+	"Taskfile.yml":     "task",
+	"Taskfile.yaml":    "task",
+}
+
+// DetectMarkers returns a detector matching directories containing any of
+// the given marker files.
+func DetectMarkers(markers []string) Detector {
+	return func(fsys fsys.FS, dir string) (*Project, bool) {
+		for _, marker := range markers {
+			if !fsys.Exists(path.Join(dir, marker)) {
+				continue
+			}
+
+			projectType, ok := MARKER_TYPES[marker]
+			if !ok {
+				projectType = marker
+			}
+
+			return &Project{Dir: dir, Type: projectType}, true
+		}
+
+		return nil, false
+	}
+}
+
+// DetectFiles returns a detector matching directories containing any of
+// files as projectType, such as those of a tool added in the config.
+func DetectFiles(projectType string, files []string) Detector {
+	return func(fsys fsys.FS, dir string) (*Project, bool) {
+		for _, file := range files {
+			if fsys.Exists(path.Join(dir, file)) {
+				return &Project{Dir: dir, Type: projectType}, true
+			}
+		}
+
+		return nil, false
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+
+// Package fsys is the filesystem qk discovers and inspects projects through.
+// Discovery is given an FS and the projects it finds keep it, so tests and
+// embedders can pass any fs.FS, such as an fstest.MapFS, to work against a
+// virtual workspace.
+package fsys
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// FS reads absolute paths such as /work/app/package.json from an fs.FS, as
+// work/app/package.json. The zero FS is the real filesystem.
+type FS struct {
+	fsys fs.FS
+}
+
+// OS is the real filesystem.
+var OS = FS{}
+
+var root = os.DirFS("/")
+
+// New returns the FS reading from fsys.
+func New(fsys fs.FS) FS {
+	return FS{fsys: fsys}
+}
+
+func (f FS) fs() fs.FS {
+	if f.fsys == nil {
+		return root
+	}
+
+	return f.fsys
+}
+
+// rel turns an absolute path into the unrooted form io/fs expects.
+func rel(name string) string {
+	name = strings.TrimPrefix(path.Clean(name), "/")
+	if name == "" {
+		return "."
+	}
+
+	return name
+}
+
+func (f FS) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(f.fs(), rel(name))
+}
+
+func (f FS) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(f.fs(), rel(name))
+}
+
+func (f FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(f.fs(), rel(name))
+}
+
+func (f FS) Open(name string) (fs.File, error) {
+	return f.fs().Open(rel(name))
+}
+
+// WalkDir walks the tree rooted at root like filepath.WalkDir, passing
+// absolute paths to fn.
+func (f FS) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return fs.WalkDir(f.fs(), rel(root), func(name string, entry fs.DirEntry, err error) error {
+		return fn(path.Join("/", name), entry, err)
+	})
+}
+
+// Exists reports whether name exists, treating errors other than not
+// existing as existing.
+func (f FS) Exists(name string) bool {
+	_, err := f.Stat(name)
+	return err == nil || !errors.Is(err, fs.ErrNotExist)
+}
+
+// FileExists reports whether name exists, returning errors other than not
+// existing.
+func (f FS) FileExists(name string) (bool, error) {
+	_, err := f.Stat(name)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+
+	return false, err
+}
@@ -0,0 +1,129 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+
+// Package process runs the commands of qk's runner and of the embeddable
+// runner package, keeping types.Command out of the latter's API.
+package process
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"jrmd.dev/qk/types"
+)
+
+// Exec runs command in dir in its own process group, calling out with every
+// line it writes. out may be called from two goroutines at once, one per
+// stream, and keeps being called while the process shuts down.
+//
+// Cancelling ctx sends SIGTERM to the process group and SIGKILL once
+// command.Grace has passed. command.Start and command.Pid are set and
+// command.OnStart is called once the process started, command.Slot and
+// command.Lock are held while it runs.
+func Exec(ctx context.Context, dir string, command *types.Command, out func(line string, stderr bool)) error {
+	c := exec.Command(command.Script, command.Args...)
+	c.Dir = dir
+	c.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if len(command.Env) > 0 {
+		c.Env = append(os.Environ(), command.Env...)
+	}
+
+	if command.Slot != nil {
+		command.Slot.Lock()
+		defer command.Slot.Unlock()
+	}
+	if command.Lock != nil {
+		command.Lock.Lock()
+		defer command.Lock.Unlock()
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	// under a pty stdout and stderr arrive as one stream
+	var stdout, stderr io.Reader
+	if command.Pty {
+		c.SysProcAttr = nil
+		command.Start = time.Now()
+		terminal, err := startPty(c)
+		if err != nil {
+			return err
+		}
+		defer terminal.Close()
+		stdout = terminal
+	} else {
+		stdoutPipe, err := c.StdoutPipe()
+		if err != nil {
+			return err
+		}
+
+		stderrPipe, err := c.StderrPipe()
+		if err != nil {
+			return err
+		}
+		stdout, stderr = stdoutPipe, stderrPipe
+
+		command.Start = time.Now()
+		if err := c.Start(); err != nil {
+			return err
+		}
+	}
+
+	pid := c.Process.Pid
+	command.Pid = pid
+	if command.OnStart != nil {
+		command.OnStart(pid)
+	}
+
+	// Both pipes have to be drained before calling Wait, otherwise trailing
+	// output is lost. They keep draining after cancellation so processes
+	// can log while shutting down.
+	var readers sync.WaitGroup
+	stream := func(r io.Reader, isStderr bool) {
+		defer readers.Done()
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			out(scanner.Text(), isStderr)
+		}
+	}
+	readers.Add(1)
+	go stream(stdout, false)
+	if stderr != nil {
+		readers.Add(1)
+		go stream(stderr, true)
+	}
+
+	// Handle process termination: ask the whole process group to stop and
+	// only kill it once the grace period has passed.
+	exited := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			slog.Info("sending SIGTERM", "dir", dir, "command", command.Script, "pid", pid)
+			_ = syscall.Kill(-pid, syscall.SIGTERM)
+			select {
+			case <-exited:
+			case <-time.After(command.Grace):
+				slog.Info("grace period passed, sending SIGKILL", "dir", dir, "command", command.Script, "pid", pid, "grace", command.Grace)
+				_ = syscall.Kill(-pid, syscall.SIGKILL)
+			}
+		case <-exited:
+		}
+	}()
+
+	readers.Wait()
+	err := c.Wait()
+	close(exited)
+
+	return err
+}
@@ -0,0 +1,25 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package process
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/creack/pty"
+)
+
+// startPty starts c under a pseudo-terminal as wide as ours, so tools keep
+// their colours and progress output. The process leads its own session, and
+// so its own process group.
+func startPty(c *exec.Cmd) (*os.File, error) {
+	cols, _, err := term.GetSize(os.Stdout.Fd())
+	if err != nil || cols <= 0 {
+		cols = 120
+	}
+
+	return pty.StartWithAttrs(c, &pty.Winsize{Rows: 40, Cols: uint16(cols)}, &syscall.SysProcAttr{Setsid: true, Setctty: true})
+}
@@ -0,0 +1,282 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+// FILE is the Starlark script defining pipelines, found in the working
+// directory or above it like a repo's .qk.json.
+const FILE = ".qk.star"
+
+// Path returns the nearest .qk.star above the working directory, or ""
+// when there is none.
+func Path() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for dir := wd; ; dir = path.Dir(dir) {
+		file := path.Join(dir, FILE)
+		if _, err := os.Stat(file); err == nil {
+			return file
+		}
+		if path.Dir(dir) == dir {
+			return ""
+		}
+	}
+}
+
+// Script is a loaded .qk.star. Every top level function not starting with
+// an underscore is a pipeline, called once per project with the project
+// and, when it takes a second parameter, the extra command line arguments.
+// It returns the commands to run in the project one after the other, each
+// a shell snippet or a list of the binary and its arguments; none leaves
+// the project out.
+type Script struct {
+	file    string
+	root    string
+	globals starlark.StringDict
+}
+
+var options = &syntax.FileOptions{
+	Set:             true,
+	While:           true,
+	TopLevelControl: true,
+	GlobalReassign:  true,
+	Recursion:       true,
+}
+
+// Load runs file, root being the directory qk runs from.
+func Load(file string, root string) (*Script, error) {
+	thread := &starlark.Thread{Name: file, Print: func(_ *starlark.Thread, msg string) {
+		fmt.Fprintln(os.Stderr, msg)
+	}}
+	predeclared := starlark.StringDict{
+		"json":   json.Module,
+		"struct": starlark.NewBuiltin("struct", starlarkstruct.Make),
+		"env":    starlark.NewBuiltin("env", env),
+	}
+
+	globals, err := starlark.ExecFileOptions(options, thread, file, nil, predeclared)
+	if err != nil {
+		return nil, describe(err)
+	}
+
+	return &Script{file: file, root: root, globals: globals}, nil
+}
+
+// describe adds the Starlark backtrace to evaluation errors.
+func describe(err error) error {
+	var evalErr *starlark.EvalError
+	if errors.As(err, &evalErr) {
+		return errors.New(evalErr.Backtrace())
+	}
+
+	return err
+}
+
+// Names lists the pipelines of the script, sorted.
+func (s *Script) Names() []string {
+	names := []string{}
+	for name, value := range s.globals {
+		if _, ok := value.(*starlark.Function); ok && !strings.HasPrefix(name, "_") {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// Doc returns the first line of a pipeline's docstring.
+func (s *Script) Doc(name string) string {
+	fn, ok := s.globals[name].(*starlark.Function)
+	if !ok {
+		return ""
+	}
+
+	doc, _, _ := strings.Cut(strings.TrimSpace(fn.Doc()), "\n")
+	return doc
+}
+
+// Commands calls the pipeline name for project, returning the commands it
+// runs there as binary and arguments. Shell snippets run through $SHELL.
+func (s *Script) Commands(name string, project types.Project, args []string) ([][]string, error) {
+	fn, ok := s.globals[name].(*starlark.Function)
+	if !ok || strings.HasPrefix(name, "_") {
+		return nil, fmt.Errorf("%s defines no pipeline %s", s.file, name)
+	}
+
+	params := starlark.Tuple{projectValue(s.root, project)}
+	if fn.NumParams() > 1 {
+		list := []starlark.Value{}
+		for _, arg := range args {
+			list = append(list, starlark.String(arg))
+		}
+		params = append(params, starlark.NewList(list))
+	}
+
+	thread := &starlark.Thread{Name: name + " " + project.Name, Print: func(_ *starlark.Thread, msg string) {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", project.Name, msg)
+	}}
+	result, err := starlark.Call(thread, fn, params, nil)
+	if err != nil {
+		return nil, describe(err)
+	}
+
+	return toCommands(name, result)
+}
+
+func toCommands(name string, result starlark.Value) ([][]string, error) {
+	if result == starlark.None {
+		return nil, nil
+	}
+
+	iterable, ok := result.(starlark.Iterable)
+	if !ok {
+		return nil, fmt.Errorf("%s must return a list of commands, got %s", name, result.Type())
+	}
+
+	sh := os.Getenv("SHELL")
+	if sh == "" {
+		sh = "sh"
+	}
+
+	commands := [][]string{}
+	iter := iterable.Iterate()
+	defer iter.Done()
+	var item starlark.Value
+	for iter.Next(&item) {
+		switch item := item.(type) {
+		case starlark.String:
+			commands = append(commands, []string{sh, "-c", string(item)})
+		case starlark.Iterable:
+			command := []string{}
+			for _, arg := range listOf(item) {
+				s, ok := starlark.AsString(arg)
+				if !ok {
+					return nil, fmt.Errorf("%s returned a command with the argument %s, not a string", name, arg)
+				}
+				command = append(command, s)
+			}
+			if len(command) == 0 {
+				return nil, fmt.Errorf("%s returned an empty command", name)
+			}
+			commands = append(commands, command)
+		default:
+			return nil, fmt.Errorf("%s returned %s, commands are strings or lists of strings", name, item.Type())
+		}
+	}
+
+	return commands, nil
+}
+
+func listOf(iterable starlark.Iterable) []starlark.Value {
+	values := []starlark.Value{}
+	iter := iterable.Iterate()
+	defer iter.Done()
+	var value starlark.Value
+	for iter.Next(&value) {
+		values = append(values, value)
+	}
+
+	return values
+}
+
+func stringList(values []string) *starlark.List {
+	list := []starlark.Value{}
+	for _, value := range values {
+		list = append(list, starlark.String(value))
+	}
+
+	return starlark.NewList(list)
+}
+
+// projectValue is what pipelines get as their project: the fields of the
+// command templates, its scripts, and read, exists and has_script to look
+// into its files.
+func projectValue(root string, project types.Project) starlark.Value {
+	vars := utils.ProjectVars(root, project)
+
+	file := func(name string) string {
+		return path.Join(project.Dir, name)
+	}
+	read := starlark.NewBuiltin("read", func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var name string
+		if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &name); err != nil {
+			return nil, err
+		}
+
+		data, err := project.FS.ReadFile(file(name))
+		if errors.Is(err, os.ErrNotExist) {
+			return starlark.None, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return starlark.String(data), nil
+	})
+	exists := starlark.NewBuiltin("exists", func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var name string
+		if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &name); err != nil {
+			return nil, err
+		}
+
+		ok, _ := project.FS.FileExists(file(name))
+		return starlark.Bool(ok), nil
+	})
+	hasScript := starlark.NewBuiltin("has_script", func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var name string
+		if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &name); err != nil {
+			return nil, err
+		}
+
+		return starlark.Bool(utils.HasScript(name)(project)), nil
+	})
+
+	return starlarkstruct.FromStringDict(starlark.String("project"), starlark.StringDict{
+		"name":             starlark.String(vars.Name),
+		"dir":              starlark.String(vars.Dir),
+		"path":             starlark.String(vars.Path),
+		"manager":          starlark.String(vars.Manager),
+		"type":             starlark.String(vars.Type),
+		"group":            starlark.String(vars.Group),
+		"scripts":          stringList(utils.GetScripts(project.FS, project.Dir)),
+		"composer_scripts": stringList(utils.GetComposerScripts(project.FS, project.Dir)),
+		"read":             read,
+		"exists":           exists,
+		"has_script":       hasScript,
+	})
+}
+
+// env returns an environment variable, or the default given without it.
+func env(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	var fallback starlark.Value = starlark.None
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &name, &fallback); err != nil {
+		return nil, err
+	}
+
+	if value, ok := os.LookupEnv(name); ok {
+		return starlark.String(value), nil
+	}
+
+	return fallback, nil
+}
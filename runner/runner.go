@@ -0,0 +1,259 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+
+// Package runner fans commands out across projects without a TUI, so other
+// Go programs can embed what qk does:
+//
+//	projects, _ := runner.Discover(".", 3)
+//	results, err := runner.NewRunner().
+//		WithProjects(projects...).
+//		AddCommand("yarn", "install").
+//		AddCommand("yarn", "build").
+//		OnEvent(func(e runner.Event) {
+//			if e.Type == runner.EventOutput {
+//				fmt.Printf("%s: %s\n", e.Project.Name, e.Line)
+//			}
+//		}).
+//		Run(ctx)
+//
+// Projects run concurrently, the commands of a project one after another.
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+	"time"
+
+	"jrmd.dev/qk/fsys"
+	"jrmd.dev/qk/internal/process"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+// Project is a directory commands run in.
+type Project struct {
+	Name string
+	Dir  string
+}
+
+// Discover finds the projects below dir the way qk does, descending at most
+// depth directories.
+func Discover(dir string, depth int) ([]Project, error) {
+	if info, err := fsys.OS.Stat(dir); err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	files, err := utils.FindProjects(fsys.OS, dir, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := []Project{}
+	for _, file := range files {
+		projects = append(projects, Project{Name: file.Name, Dir: file.Dir})
+	}
+
+	return projects, nil
+}
+
+// EventType tells what an Event reports.
+type EventType string
+
+const (
+	// EventStarted is sent as a command is started.
+	EventStarted EventType = "started"
+	// EventOutput carries a line written by a command.
+	EventOutput EventType = "output"
+	// EventFinished is sent once a command exited, successfully or not.
+	EventFinished EventType = "finished"
+	// EventSkipped is sent for the commands after a failed one.
+	EventSkipped EventType = "skipped"
+)
+
+// Event reports progress of a Run to the OnEvent callback.
+type Event struct {
+	Type    EventType
+	Project Project
+	Command string
+	Args    []string
+	// Line and Stderr are set for EventOutput.
+	Line   string
+	Stderr bool
+	// Result is set for EventFinished.
+	Result *Result
+}
+
+// Result is the outcome of one command in one project.
+type Result struct {
+	Project Project
+	Command string
+	Args    []string
+	// Status is finished, failed or skipped.
+	Status   string
+	ExitCode int
+	Err      error
+	Start    time.Time
+	Finish   time.Time
+}
+
+// ErrFailed is returned by Run when a command failed.
+var ErrFailed = errors.New("commands failed")
+
+type command struct {
+	script string
+	args   []string
+	when   func(Project) bool
+}
+
+// Runner runs commands across projects, build one with NewRunner.
+type Runner struct {
+	projects []Project
+	commands []command
+	env      []string
+	grace    time.Duration
+	pty      bool
+	onEvent  func(Event)
+	events   sync.Mutex
+}
+
+// NewRunner returns a Runner without projects or commands, giving commands
+// three seconds to shut down once cancelled.
+func NewRunner() *Runner {
+	return &Runner{grace: 3 * time.Second, onEvent: func(Event) {}}
+}
+
+// WithProjects adds projects to run in.
+func (r *Runner) WithProjects(projects ...Project) *Runner {
+	r.projects = append(r.projects, projects...)
+	return r
+}
+
+// AddCommand runs script with args in every project.
+func (r *Runner) AddCommand(script string, args ...string) *Runner {
+	return r.AddOptionalCommand(func(Project) bool { return true }, script, args...)
+}
+
+// AddOptionalCommand runs script with args in the projects when accepts.
+func (r *Runner) AddOptionalCommand(when func(Project) bool, script string, args ...string) *Runner {
+	r.commands = append(r.commands, command{script: script, args: args, when: when})
+	return r
+}
+
+// WithEnv sets KEY=VALUE variables on every command.
+func (r *Runner) WithEnv(env ...string) *Runner {
+	r.env = append(r.env, env...)
+	return r
+}
+
+// WithGrace sets how long commands get to exit after SIGTERM before they
+// are killed.
+func (r *Runner) WithGrace(grace time.Duration) *Runner {
+	r.grace = grace
+	return r
+}
+
+// WithPty runs commands under a pseudo-terminal, their stderr is then
+// reported as stdout.
+func (r *Runner) WithPty(pty bool) *Runner {
+	r.pty = pty
+	return r
+}
+
+// OnEvent calls fn with every event of a Run. Calls never overlap, so fn
+// doesn't have to be safe for concurrent use, but slow callbacks hold up
+// output.
+func (r *Runner) OnEvent(fn func(Event)) *Runner {
+	r.onEvent = fn
+	return r
+}
+
+func (r *Runner) emit(e Event) {
+	r.events.Lock()
+	defer r.events.Unlock()
+	r.onEvent(e)
+}
+
+// Run runs the commands and returns their results in project order. It
+// returns ErrFailed when a command failed, or ctx's error once cancelled.
+func (r *Runner) Run(ctx context.Context) ([]Result, error) {
+	results := make([][]Result, len(r.projects))
+
+	var wg sync.WaitGroup
+	for i, project := range r.projects {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = r.runProject(ctx, project)
+		}()
+	}
+	wg.Wait()
+
+	all := []Result{}
+	failed := false
+	for _, project := range results {
+		for _, result := range project {
+			all = append(all, result)
+			failed = failed || result.Status != "finished"
+		}
+	}
+
+	if ctx.Err() != nil {
+		return all, ctx.Err()
+	}
+	if failed {
+		return all, ErrFailed
+	}
+
+	return all, nil
+}
+
+func (r *Runner) runProject(ctx context.Context, project Project) []Result {
+	results := []Result{}
+	failed := false
+
+	for _, cmd := range r.commands {
+		if !cmd.when(project) {
+			continue
+		}
+
+		if failed || ctx.Err() != nil {
+			results = append(results, Result{Project: project, Command: cmd.script, Args: cmd.args, Status: "skipped"})
+			r.emit(Event{Type: EventSkipped, Project: project, Command: cmd.script, Args: cmd.args})
+			continue
+		}
+
+		result := r.runCommand(ctx, project, cmd)
+		results = append(results, result)
+		failed = result.Status != "finished"
+	}
+
+	return results
+}
+
+func (r *Runner) runCommand(ctx context.Context, project Project, cmd command) Result {
+	c := &types.Command{Script: cmd.script, Args: cmd.args, Env: r.env, Grace: r.grace, Pty: r.pty}
+	r.emit(Event{Type: EventStarted, Project: project, Command: cmd.script, Args: cmd.args})
+	err := process.Exec(ctx, project.Dir, c, func(line string, stderr bool) {
+		r.emit(Event{Type: EventOutput, Project: project, Command: cmd.script, Args: cmd.args, Line: line, Stderr: stderr})
+	})
+
+	result := Result{Project: project, Command: cmd.script, Args: cmd.args, Status: "finished", Err: err, Start: c.Start, Finish: time.Now()}
+	if err != nil {
+		result.Status = "failed"
+		result.ExitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			result.ExitCode = exitErr.ExitCode()
+		}
+	}
+
+	r.emit(Event{Type: EventFinished, Project: project, Command: cmd.script, Args: cmd.args, Result: &result})
+
+	return result
+}
@@ -0,0 +1,181 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package tooling
+
+import (
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+// builtin is a tool whose phases are functions, nil for the phases it has
+// nothing for.
+type builtin struct {
+	name                        string
+	detect                      func(types.Project) bool
+	install, build, test, watch func(types.Project, []string) []Cmd
+}
+
+func (t builtin) Name() string {
+	return t.name
+}
+
+func (t builtin) Detect(project types.Project) bool {
+	return t.detect(project)
+}
+
+func run(phase func(types.Project, []string) []Cmd, project types.Project, args []string) []Cmd {
+	if phase == nil {
+		return nil
+	}
+
+	return phase(project, args)
+}
+
+func (t builtin) InstallCmd(project types.Project, args []string) []Cmd {
+	return run(t.install, project, args)
+}
+
+func (t builtin) BuildCmd(project types.Project, args []string) []Cmd {
+	return run(t.build, project, args)
+}
+
+func (t builtin) TestCmd(project types.Project, args []string) []Cmd {
+	return run(t.test, project, args)
+}
+
+func (t builtin) WatchCmd(project types.Project, args []string) []Cmd {
+	return run(t.watch, project, args)
+}
+
+// when returns cmd when project matches pred.
+func when(pred func(types.Project) bool, project types.Project, cmd Cmd) []Cmd {
+	if !pred(project) {
+		return nil
+	}
+
+	return []Cmd{cmd}
+}
+
+// node is the tool of the projects whose package manager is manager.
+func node(manager string) Tool {
+	return builtin{
+		name:   manager,
+		detect: utils.UsesManager(manager),
+		install: func(project types.Project, args []string) []Cmd {
+			// the workspace root installs its members
+			return when(utils.Not(utils.IsWorkspaceMember), project, append(Cmd{manager}, utils.InstallArgs(manager)...))
+		},
+		build: func(project types.Project, args []string) []Cmd {
+			return []Cmd{append(Cmd{manager}, utils.RunArgs(manager, "build:prod")...)}
+		},
+		test: func(project types.Project, args []string) []Cmd {
+			return when(utils.HasScript("test"), project, append(append(Cmd{manager}, utils.RunArgs(manager, "test")...), args...))
+		},
+		watch: func(project types.Project, args []string) []Cmd {
+			cmds := when(
+				utils.And(utils.HasScript("start"), utils.Not(utils.HasScript("watch:dev")), utils.Not(utils.HasScript("dev"))),
+				project,
+				append(Cmd{manager}, utils.RunArgs(manager, "start")...),
+			)
+			for _, script := range []string{"watch:dev", "dev"} {
+				cmds = append(cmds, when(utils.HasScript(script), project, append(Cmd{manager}, utils.RunArgs(manager, script)...))...)
+			}
+
+			return cmds
+		},
+	}
+}
+
+// firstTarget runs the first of targets the project has with bin, argv
+// turning the target and extra arguments into bin's arguments.
+func firstTarget(bin string, has func(string) func(types.Project) bool, argv func(string, ...string) []string, targets ...string) func(types.Project, []string) []Cmd {
+	return func(project types.Project, args []string) []Cmd {
+		for _, target := range targets {
+			if has(target)(project) {
+				return []Cmd{append(Cmd{bin}, argv(target, args...)...)}
+			}
+		}
+
+		return nil
+	}
+}
+
+// makeArgs passes args on to make after target, as variables such as
+// V=1 or further targets.
+func makeArgs(target string, args ...string) []string {
+	return append([]string{target}, args...)
+}
+
+// isType matches projects discovery recognised as projectType.
+func isType(projectType string) func(types.Project) bool {
+	return func(project types.Project) bool {
+		return utils.ProjectType(project.FS, project.Dir) == projectType
+	}
+}
+
+var cargoRoot = utils.And(utils.HasCargo, utils.Not(utils.IsCargoWorkspaceMember))
+
+// BUILTIN are the tools qk knows, in the order their commands run in a
+// project several of them detect.
+var BUILTIN = []Tool{
+	node("bun"),
+	node("pnpm"),
+	node("yarn"),
+	node("npm"),
+	builtin{
+		name:   "composer",
+		detect: utils.HasComposerJSON,
+		install: func(project types.Project, args []string) []Cmd {
+			return []Cmd{{"composer", "install"}}
+		},
+		test: func(project types.Project, args []string) []Cmd {
+			return when(utils.HasComposerScript("test"), project, append(Cmd{"composer"}, utils.ComposerRunArgs("test", args...)...))
+		},
+	},
+	builtin{
+		name:   "go",
+		detect: utils.HasGoMod,
+		build: func(project types.Project, args []string) []Cmd {
+			return []Cmd{{"go", "build", "./..."}}
+		},
+		test: func(project types.Project, args []string) []Cmd {
+			return []Cmd{append(Cmd{"go", "test", "./..."}, args...)}
+		},
+	},
+	builtin{
+		name:   "cargo",
+		detect: cargoRoot,
+		install: func(project types.Project, args []string) []Cmd {
+			return []Cmd{{"cargo", "fetch"}}
+		},
+		build: func(project types.Project, args []string) []Cmd {
+			return []Cmd{{"cargo", "build"}}
+		},
+		test: func(project types.Project, args []string) []Cmd {
+			return []Cmd{append(Cmd{"cargo", "test"}, args...)}
+		},
+		watch: func(project types.Project, args []string) []Cmd {
+			// cargo watch is a separate install, without it crates are left
+			// alone. It rebuilds like the build phase, libraries have
+			// nothing to run
+			return when(utils.HasCargoWatch, project, Cmd{"cargo", "watch", "-x", "build"})
+		},
+	},
+	// task and make only stand in for projects without a manifest
+	builtin{
+		name:    "task",
+		detect:  isType("task"),
+		install: firstTarget("task", utils.HasTask, utils.TaskArgs, "install"),
+		build:   firstTarget("task", utils.HasTask, utils.TaskArgs, "build"),
+		test:    firstTarget("task", utils.HasTask, utils.TaskArgs, "test"),
+		watch:   firstTarget("task", utils.HasTask, utils.TaskArgs, "watch", "dev"),
+	},
+	builtin{
+		name:   "make",
+		detect: isType("make"),
+		build:  firstTarget("make", utils.HasMakeTarget, makeArgs, "build"),
+		test:   firstTarget("make", utils.HasMakeTarget, makeArgs, "test"),
+		watch:  firstTarget("make", utils.HasMakeTarget, makeArgs, "watch", "dev"),
+	},
+}
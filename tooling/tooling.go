@@ -0,0 +1,149 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package tooling
+
+import (
+	"os"
+	"path"
+	"slices"
+	"sync"
+
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+// Cmd is a command line, the binary followed by its arguments.
+type Cmd []string
+
+// Phase is what qk install, build, test and watch ask the tools for.
+type Phase int
+
+const (
+	Install Phase = iota
+	Build
+	Test
+	Watch
+)
+
+func (p Phase) String() string {
+	switch p {
+	case Install:
+		return "install"
+	case Build:
+		return "build"
+	case Test:
+		return "test"
+	case Watch:
+		return "watch"
+	}
+
+	return "unknown"
+}
+
+// Tool knows the commands a kind of project runs to install, build, test
+// and watch it. A phase the tool has nothing for returns no commands, args
+// are the extra arguments given on the command line.
+type Tool interface {
+	// Name is shown next to the tool's commands, such as yarn.
+	Name() string
+	// Detect reports whether the tool handles project.
+	Detect(project types.Project) bool
+	InstallCmd(project types.Project, args []string) []Cmd
+	BuildCmd(project types.Project, args []string) []Cmd
+	TestCmd(project types.Project, args []string) []Cmd
+	WatchCmd(project types.Project, args []string) []Cmd
+}
+
+var (
+	mu    sync.RWMutex
+	tools = slices.Clone(BUILTIN)
+)
+
+// Register adds a tool after the built-in ones and those registered before
+// it, a project runs the commands of every tool detecting it in that order.
+func Register(tool Tool) {
+	mu.Lock()
+	defer mu.Unlock()
+	tools = append(tools, tool)
+}
+
+// Tools returns the registered tools in order.
+func Tools() []Tool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return slices.Clone(tools)
+}
+
+// Commands returns what tool runs in project for phase.
+func Commands(tool Tool, phase Phase, project types.Project, args []string) []Cmd {
+	switch phase {
+	case Install:
+		return tool.InstallCmd(project, args)
+	case Build:
+		return tool.BuildCmd(project, args)
+	case Test:
+		return tool.TestCmd(project, args)
+	case Watch:
+		return tool.WatchCmd(project, args)
+	}
+
+	return nil
+}
+
+// configured is a project type added in the config, running shell
+// snippets.
+type configured struct {
+	name string
+	conf utils.ProjectTypeConfig
+}
+
+// FromConfig makes a tool of a project type configured in ProjectTypes,
+// detecting the projects with any of its files.
+func FromConfig(name string, conf utils.ProjectTypeConfig) Tool {
+	return configured{name, conf}
+}
+
+func (t configured) Name() string {
+	return t.name
+}
+
+func (t configured) Detect(project types.Project) bool {
+	return slices.ContainsFunc(t.conf.Files, func(file string) bool {
+		exists, _ := project.FS.FileExists(path.Join(project.Dir, file))
+		return exists
+	})
+}
+
+// shell runs snippet through $SHELL, passing args on as "$@".
+func shell(snippet string, args []string) []Cmd {
+	if snippet == "" {
+		return nil
+	}
+
+	sh := os.Getenv("SHELL")
+	if sh == "" {
+		sh = "sh"
+	}
+	if len(args) == 0 {
+		return []Cmd{{sh, "-c", snippet}}
+	}
+
+	return []Cmd{append(Cmd{sh, "-c", snippet + ` "$@"`, sh}, args...)}
+}
+
+func (t configured) InstallCmd(project types.Project, args []string) []Cmd {
+	return shell(t.conf.Install, args)
+}
+
+func (t configured) BuildCmd(project types.Project, args []string) []Cmd {
+	return shell(t.conf.Build, args)
+}
+
+func (t configured) TestCmd(project types.Project, args []string) []Cmd {
+	return shell(t.conf.Test, args)
+}
+
+func (t configured) WatchCmd(project types.Project, args []string) []Cmd {
+	return shell(t.conf.Watch, args)
+}
@@ -4,15 +4,81 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// OutputLimits bound how much output of a command is kept in memory and
+// forwarded to the TUI. Zero values disable a limit.
+type OutputLimits struct {
+	// MaxLineRate is the number of lines per second sent to the TUI.
+	MaxLineRate int
+	// MaxOutput is the number of bytes of output kept in memory.
+	MaxOutput int
+}
+
 type Command struct {
 	Script string
 	Args   []string
+	Env    []string
+	// Lock is held while the command runs, to serialize commands that
+	// can't safely run concurrently.
+	Lock sync.Locker
+	// Slot is held while the command runs, before Lock, to cap how many
+	// commands run at once.
+	Slot   sync.Locker
 	Status string
 	Ctx    context.Context
 	Cancel context.CancelFunc
 	Output *bytes.Buffer
-	Render func(*Command, bool) string
-	Reader *bufio.Scanner
+	Stdout *bytes.Buffer
+	Stderr *bytes.Buffer
+	// OutputLock is held while output is written, read Output through
+	// OutputString while the command runs.
+	OutputLock sync.Mutex
+	// Queued is when the command was due to run, Start once it actually
+	// did after waiting for dependencies and locks.
+	Queued   time.Time
+	Start    time.Time
+	Finish   time.Time
+	ExitCode int
+	Pid      int
+	// Grace is how long the process gets to exit after SIGTERM before it
+	// is killed.
+	Grace   time.Duration
+	LogFile string
+	Limits  OutputLimits
+	// Remediation describes the automatic fix applied after the command
+	// failed on a known error, such as clearing a corrupted cache.
+	Remediation string
+	// Stage orders the commands of a project: a command waits for the
+	// project's commands of earlier stages, such as pre hooks, to finish.
+	Stage int
+	// Pty runs the command under a pseudo-terminal, its stderr is then
+	// part of Stdout.
+	Pty bool
+	// OnStart is called with the pid once the process started.
+	OnStart func(pid int)
+	// OnDemand marks a command started from the runner rather than up
+	// front, such as a build triggered while watching.
+	OnDemand bool
+	// Cached marks a finished command which didn't run because the build
+	// cache had it finish with the same inputs before.
+	Cached bool
+	// Truncated is set once output was dropped because of Limits.
+	Truncated atomic.Bool
+	// OutputBytes counts the output written, including dropped output.
+	OutputBytes atomic.Int64
+	Renderer    CommandRenderer
+	Reader      *bufio.Scanner
+}
+
+// OutputString returns the output captured so far, safe to call while the
+// command is still writing it.
+func (c *Command) OutputString() string {
+	c.OutputLock.Lock()
+	defer c.OutputLock.Unlock()
+
+	return c.Output.String()
 }
@@ -2,11 +2,28 @@ package types
 
 import (
 	"github.com/charmbracelet/bubbles/spinner"
+	"jrmd.dev/qk/fsys"
 )
 
 type Project struct {
 	Spinner spinner.Model
 	Name    string
 	Dir     string
-	Scripts []*Command
+	// FS is the filesystem the project was found in.
+	FS        fsys.FS
+	Workspace string
+	Group     string
+	// Git is the checked out branch, marked with * when the project has
+	// uncommitted changes, empty outside of a repository.
+	Git string
+	// Containers sums up the state of the containers of a compose
+	// project, such as "2/3 up, 1 unhealthy".
+	Containers string
+	// Port is the PORT assigned to the project's commands, 0 for none.
+	Port int
+	// URL is where the project's dev server listens, once detected.
+	URL              string
+	Scripts          []*Command
+	Lockfiles        map[string]string
+	ChangedLockfiles []string
 }
@@ -0,0 +1,15 @@
+package types
+
+// RenderOptions controls how a command is rendered.
+type RenderOptions struct {
+	// ShowStatus appends the command status to its name.
+	ShowStatus bool
+	// Width truncates the rendered command, 0 means unlimited.
+	Width int
+}
+
+// CommandRenderer renders a command for display in the runner.
+type CommandRenderer interface {
+	Render(c *Command, opts RenderOptions) string
+	Theme() Theme
+}
@@ -0,0 +1,38 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package types
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/bubbles/spinner"
+)
+
+// SPINNERS are the spinners a project can show while its commands run,
+// by the name the Spinner config picks them with.
+var SPINNERS = map[string]spinner.Spinner{
+	"dot":       spinner.Dot,
+	"minidot":   spinner.MiniDot,
+	"line":      spinner.Line,
+	"jump":      spinner.Jump,
+	"pulse":     spinner.Pulse,
+	"points":    spinner.Points,
+	"globe":     spinner.Globe,
+	"moon":      spinner.Moon,
+	"monkey":    spinner.Monkey,
+	"meter":     spinner.Meter,
+	"hamburger": spinner.Hamburger,
+	"ellipsis":  spinner.Ellipsis,
+}
+
+// SpinnerNames lists the names of SPINNERS in order.
+func SpinnerNames() []string {
+	names := []string{}
+	for name := range SPINNERS {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
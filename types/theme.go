@@ -0,0 +1,270 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme holds the colours qk renders with. Each has a light and a dark
+// variant, picked by the terminal's background.
+type Theme struct {
+	// Text is written on Primary, such as the runner's title.
+	Text lipgloss.AdaptiveColor
+	// Output is command output that brings no colours of its own.
+	Output lipgloss.AdaptiveColor
+	// Primary is the background of titles and search matches.
+	Primary lipgloss.AdaptiveColor
+	// Accent marks the selected project and subtitles.
+	Accent lipgloss.AdaptiveColor
+	// Highlight marks command names.
+	Highlight lipgloss.AdaptiveColor
+	Success   lipgloss.AdaptiveColor
+	Error     lipgloss.AdaptiveColor
+	Warning   lipgloss.AdaptiveColor
+	// Subtle is for secondary text such as statuses, timings and hints.
+	Subtle lipgloss.AdaptiveColor
+	// Faint is for separators.
+	Faint   lipgloss.AdaptiveColor
+	Spinner lipgloss.AdaptiveColor
+	// ProgressStart and ProgressEnd are the ends of the progress bar's
+	// gradient.
+	ProgressStart lipgloss.AdaptiveColor
+	ProgressEnd   lipgloss.AdaptiveColor
+	// Border, Row and AltRow colour tables.
+	Border lipgloss.AdaptiveColor
+	Row    lipgloss.AdaptiveColor
+	AltRow lipgloss.AdaptiveColor
+	// Projects are cycled through to tell projects apart.
+	Projects []lipgloss.AdaptiveColor
+}
+
+func same(colour string) lipgloss.AdaptiveColor {
+	return lipgloss.AdaptiveColor{Light: colour, Dark: colour}
+}
+
+func DefaultTheme() Theme {
+	return Theme{
+		Text:          same("#EEEEEE"),
+		Output:        lipgloss.AdaptiveColor{Light: "#3C3C3C", Dark: "#EEEEEE"},
+		Primary:       lipgloss.AdaptiveColor{Light: "#874BFD", Dark: "#7D56F4"},
+		Accent:        same("#04a5e5"),
+		Highlight:     same("#dc8a78"),
+		Success:       lipgloss.AdaptiveColor{Light: "#43BF6D", Dark: "#73F59F"},
+		Error:         same("#FF5555"),
+		Warning:       lipgloss.AdaptiveColor{Light: "#df8e1d", Dark: "#edc43e"},
+		Subtle:        lipgloss.AdaptiveColor{Light: "#969B86", Dark: "#696969"},
+		Faint:         lipgloss.AdaptiveColor{Light: "#D9DCCF", Dark: "#383838"},
+		Spinner:       same("205"),
+		ProgressStart: same("#5A56E0"),
+		ProgressEnd:   same("#EE6FF8"),
+		Border:        same("99"),
+		Row:           same("245"),
+		AltRow:        same("241"),
+		Projects: []lipgloss.AdaptiveColor{
+			same("#15ec75"),
+			same("#8310ec"),
+			same("#da50e1"),
+			same("#edc43e"),
+			same("#b14e86"),
+			same("#0d6ce9"),
+			same("#f66582"),
+		},
+	}
+}
+
+// THEMES are the built-in themes by name.
+var THEMES = map[string]func() Theme{
+	"default": DefaultTheme,
+	// latte on light terminals, mocha on dark ones
+	"catppuccin": func() Theme {
+		return Theme{
+			Text:          lipgloss.AdaptiveColor{Light: "#eff1f5", Dark: "#1e1e2e"},
+			Output:        lipgloss.AdaptiveColor{Light: "#4c4f69", Dark: "#cdd6f4"},
+			Primary:       lipgloss.AdaptiveColor{Light: "#8839ef", Dark: "#cba6f7"},
+			Accent:        lipgloss.AdaptiveColor{Light: "#1e66f5", Dark: "#89b4fa"},
+			Highlight:     lipgloss.AdaptiveColor{Light: "#fe640b", Dark: "#fab387"},
+			Success:       lipgloss.AdaptiveColor{Light: "#40a02b", Dark: "#a6e3a1"},
+			Error:         lipgloss.AdaptiveColor{Light: "#d20f39", Dark: "#f38ba8"},
+			Warning:       lipgloss.AdaptiveColor{Light: "#df8e1d", Dark: "#f9e2af"},
+			Subtle:        lipgloss.AdaptiveColor{Light: "#8c8fa1", Dark: "#7f849c"},
+			Faint:         lipgloss.AdaptiveColor{Light: "#acb0be", Dark: "#585b70"},
+			Spinner:       lipgloss.AdaptiveColor{Light: "#ea76cb", Dark: "#f5c2e7"},
+			ProgressStart: lipgloss.AdaptiveColor{Light: "#1e66f5", Dark: "#89b4fa"},
+			ProgressEnd:   lipgloss.AdaptiveColor{Light: "#8839ef", Dark: "#cba6f7"},
+			Border:        lipgloss.AdaptiveColor{Light: "#7287fd", Dark: "#b4befe"},
+			Row:           lipgloss.AdaptiveColor{Light: "#4c4f69", Dark: "#cdd6f4"},
+			AltRow:        lipgloss.AdaptiveColor{Light: "#6c6f85", Dark: "#a6adc8"},
+			Projects: []lipgloss.AdaptiveColor{
+				{Light: "#40a02b", Dark: "#a6e3a1"},
+				{Light: "#8839ef", Dark: "#cba6f7"},
+				{Light: "#ea76cb", Dark: "#f5c2e7"},
+				{Light: "#df8e1d", Dark: "#f9e2af"},
+				{Light: "#e64553", Dark: "#eba0ac"},
+				{Light: "#1e66f5", Dark: "#89b4fa"},
+				{Light: "#fe640b", Dark: "#fab387"},
+			},
+		}
+	},
+	// alucard on light terminals
+	"dracula": func() Theme {
+		return Theme{
+			Text:          lipgloss.AdaptiveColor{Light: "#fffbeb", Dark: "#282a36"},
+			Output:        lipgloss.AdaptiveColor{Light: "#1f1f1f", Dark: "#f8f8f2"},
+			Primary:       lipgloss.AdaptiveColor{Light: "#644ac9", Dark: "#bd93f9"},
+			Accent:        lipgloss.AdaptiveColor{Light: "#036a96", Dark: "#8be9fd"},
+			Highlight:     lipgloss.AdaptiveColor{Light: "#a34d14", Dark: "#ffb86c"},
+			Success:       lipgloss.AdaptiveColor{Light: "#14710a", Dark: "#50fa7b"},
+			Error:         lipgloss.AdaptiveColor{Light: "#cb3a2a", Dark: "#ff5555"},
+			Warning:       lipgloss.AdaptiveColor{Light: "#846e15", Dark: "#f1fa8c"},
+			Subtle:        lipgloss.AdaptiveColor{Light: "#635d97", Dark: "#6272a4"},
+			Faint:         lipgloss.AdaptiveColor{Light: "#cfcfde", Dark: "#44475a"},
+			Spinner:       lipgloss.AdaptiveColor{Light: "#a3144d", Dark: "#ff79c6"},
+			ProgressStart: lipgloss.AdaptiveColor{Light: "#644ac9", Dark: "#bd93f9"},
+			ProgressEnd:   lipgloss.AdaptiveColor{Light: "#a3144d", Dark: "#ff79c6"},
+			Border:        lipgloss.AdaptiveColor{Light: "#644ac9", Dark: "#bd93f9"},
+			Row:           lipgloss.AdaptiveColor{Light: "#1f1f1f", Dark: "#f8f8f2"},
+			AltRow:        lipgloss.AdaptiveColor{Light: "#635d97", Dark: "#6272a4"},
+			Projects: []lipgloss.AdaptiveColor{
+				{Light: "#14710a", Dark: "#50fa7b"},
+				{Light: "#644ac9", Dark: "#bd93f9"},
+				{Light: "#a3144d", Dark: "#ff79c6"},
+				{Light: "#846e15", Dark: "#f1fa8c"},
+				{Light: "#a34d14", Dark: "#ffb86c"},
+				{Light: "#036a96", Dark: "#8be9fd"},
+				{Light: "#cb3a2a", Dark: "#ff5555"},
+			},
+		}
+	},
+	// polar night text on light terminals, snow storm on dark ones
+	"nord": func() Theme {
+		return Theme{
+			Text:          lipgloss.AdaptiveColor{Light: "#eceff4", Dark: "#2e3440"},
+			Output:        lipgloss.AdaptiveColor{Light: "#2e3440", Dark: "#eceff4"},
+			Primary:       lipgloss.AdaptiveColor{Light: "#5e81ac", Dark: "#88c0d0"},
+			Accent:        lipgloss.AdaptiveColor{Light: "#5e81ac", Dark: "#81a1c1"},
+			Highlight:     same("#d08770"),
+			Success:       lipgloss.AdaptiveColor{Light: "#6f8f52", Dark: "#a3be8c"},
+			Error:         same("#bf616a"),
+			Warning:       lipgloss.AdaptiveColor{Light: "#b48a2c", Dark: "#ebcb8b"},
+			Subtle:        lipgloss.AdaptiveColor{Light: "#4c566a", Dark: "#616e88"},
+			Faint:         lipgloss.AdaptiveColor{Light: "#d8dee9", Dark: "#4c566a"},
+			Spinner:       same("#b48ead"),
+			ProgressStart: lipgloss.AdaptiveColor{Light: "#5e81ac", Dark: "#81a1c1"},
+			ProgressEnd:   lipgloss.AdaptiveColor{Light: "#4c8c8b", Dark: "#8fbcbb"},
+			Border:        lipgloss.AdaptiveColor{Light: "#5e81ac", Dark: "#81a1c1"},
+			Row:           lipgloss.AdaptiveColor{Light: "#2e3440", Dark: "#d8dee9"},
+			AltRow:        lipgloss.AdaptiveColor{Light: "#4c566a", Dark: "#e5e9f0"},
+			Projects: []lipgloss.AdaptiveColor{
+				{Light: "#6f8f52", Dark: "#a3be8c"},
+				same("#b48ead"),
+				{Light: "#5e81ac", Dark: "#88c0d0"},
+				{Light: "#b48a2c", Dark: "#ebcb8b"},
+				same("#d08770"),
+				{Light: "#4c8c8b", Dark: "#8fbcbb"},
+				same("#bf616a"),
+			},
+		}
+	},
+}
+
+// ThemeNames lists the built-in themes in order.
+func ThemeNames() []string {
+	names := []string{}
+	for name := range THEMES {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+func (t *Theme) colors() map[string]*lipgloss.AdaptiveColor {
+	return map[string]*lipgloss.AdaptiveColor{
+		"Text":          &t.Text,
+		"Output":        &t.Output,
+		"Primary":       &t.Primary,
+		"Accent":        &t.Accent,
+		"Highlight":     &t.Highlight,
+		"Success":       &t.Success,
+		"Error":         &t.Error,
+		"Warning":       &t.Warning,
+		"Subtle":        &t.Subtle,
+		"Faint":         &t.Faint,
+		"Spinner":       &t.Spinner,
+		"ProgressStart": &t.ProgressStart,
+		"ProgressEnd":   &t.ProgressEnd,
+		"Border":        &t.Border,
+		"Row":           &t.Row,
+		"AltRow":        &t.AltRow,
+	}
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// parseColor reads "#rrggbb" or an ANSI colour number, or two of them as
+// "light,dark" for different colours on light and dark terminals.
+func parseColor(value string) (lipgloss.AdaptiveColor, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) > 2 {
+		return lipgloss.AdaptiveColor{}, fmt.Errorf("expects a colour or light,dark colours, got %q", value)
+	}
+
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if n, err := strconv.Atoi(part); !hexColor.MatchString(part) && (err != nil || n < 0 || n > 255) {
+			return lipgloss.AdaptiveColor{}, fmt.Errorf("expects #rrggbb or an ANSI colour from 0 to 255, got %q", part)
+		}
+		parts[i] = part
+	}
+
+	if len(parts) == 1 {
+		return same(parts[0]), nil
+	}
+	return lipgloss.AdaptiveColor{Light: parts[0], Dark: parts[1]}, nil
+}
+
+// SetColor replaces the colour named like one of the theme's fields, such
+// as Error, see parseColor for the values it takes. Projects takes a list
+// of them separated by spaces.
+func (t *Theme) SetColor(name string, value string) error {
+	if name == "Projects" {
+		projects := []lipgloss.AdaptiveColor{}
+		for _, field := range strings.Fields(value) {
+			colour, err := parseColor(field)
+			if err != nil {
+				return fmt.Errorf("Projects %w", err)
+			}
+			projects = append(projects, colour)
+		}
+		if len(projects) == 0 {
+			return fmt.Errorf("Projects expects at least one colour")
+		}
+		t.Projects = projects
+		return nil
+	}
+
+	field, ok := t.colors()[name]
+	if !ok {
+		names := []string{}
+		for name := range t.colors() {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return fmt.Errorf("unknown theme colour %q, expected one of %s or Projects", name, strings.Join(names, ", "))
+	}
+
+	colour, err := parseColor(value)
+	if err != nil {
+		return fmt.Errorf("%s %w", name, err)
+	}
+	*field = colour
+	return nil
+}
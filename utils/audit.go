@@ -0,0 +1,166 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path"
+	"slices"
+	"strings"
+
+	"jrmd.dev/qk/fsys"
+)
+
+// SEVERITIES are the vulnerability severities from least to most severe.
+var SEVERITIES = []string{"info", "low", "moderate", "high", "critical"}
+
+// AuditCounts maps a severity to the number of vulnerabilities found.
+type AuditCounts map[string]int
+
+// AtLeast counts the vulnerabilities of severity level or worse.
+func (c AuditCounts) AtLeast(level string) int {
+	total := 0
+	for _, severity := range SEVERITIES[max(slices.Index(SEVERITIES, level), 0):] {
+		total += c[severity]
+	}
+
+	return total
+}
+
+func (c AuditCounts) add(severity string) {
+	severity = strings.ToLower(severity)
+	if severity == "medium" {
+		severity = "moderate"
+	}
+	if !slices.Contains(SEVERITIES, severity) {
+		// unrated advisories count as moderate rather than being lost
+		severity = "moderate"
+	}
+	c[severity]++
+}
+
+// AuditArgs returns the tool and arguments auditing a project's node
+// dependencies as JSON.
+func AuditArgs(dir string, manager string) (string, []string, error) {
+	switch manager {
+	case "npm", "pnpm":
+		return manager, []string{"audit", "--json"}, nil
+	case "yarn":
+		if YarnBerry(fsys.OS, dir) {
+			return manager, []string{"npm", "audit", "--json", "--recursive"}, nil
+		}
+		return manager, []string{"audit", "--json"}, nil
+	}
+
+	return "", nil, fmt.Errorf("%s can't audit packages as JSON", manager)
+}
+
+// Audit counts the known vulnerabilities in the node and composer
+// dependencies of the project in dir. manager is its node package manager.
+func Audit(dir string, manager string) (AuditCounts, error) {
+	counts := AuditCounts{}
+
+	type audit struct {
+		tool string
+		args []string
+	}
+	audits := []audit{}
+	if manager != "" {
+		tool, args, err := AuditArgs(dir, manager)
+		if err != nil {
+			return counts, err
+		}
+		audits = append(audits, audit{tool, args})
+	}
+	if exists, _ := FileExists(path.Join(dir, "composer.json")); exists {
+		audits = append(audits, audit{"composer", []string{"audit", "--format=json", "--no-interaction"}})
+	}
+
+	for _, a := range audits {
+		c := exec.Command(a.tool, a.args...)
+		c.Dir = dir
+		// the tools exit non-zero when something is vulnerable
+		out, err := c.Output()
+		if len(out) == 0 && err != nil {
+			return counts, fmt.Errorf("%s %s: %w", a.tool, strings.Join(a.args, " "), err)
+		}
+
+		if err := ParseAudit(a.tool, out, counts); err != nil {
+			return counts, fmt.Errorf("%s %s: %w", a.tool, strings.Join(a.args, " "), err)
+		}
+	}
+
+	return counts, nil
+}
+
+// ParseAudit adds the vulnerabilities in a tool's JSON audit output to
+// counts.
+func ParseAudit(tool string, out []byte, counts AuditCounts) error {
+	switch tool {
+	case "npm", "pnpm":
+		report := struct {
+			Metadata struct {
+				Vulnerabilities map[string]int `json:"vulnerabilities"`
+			} `json:"metadata"`
+		}{}
+		if err := json.Unmarshal(out, &report); err != nil {
+			return err
+		}
+		for _, severity := range SEVERITIES {
+			counts[severity] += report.Metadata.Vulnerabilities[severity]
+		}
+	case "yarn":
+		// yarn 1 ends its stream with a summary, later versions print one
+		// advisory per line
+		scanner := bufio.NewScanner(strings.NewReader(string(out)))
+		scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+		for scanner.Scan() {
+			line := struct {
+				Type string `json:"type"`
+				Data struct {
+					Vulnerabilities map[string]int `json:"vulnerabilities"`
+				} `json:"data"`
+				Children struct {
+					Severity string `json:"Severity"`
+				} `json:"children"`
+			}{}
+			if json.Unmarshal(scanner.Bytes(), &line) != nil {
+				continue
+			}
+			switch {
+			case line.Type == "auditSummary":
+				for _, severity := range SEVERITIES {
+					counts[severity] += line.Data.Vulnerabilities[severity]
+				}
+			case line.Children.Severity != "":
+				counts.add(line.Children.Severity)
+			}
+		}
+	case "composer":
+		report := struct {
+			Advisories json.RawMessage `json:"advisories"`
+		}{}
+		if err := json.Unmarshal(out, &report); err != nil {
+			return err
+		}
+
+		// composer prints an empty list rather than an empty object
+		byPackage := map[string][]struct {
+			Severity string `json:"severity"`
+		}{}
+		_ = json.Unmarshal(report.Advisories, &byPackage)
+		for _, advisories := range byPackage {
+			for _, advisory := range advisories {
+				counts.add(advisory.Severity)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown tool %q", tool)
+	}
+
+	return nil
+}
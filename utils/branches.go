@@ -0,0 +1,88 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Branch is a local git branch.
+type Branch struct {
+	Name       string
+	LastCommit time.Time
+	Current    bool
+	// Merged is set when the branch is merged into the default branch.
+	Merged bool
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	return strings.TrimRight(string(out), "\n"), err
+}
+
+// DefaultBranch returns the branch a repository merges into, taken from
+// origin's HEAD or else main or master.
+func DefaultBranch(repo string) string {
+	if ref, err := git(repo, "symbolic-ref", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		return strings.TrimPrefix(ref, "origin/")
+	}
+
+	for _, name := range []string{"main", "master"} {
+		if _, err := git(repo, "rev-parse", "--verify", "--quiet", "refs/heads/"+name); err == nil {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// Branches lists the local branches of a repository with their last commit
+// and whether they're merged into the default branch.
+func Branches(repo string) ([]Branch, error) {
+	out, err := git(repo, "for-each-ref", "--format=%(HEAD)%09%(refname:short)%09%(committerdate:unix)", "refs/heads")
+	if err != nil {
+		return nil, err
+	}
+
+	merged := []string{}
+	if base := DefaultBranch(repo); base != "" {
+		if list, err := git(repo, "for-each-ref", "--format=%(refname:short)", "--merged", base, "refs/heads"); err == nil {
+			merged = strings.Split(list, "\n")
+		}
+	}
+
+	branches := []Branch{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			continue
+		}
+
+		unix, _ := strconv.ParseInt(fields[2], 10, 64)
+		branches = append(branches, Branch{
+			Name:       fields[1],
+			LastCommit: time.Unix(unix, 0),
+			Current:    fields[0] == "*",
+			Merged:     slices.Contains(merged, fields[1]),
+		})
+	}
+
+	return branches, nil
+}
+
+// Stale reports whether a branch can be cleaned up: it isn't checked out or
+// the default branch, and it's either merged or older than maxAge.
+func (b Branch) Stale(defaultBranch string, maxAge time.Duration) bool {
+	if b.Current || b.Name == defaultBranch {
+		return false
+	}
+
+	return b.Merged || time.Since(b.LastCommit) > maxAge
+}
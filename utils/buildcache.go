@@ -0,0 +1,195 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"jrmd.dev/qk/fsys"
+)
+
+// BUILD_OUTPUTS are directories builds commonly write to, left out of a
+// project's inputs when it isn't in a git repository whose .gitignore
+// already leaves them out.
+var BUILD_OUTPUTS = []string{"dist", "build", "out", ".next", ".nuxt", ".output", ".cache", ".turbo", "coverage"}
+
+// BuildCacheEntry records the inputs of a project's last build in which
+// every command finished, and the BUILD_OUTPUTS it left behind.
+type BuildCacheEntry struct {
+	Dir     string
+	Hash    string
+	Time    time.Time
+	Outputs []string `json:",omitempty"`
+}
+
+// BuildOutputs lists the BUILD_OUTPUTS present in dir.
+func BuildOutputs(fsys fsys.FS, dir string) []string {
+	outputs := []string{}
+	for _, name := range BUILD_OUTPUTS {
+		if info, err := fsys.Stat(path.Join(dir, name)); err == nil && info.IsDir() {
+			outputs = append(outputs, name)
+		}
+	}
+
+	return outputs
+}
+
+// OutputsExist reports whether every output recorded with the build is
+// still there, a build whose dist was removed having to run again.
+func (e BuildCacheEntry) OutputsExist(fsys fsys.FS) bool {
+	for _, name := range e.Outputs {
+		if info, err := fsys.Stat(path.Join(e.Dir, name)); err != nil || !info.IsDir() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func buildCacheFile() (string, error) {
+	return stateFile("builds.json")
+}
+
+// ReadBuildCache returns the recorded builds keyed by project directory.
+func ReadBuildCache() map[string]BuildCacheEntry {
+	cache := map[string]BuildCacheEntry{}
+
+	file, err := buildCacheFile()
+	if err != nil {
+		return cache
+	}
+
+	data, err := fsys.OS.ReadFile(file)
+	if err != nil {
+		return cache
+	}
+
+	_ = json.Unmarshal(data, &cache)
+	return cache
+}
+
+// WriteBuildCache replaces the recorded builds.
+func WriteBuildCache(cache map[string]BuildCacheEntry) error {
+	file, err := buildCacheFile()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(path.Dir(file), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, data, 0o644)
+}
+
+// RecordBuilds adds entries to the recorded builds, replacing earlier ones
+// of the same directories.
+func RecordBuilds(entries ...BuildCacheEntry) error {
+	cache := ReadBuildCache()
+	for _, entry := range entries {
+		cache[entry.Dir] = entry
+	}
+
+	return WriteBuildCache(cache)
+}
+
+// ClearBuildCache forgets every recorded build.
+func ClearBuildCache() error {
+	file, err := buildCacheFile()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// InputsHash hashes the files making up the project in dir: the ones git
+// tracks or would track, or else every file outside BLACKLIST and
+// BUILD_OUTPUTS, plus its lockfiles even when they are ignored.
+func InputsHash(fsys fsys.FS, dir string) (string, error) {
+	files, err := inputFiles(fsys, dir)
+	if err != nil {
+		return "", err
+	}
+	for _, lockfile := range LOCKFILES {
+		if exists, _ := fsys.FileExists(path.Join(dir, lockfile)); exists && !slices.Contains(files, lockfile) {
+			files = append(files, lockfile)
+		}
+	}
+	slices.Sort(files)
+
+	sum := sha256.New()
+	for _, file := range files {
+		f, err := fsys.Open(path.Join(dir, file))
+		if err != nil {
+			// deleted but still tracked, the deletion is what changed
+			fmt.Fprintf(sum, "%s\x00deleted\n", file)
+			continue
+		}
+
+		content := sha256.New()
+		_, err = io.Copy(content, f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(sum, "%s\x00%x\n", file, content.Sum(nil))
+	}
+
+	return hex.EncodeToString(sum.Sum(nil)), nil
+}
+
+// inputFiles lists the files of a project relative to dir.
+func inputFiles(fsys fsys.FS, dir string) ([]string, error) {
+	if _, ok := GitRoot(dir); ok {
+		c := exec.Command("git", "ls-files", "-z", "--cached", "--others", "--exclude-standard", "--", ".")
+		c.Dir = dir
+		if out, err := c.Output(); err == nil {
+			return strings.FieldsFunc(string(out), func(r rune) bool { return r == 0 }), nil
+		}
+	}
+
+	files := []string{}
+	err := fsys.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if file != dir && (slices.Contains(BLACKLIST, entry.Name()) || slices.Contains(BUILD_OUTPUTS, entry.Name())) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		files = append(files, rel)
+		return nil
+	})
+
+	return files, err
+}
@@ -0,0 +1,40 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"path"
+)
+
+// CacheSpec describes how a package manager's cache can be shared between
+// projects.
+type CacheSpec struct {
+	// Env is the environment variable pointing the tool at its cache.
+	Env string
+	// Serialize is set for tools whose cache breaks under concurrent use.
+	Serialize bool
+}
+
+var CACHES = map[string]CacheSpec{
+	"bun":      {Env: "BUN_INSTALL_CACHE_DIR"},
+	"composer": {Env: "COMPOSER_CACHE_DIR"},
+	"npm":      {Env: "npm_config_cache"},
+	"yarn":     {Env: "YARN_CACHE_FOLDER", Serialize: true},
+}
+
+// SharedCacheEnv returns the environment needed to point tool at the shared
+// cache under ~/.qk/cache, or nil when the tool isn't known.
+func SharedCacheEnv(tool string) []string {
+	spec, ok := CACHES[tool]
+	if !ok {
+		return nil
+	}
+
+	dir, err := QkDir()
+	if err != nil {
+		return nil
+	}
+
+	return []string{spec.Env + "=" + path.Join(dir, "cache", tool)}
+}
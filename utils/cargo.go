@@ -0,0 +1,42 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"os/exec"
+	"path"
+	"regexp"
+
+	"jrmd.dev/qk/types"
+)
+
+// cargoWorkspace matches the [workspace] table of a Cargo.toml.
+var cargoWorkspace = regexp.MustCompile(`(?m)^\s*\[workspace\]`)
+
+// HasCargo matches rust crates and workspaces.
+func HasCargo(project types.Project) bool {
+	exists, _ := project.FS.FileExists(path.Join(project.Dir, "Cargo.toml"))
+	return exists
+}
+
+// IsCargoWorkspaceMember matches crates below a Cargo.toml declaring a
+// workspace, cargo in the workspace root covers them already.
+func IsCargoWorkspaceMember(project types.Project) bool {
+	for dir := path.Dir(project.Dir); dir != path.Dir(dir); dir = path.Dir(dir) {
+		if manifest, err := project.FS.ReadFile(path.Join(dir, "Cargo.toml")); err == nil && cargoWorkspace.Match(manifest) {
+			return true
+		}
+		if project.FS.Exists(path.Join(dir, ".git")) {
+			break
+		}
+	}
+
+	return false
+}
+
+// HasCargoWatch matches every project when cargo-watch is installed.
+func HasCargoWatch(project types.Project) bool {
+	_, err := exec.LookPath("cargo-watch")
+	return err == nil
+}
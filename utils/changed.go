@@ -0,0 +1,105 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+)
+
+// ChangedFiles lists the files of the repository at root changed since the
+// merge-base of base and HEAD, whether committed, uncommitted or untracked,
+// relative to root. An empty base is the default branch, as origin knows it
+// when it can so shallow CI checkouts work too.
+func ChangedFiles(root string, base string) ([]string, error) {
+	if base == "" {
+		if ref, err := git(root, "symbolic-ref", "--short", "refs/remotes/origin/HEAD"); err == nil {
+			base = ref
+		} else if base = DefaultBranch(root); base == "" {
+			return nil, fmt.Errorf("%s has no main or master branch, pass --changed=<ref>", root)
+		}
+	}
+
+	mergeBase, err := git(root, "merge-base", base, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("no merge-base of %s and HEAD in %s", base, root)
+	}
+
+	diff, err := git(root, "diff", "--name-only", "--no-renames", mergeBase)
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := git(root, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, file := range strings.Split(diff+"\n"+untracked, "\n") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
+
+// ChangedProjects keeps the projects with files changed since base, see
+// ChangedFiles. A file counts for the deepest project containing it, so a
+// change inside a package doesn't mark the workspace root around it as
+// changed. Projects outside a git repository are always kept.
+func ChangedProjects(projects []File, base string) ([]File, error) {
+	changedDirs := map[string]bool{}
+	repos := map[string][]string{}
+
+	for _, project := range projects {
+		root, ok := GitRoot(project.Dir)
+		if !ok {
+			slog.Debug("keeping project outside a git repository", "name", project.Name, "dir", project.Dir)
+			changedDirs[project.Dir] = true
+			continue
+		}
+
+		if _, seen := repos[root]; seen {
+			continue
+		}
+
+		files, err := ChangedFiles(root, base)
+		if err != nil {
+			return nil, err
+		}
+		repos[root] = files
+	}
+
+	for root, files := range repos {
+		for _, file := range files {
+			owner := ""
+			for _, project := range projects {
+				if inDir(path.Join(root, file), project.Dir) && len(project.Dir) > len(owner) {
+					owner = project.Dir
+				}
+			}
+			if owner != "" {
+				changedDirs[owner] = true
+			}
+		}
+	}
+
+	changed := []File{}
+	for _, project := range projects {
+		if changedDirs[project.Dir] {
+			changed = append(changed, project)
+		} else {
+			slog.Debug("skipping unchanged project", "name", project.Name, "dir", project.Dir)
+		}
+	}
+
+	return changed, nil
+}
+
+func inDir(file string, dir string) bool {
+	return strings.HasPrefix(file, strings.TrimSuffix(dir, "/")+"/")
+}
@@ -0,0 +1,145 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	"jrmd.dev/qk/types"
+)
+
+// COMPOSE_FILES are the files docker compose reads from a project's
+// directory, in the order it looks for them.
+var COMPOSE_FILES = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// ComposeFile returns the compose file in dir.
+func ComposeFile(dir string) (string, bool) {
+	for _, name := range COMPOSE_FILES {
+		if exists, _ := FileExists(path.Join(dir, name)); exists {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+func HasComposeFile(project types.Project) bool {
+	_, ok := ComposeFile(project.Dir)
+	return ok
+}
+
+// ComposeCommand is the compose binary and the arguments selecting it:
+// docker compose, or the standalone docker-compose without docker.
+func ComposeCommand() (string, []string) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		if _, err := exec.LookPath("docker-compose"); err == nil {
+			return "docker-compose", []string{}
+		}
+	}
+
+	return "docker", []string{"compose"}
+}
+
+// ComposeArgs prefixes args with the arguments ComposeCommand needs.
+func ComposeArgs(args ...string) (string, []string) {
+	bin, prefix := ComposeCommand()
+	return bin, append(prefix, args...)
+}
+
+// ComposeContainer is a container of docker compose ps.
+type ComposeContainer struct {
+	Service string `json:"Service"`
+	State   string `json:"State"`
+	// Health is healthy, unhealthy or starting, empty without a health
+	// check.
+	Health string `json:"Health"`
+}
+
+// ContainerState counts the containers of a project's compose services.
+type ContainerState struct {
+	Total     int `json:"total"`
+	Running   int `json:"running"`
+	Unhealthy int `json:"unhealthy"`
+	Starting  int `json:"starting"`
+}
+
+// String renders the state as how many containers are up, followed by the
+// unhealthy ones and the ones whose health check hasn't passed yet.
+func (s ContainerState) String() string {
+	if s.Total == 0 {
+		return "no containers"
+	}
+
+	parts := []string{fmt.Sprintf("%d/%d up", s.Running, s.Total)}
+	if s.Unhealthy > 0 {
+		parts = append(parts, fmt.Sprintf("%d unhealthy", s.Unhealthy))
+	}
+	if s.Starting > 0 {
+		parts = append(parts, fmt.Sprintf("%d starting", s.Starting))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// ProjectContainers reads the state of the containers of dir's compose
+// project, ok is false when compose can't tell.
+func ProjectContainers(dir string) (ContainerState, bool) {
+	bin, args := ComposeArgs("ps", "--all", "--format", "json")
+	cmd := exec.Command(bin, args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ContainerState{}, false
+	}
+
+	containers, err := parseComposePs(out)
+	if err != nil {
+		return ContainerState{}, false
+	}
+
+	state := ContainerState{Total: len(containers)}
+	for _, c := range containers {
+		if c.State == "running" {
+			state.Running++
+		}
+		switch c.Health {
+		case "unhealthy":
+			state.Unhealthy++
+		case "starting":
+			state.Starting++
+		}
+	}
+
+	return state, true
+}
+
+// parseComposePs reads docker compose ps --format json, a JSON array before
+// compose 2.21 and a JSON object per line since.
+func parseComposePs(out []byte) ([]ComposeContainer, error) {
+	out = bytes.TrimSpace(out)
+	containers := []ComposeContainer{}
+	if len(out) == 0 {
+		return containers, nil
+	}
+
+	if out[0] == '[' {
+		err := json.Unmarshal(out, &containers)
+		return containers, err
+	}
+
+	for _, line := range bytes.Split(out, []byte("\n")) {
+		c := ComposeContainer{}
+		if err := json.Unmarshal(line, &c); err != nil {
+			return nil, err
+		}
+		containers = append(containers, c)
+	}
+
+	return containers, nil
+}
@@ -0,0 +1,54 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseComposePs(t *testing.T) {
+	web := ComposeContainer{Service: "web", State: "running", Health: "healthy"}
+	db := ComposeContainer{Service: "db", State: "exited"}
+
+	tests := []struct {
+		name    string
+		out     string
+		want    []ComposeContainer
+		wantErr bool
+	}{
+		{
+			name: "array before compose 2.21",
+			out:  `[{"Service":"web","State":"running","Health":"healthy"},{"Service":"db","State":"exited","Health":""}]`,
+			want: []ComposeContainer{web, db},
+		},
+		{
+			name: "object per line",
+			out:  "{\"Service\":\"web\",\"State\":\"running\",\"Health\":\"healthy\"}\n{\"Service\":\"db\",\"State\":\"exited\"}\n",
+			want: []ComposeContainer{web, db},
+		},
+		{
+			name: "no containers",
+			out:  "\n",
+			want: []ComposeContainer{},
+		},
+		{
+			name:    "not json",
+			out:     "no configuration file provided: not found",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseComposePs([]byte(tt.out))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseComposePs error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !slices.Equal(got, tt.want) {
+				t.Errorf("parseComposePs = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
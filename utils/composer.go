@@ -0,0 +1,82 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"encoding/json"
+	"path"
+	"slices"
+	"strings"
+
+	"jrmd.dev/qk/fsys"
+	"jrmd.dev/qk/types"
+)
+
+// composerScripts reads the scripts of the composer.json in dir. A script
+// is a command or a list of commands, each kept as a list here.
+func composerScripts(fsys fsys.FS, dir string) map[string][]string {
+	scripts := map[string][]string{}
+	file, err := fsys.ReadFile(path.Join(dir, "composer.json"))
+	if err != nil {
+		return scripts
+	}
+
+	manifest := struct {
+		Scripts map[string]json.RawMessage `json:"scripts"`
+	}{}
+	_ = json.Unmarshal(file, &manifest)
+	for name, raw := range manifest.Scripts {
+		var single string
+		if err := json.Unmarshal(raw, &single); err == nil {
+			scripts[name] = []string{single}
+			continue
+		}
+
+		var list []string
+		if err := json.Unmarshal(raw, &list); err == nil {
+			scripts[name] = list
+		}
+	}
+
+	return scripts
+}
+
+// HasComposerScript matches projects whose composer.json defines script.
+func HasComposerScript(script string) func(p types.Project) bool {
+	return func(project types.Project) bool {
+		_, exists := GetComposerScript(project.FS, project.Dir, script)
+
+		return exists
+	}
+}
+
+// GetComposerScript returns the body of a composer.json script in dir, the
+// commands of a list joined by && as composer stops at the first failing one.
+func GetComposerScript(fsys fsys.FS, dir string, script string) (string, bool) {
+	commands, exists := composerScripts(fsys, dir)[script]
+
+	return strings.Join(commands, " && "), exists
+}
+
+// GetComposerScripts returns the sorted names of the composer.json scripts
+// in dir.
+func GetComposerScripts(fsys fsys.FS, dir string) []string {
+	scripts := []string{}
+	for name := range composerScripts(fsys, dir) {
+		scripts = append(scripts, name)
+	}
+	slices.Sort(scripts)
+
+	return scripts
+}
+
+// ComposerRunArgs returns the composer arguments running script with args,
+// which are passed after -- so composer doesn't take them as its options.
+func ComposerRunArgs(script string, args ...string) []string {
+	if len(args) == 0 {
+		return []string{"run-script", script}
+	}
+
+	return append([]string{"run-script", script, "--"}, args...)
+}
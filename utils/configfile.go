@@ -0,0 +1,204 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"jrmd.dev/qk/discovery"
+	"jrmd.dev/qk/fsys"
+	"jrmd.dev/qk/types"
+)
+
+// ConfigFile is the name of both the global and the repo-level config.
+const ConfigFile = ".qk.json"
+
+// GlobalConfigPath returns ~/.qk.json.
+func GlobalConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return path.Join(home, ConfigFile)
+}
+
+// LocalConfigPath returns the nearest .qk.json above the working directory,
+// other than the global one, or "" when there is none.
+func LocalConfigPath() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for dir := wd; ; dir = path.Dir(dir) {
+		file := path.Join(dir, ConfigFile)
+		if file == GlobalConfigPath() {
+			return ""
+		}
+		if _, err := fsys.OS.Stat(file); err == nil {
+			return file
+		}
+		if path.Dir(dir) == dir {
+			return ""
+		}
+	}
+}
+
+// ReadConfigFile reads a config file as raw JSON values, so unknown keys
+// survive being written back.
+func ReadConfigFile(file string) (map[string]any, error) {
+	values := map[string]any{}
+	data, err := fsys.OS.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return values, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return values, nil
+}
+
+// WriteConfigFile validates values and writes them to file.
+func WriteConfigFile(file string, values map[string]any) error {
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := ValidateConfig(data); err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, append(data, '\n'), 0o644)
+}
+
+// ConfigKey resolves a key, matched case-insensitively, to the name of a
+// Config field.
+func ConfigKey(key string) (string, bool) {
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		if strings.EqualFold(t.Field(i).Name, key) {
+			return t.Field(i).Name, true
+		}
+	}
+
+	return "", false
+}
+
+// ConfigKeys lists every Config field name.
+func ConfigKeys() []string {
+	keys := []string{}
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		keys = append(keys, t.Field(i).Name)
+	}
+
+	return keys
+}
+
+// ParseConfigValue reads a value given on the command line for key. JSON is
+// accepted for every type, plain text for string fields and comma separated
+// lists for string lists.
+func ParseConfigValue(key string, raw string) (any, error) {
+	field, _ := reflect.TypeOf(Config{}).FieldByName(key)
+
+	var value any
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		switch {
+		case field.Type.Kind() == reflect.String:
+			value = raw
+		case field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.String:
+			value = strings.Split(raw, ",")
+		default:
+			return nil, fmt.Errorf("%s expects %s, got %q", key, field.Type, raw)
+		}
+	}
+
+	// a bare word like `true` for a string field stays a string
+	if field.Type.Kind() == reflect.String {
+		if _, ok := value.(string); !ok {
+			value = raw
+		}
+	}
+
+	return value, nil
+}
+
+// ValidateConfig checks that data decodes into a Config with sensible
+// values.
+func ValidateConfig(data []byte) error {
+	cfg := Config{}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	if err := decoder.Decode(&cfg); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return fmt.Errorf("%s expects %s", typeErr.Field, typeErr.Type)
+		}
+		return err
+	}
+
+	if cfg.Detect != "" {
+		switch cfg.Detect {
+		case discovery.ModeNode, discovery.ModePHP, discovery.ModeBoth, discovery.ModeAny:
+		default:
+			return fmt.Errorf("Detect must be node, php, both or any, got %q", cfg.Detect)
+		}
+	}
+
+	if cfg.AuditLevel != "" && !slices.Contains(SEVERITIES, cfg.AuditLevel) {
+		return fmt.Errorf("AuditLevel must be one of %s, got %q", strings.Join(SEVERITIES, ", "), cfg.AuditLevel)
+	}
+
+	if cfg.PortRange != "" {
+		if _, _, err := ParsePortRange(cfg.PortRange); err != nil {
+			return fmt.Errorf("PortRange: %w", err)
+		}
+	}
+
+	for name, profile := range cfg.Profiles {
+		if err := ValidateConfig(profile); err != nil {
+			return fmt.Errorf("Profiles.%s: %w", name, err)
+		}
+	}
+
+	if err := ValidateNotify(cfg.Notify); err != nil {
+		return err
+	}
+
+	if err := ValidateProjectTypes(cfg.ProjectTypes); err != nil {
+		return err
+	}
+
+	if _, err := ConfiguredTheme(cfg); err != nil {
+		return err
+	}
+
+	if _, ok := types.SPINNERS[cfg.Spinner]; cfg.Spinner != "" && !ok {
+		return fmt.Errorf("Spinner must be one of %s, got %q", strings.Join(types.SpinnerNames(), ", "), cfg.Spinner)
+	}
+
+	for key, value := range map[string]string{"GracePeriod": cfg.GracePeriod, "MaxRefresh": cfg.MaxRefresh} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("%s must be a duration such as 5s, got %q", key, value)
+		}
+	}
+
+	return nil
+}
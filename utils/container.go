@@ -0,0 +1,70 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"jrmd.dev/qk/types"
+)
+
+// ContainerImage returns the image configured for service in Images.
+func ContainerImage(conf Config, service string) (string, bool) {
+	image, ok := conf.Images[service]
+	return image, ok && image != ""
+}
+
+// CheckContainer makes sure every project can run commands in service's
+// container: through its compose file, or an image configured for service.
+func CheckContainer(conf Config, service string, projects []types.Project) error {
+	if _, ok := ContainerImage(conf, service); ok {
+		return nil
+	}
+
+	missing := []string{}
+	for _, project := range projects {
+		if !HasComposeFile(project) {
+			missing = append(missing, project.Name)
+		}
+	}
+	switch {
+	case len(missing) == 1:
+		return fmt.Errorf("%s has no compose file, configure an image for %s in Images to docker run it", missing[0], service)
+	case len(missing) > 1:
+		return fmt.Errorf("%s have no compose file, configure an image for %s in Images to docker run them", strings.Join(missing, ", "), service)
+	}
+
+	return nil
+}
+
+// InContainer rewrites a command to run in service's container instead of
+// on the host, with env passed on. A compose project runs it with docker
+// compose exec, others with docker run of the image configured for
+// service, with dir mounted at the same path.
+func InContainer(conf Config, service string, dir string, env []string, tty bool, script string, args []string) (string, []string) {
+	flags := []string{}
+	for _, pair := range env {
+		// the host's PATH means nothing in the container
+		if !strings.HasPrefix(pair, "PATH=") {
+			flags = append(flags, "--env", pair)
+		}
+	}
+	command := append([]string{script}, args...)
+
+	if _, ok := ComposeFile(dir); ok {
+		if !tty {
+			flags = append(flags, "-T")
+		}
+		return ComposeArgs(append(append(append([]string{"exec"}, flags...), service), command...)...)
+	}
+
+	image, _ := ContainerImage(conf, service)
+	run := []string{"run", "--rm", "--volume", dir + ":" + dir, "--workdir", dir}
+	if tty {
+		run = append(run, "--tty")
+	}
+
+	return "docker", append(append(append(run, flags...), image), command...)
+}
@@ -0,0 +1,35 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import "regexp"
+
+// CorruptionSignature recognises a cache corruption error in a tool's output
+// and the command which clears that tool's cache.
+type CorruptionSignature struct {
+	Tool    string
+	Pattern *regexp.Regexp
+	Clear   []string
+}
+
+// CORRUPTION_SIGNATURES are the cache corruption errors qk remediates by
+// clearing the cache and running the command again.
+var CORRUPTION_SIGNATURES = []CorruptionSignature{
+	{Tool: "npm", Pattern: regexp.MustCompile(`EINTEGRITY`), Clear: []string{"npm", "cache", "clean", "--force"}},
+	{Tool: "yarn", Pattern: regexp.MustCompile(`(?i)integrity check failed|incorrect integrity`), Clear: []string{"yarn", "cache", "clean"}},
+	{Tool: "pnpm", Pattern: regexp.MustCompile(`ERR_PNPM_TARBALL_INTEGRITY`), Clear: []string{"pnpm", "store", "prune"}},
+	{Tool: "bun", Pattern: regexp.MustCompile(`(?i)integrity check failed`), Clear: []string{"bun", "pm", "cache", "rm"}},
+	{Tool: "composer", Pattern: regexp.MustCompile(`(?i)corrupt(ed)? zip|zip archive.*corrupt|not a valid zip`), Clear: []string{"composer", "clear-cache"}},
+}
+
+// MatchCorruption finds the corruption signature of tool matching output.
+func MatchCorruption(tool string, output string) (CorruptionSignature, bool) {
+	for _, signature := range CORRUPTION_SIGNATURES {
+		if signature.Tool == tool && signature.Pattern.MatchString(output) {
+			return signature, true
+		}
+	}
+
+	return CorruptionSignature{}, false
+}
@@ -0,0 +1,30 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"regexp"
+)
+
+// DANGEROUS_PATTERNS are regular expressions matching commands which ask for
+// confirmation before qk cmd runs them, on top of the configured
+// DangerousPatterns. They are matched case-insensitively.
+var DANGEROUS_PATTERNS = []string{`\brm\b`, `\bdrop\b`, `\breset\b`, `\btruncate\b`, `\bgit\s+clean\b`}
+
+// DangerousMatch returns the first dangerous pattern command matches.
+// Invalid configured patterns are ignored.
+func DangerousMatch(conf Config, command string) (string, bool) {
+	for _, pattern := range append(append([]string{}, DANGEROUS_PATTERNS...), conf.DangerousPatterns...) {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			continue
+		}
+
+		if re.MatchString(command) {
+			return pattern, true
+		}
+	}
+
+	return "", false
+}
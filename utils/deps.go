@@ -0,0 +1,85 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"encoding/json"
+	"path"
+	"slices"
+
+	"jrmd.dev/qk/fsys"
+)
+
+type packageDependencies struct {
+	Name            string            `json:"name"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+	Require         map[string]string `json:"require"`
+	RequireDev      map[string]string `json:"require-dev"`
+}
+
+func readManifest(fsys fsys.FS, dir string, name string) packageDependencies {
+	manifest := packageDependencies{}
+	file, err := fsys.ReadFile(path.Join(dir, name))
+	if err != nil {
+		return manifest
+	}
+	_ = json.Unmarshal(file, &manifest)
+
+	return manifest
+}
+
+// PackageNames returns the names a project is published under in its
+// package.json and composer.json.
+func PackageNames(fsys fsys.FS, dir string) []string {
+	names := []string{}
+	for _, manifest := range []string{"package.json", "composer.json"} {
+		if name := readManifest(fsys, dir, manifest).Name; name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// LocalDependencies returns the names of the other projects that a project
+// depends on, matched by package name.
+func LocalDependencies(project File, projects []File) []string {
+	wanted := map[string]bool{}
+	for _, manifest := range []string{"package.json", "composer.json"} {
+		deps := readManifest(project.FS, project.Dir, manifest)
+		for _, group := range []map[string]string{deps.Dependencies, deps.DevDependencies, deps.Require, deps.RequireDev} {
+			for name := range group {
+				wanted[name] = true
+			}
+		}
+	}
+
+	local := []string{}
+	for _, other := range projects {
+		if other.Dir == project.Dir {
+			continue
+		}
+
+		if slices.ContainsFunc(PackageNames(other.FS, other.Dir), func(name string) bool { return wanted[name] }) {
+			local = append(local, other.Name)
+		}
+	}
+
+	return local
+}
+
+// ProjectDependencies returns the names of the projects a project has to
+// wait for, inferred from its manifests and extended by the Dependencies
+// config.
+func ProjectDependencies(conf Config, project File, projects []File) []string {
+	deps := LocalDependencies(project, projects)
+	for _, name := range conf.Dependencies[project.Name] {
+		if !slices.Contains(deps, name) && slices.ContainsFunc(projects, func(other File) bool { return other.Name == name }) {
+			deps = append(deps, name)
+		}
+	}
+
+	return deps
+}
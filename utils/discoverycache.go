@@ -0,0 +1,164 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"strings"
+
+	"jrmd.dev/qk/fsys"
+)
+
+// DISCOVERY_MANIFESTS are the files whose contents, not just existence,
+// change which projects are found, so their mtimes are cached too.
+var DISCOVERY_MANIFESTS = []string{"package.json", "pnpm-workspace.yaml", ".gitignore"}
+
+var discoveryCache = struct {
+	enabled bool
+	variant string
+}{}
+
+// UseDiscoveryCache makes GetAllProjects reuse the projects found on a
+// previous run until a directory they were found in changes. variant
+// describes the detection settings, results found with other settings are
+// not reused.
+func UseDiscoveryCache(enabled bool, variant string) {
+	discoveryCache.enabled = enabled
+	discoveryCache.variant = variant
+}
+
+type discoveryEntry struct {
+	Projects []File
+	// Mtimes are the modification times of the walked directories and
+	// their manifests in nanoseconds.
+	Mtimes map[string]int64
+}
+
+func discoveryCacheFile() (string, error) {
+	return stateFile("discovery.json")
+}
+
+// readDiscoveryCache reads the cache from qk's state on the real filesystem,
+// whichever filesystem is scanned.
+func readDiscoveryCache() map[string]discoveryEntry {
+	cache := map[string]discoveryEntry{}
+
+	file, err := discoveryCacheFile()
+	if err != nil {
+		return cache
+	}
+
+	data, err := fsys.OS.ReadFile(file)
+	if err != nil {
+		return cache
+	}
+
+	_ = json.Unmarshal(data, &cache)
+	return cache
+}
+
+func writeDiscoveryCache(cache map[string]discoveryEntry) error {
+	file, err := discoveryCacheFile()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(path.Dir(file), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, data, 0o644)
+}
+
+// ClearDiscoveryCache forgets every cached discovery result.
+func ClearDiscoveryCache() error {
+	file, err := discoveryCacheFile()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func mtime(fsys fsys.FS, name string) (int64, bool) {
+	info, err := fsys.Stat(name)
+	if err != nil {
+		return 0, false
+	}
+
+	return info.ModTime().UnixNano(), true
+}
+
+// fresh reports whether none of the paths recorded for entry changed.
+func (entry discoveryEntry) fresh(fsys fsys.FS) bool {
+	if len(entry.Mtimes) == 0 {
+		return false
+	}
+
+	for name, recorded := range entry.Mtimes {
+		if current, ok := mtime(fsys, name); !ok || current != recorded {
+			return false
+		}
+	}
+
+	return true
+}
+
+// cached returns the cached projects under the scan's root, walking the tree
+// again and updating the cache when anything changed.
+func (s *scan) cached(depth int) []File {
+	key := fmt.Sprintf("%s|%d|%s|%s|%t", s.root, depth, discoveryCache.variant, strings.Join(s.ignore.Patterns(), ","), useGitignore)
+	cache := readDiscoveryCache()
+	if entry, ok := cache[key]; ok && entry.fresh(s.fsys) {
+		slog.Debug("reusing cached discovery", "root", s.root, "projects", len(entry.Projects))
+		for i := range entry.Projects {
+			entry.Projects[i].FS = s.fsys
+		}
+		return entry.Projects
+	}
+	slog.Debug("walking the directory tree", "root", s.root, "depth", depth)
+
+	mtimes := map[string]int64{}
+	s.visit = func(dir string) {
+		if _, seen := mtimes[dir]; seen {
+			return
+		}
+		if modified, ok := mtime(s.fsys, dir); ok {
+			mtimes[dir] = modified
+		}
+		for _, manifest := range DISCOVERY_MANIFESTS {
+			if modified, ok := mtime(s.fsys, path.Join(dir, manifest)); ok {
+				mtimes[path.Join(dir, manifest)] = modified
+			}
+		}
+	}
+
+	// a .gitignore added or edited above root changes the result too,
+	// .qkignore files are part of the key
+	for _, dir := range s.ancestors {
+		s.visit(dir)
+	}
+
+	projects := s.walk(s.root, depth, 0)
+	if s.err != nil {
+		return projects
+	}
+	cache[key] = discoveryEntry{Projects: projects, Mtimes: mtimes}
+	_ = writeDiscoveryCache(cache)
+
+	return projects
+}
@@ -0,0 +1,60 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"bufio"
+	"path"
+	"strings"
+
+	"jrmd.dev/qk/fsys"
+)
+
+var DOTENV_FILES = []string{".env", ".env.local"}
+
+// LoadDotenv reads the dotenv files in dir and returns their variables as
+// KEY=VALUE pairs, later files overriding earlier ones.
+func LoadDotenv(fsys fsys.FS, dir string) []string {
+	pairs := []string{}
+	for _, name := range DOTENV_FILES {
+		pairs = append(pairs, parseDotenv(fsys, path.Join(dir, name))...)
+	}
+
+	return pairs
+}
+
+func parseDotenv(fsys fsys.FS, file string) []string {
+	pairs := []string{}
+	f, err := fsys.Open(file)
+	if err != nil {
+		return pairs
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		} else if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+
+		pairs = append(pairs, key+"="+value)
+	}
+
+	return pairs
+}
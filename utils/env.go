@@ -0,0 +1,24 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"maps"
+	"slices"
+)
+
+// ProjectEnv returns the configured environment for a project as KEY=VALUE
+// pairs, with per-project values overriding the global ones.
+func ProjectEnv(conf Config, project string) []string {
+	env := map[string]string{}
+	maps.Copy(env, conf.Env)
+	maps.Copy(env, conf.ProjectEnv[project])
+
+	pairs := []string{}
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		pairs = append(pairs, key+"="+env[key])
+	}
+
+	return pairs
+}
@@ -0,0 +1,75 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ErrorPattern recognises the first line of an error a tool prints, the
+// Context lines after it are part of the error.
+type ErrorPattern struct {
+	Tool    string
+	Pattern *regexp.Regexp
+	Context int
+}
+
+// ERROR_PATTERNS are the errors shown under a failed command, so the cause
+// of a failure can be read without its whole output.
+var ERROR_PATTERNS = []ErrorPattern{
+	{Tool: "typescript", Pattern: regexp.MustCompile(`\berror TS\d+:`), Context: 1},
+	{Tool: "webpack", Pattern: regexp.MustCompile(`^ERROR in `), Context: 3},
+	{Tool: "esbuild", Pattern: regexp.MustCompile(`^\s*[✘X] \[ERROR\]`), Context: 4},
+	{Tool: "vite", Pattern: regexp.MustCompile(`^error during build:|^\[vite\]: Rollup failed|RollupError:`), Context: 3},
+	{Tool: "eslint", Pattern: regexp.MustCompile(`^\s+\d+:\d+\s+error\s`), Context: 0},
+	{Tool: "php", Pattern: regexp.MustCompile(`(PHP )?(Fatal|Parse) error:|PHP Warning:|Uncaught [\w\\]+(Exception|Error)`), Context: 2},
+	{Tool: "composer", Pattern: regexp.MustCompile(`Your requirements could not be resolved|Problem \d+$`), Context: 3},
+	{Tool: "go", Pattern: regexp.MustCompile(`^(\./)?[\w./-]+\.go:\d+:\d+: `), Context: 0},
+	{Tool: "go test", Pattern: regexp.MustCompile(`^\s*--- FAIL: `), Context: 2},
+	{Tool: "rust", Pattern: regexp.MustCompile(`^error(\[E\d+\])?: `), Context: 1},
+	{Tool: "node", Pattern: regexp.MustCompile(`^(\w+)?Error: |^Error \[ERR_\w+\]`), Context: 2},
+}
+
+// ExtractErrors finds the errors matching ERROR_PATTERNS in output, each
+// with its context lines, keeping at most limit of them. Repeated errors
+// are only kept once.
+func ExtractErrors(output string, limit int) []string {
+	lines := strings.Split(ansi.Strip(strings.ReplaceAll(output, "\r\n", "\n")), "\n")
+
+	snippets := []string{}
+	for i := 0; i < len(lines) && len(snippets) < limit; i++ {
+		j := errorPattern(lines[i])
+		if j == -1 {
+			continue
+		}
+
+		end := min(i+1+ERROR_PATTERNS[j].Context, len(lines))
+		snippet := []string{strings.TrimRight(lines[i], " \t")}
+		for _, line := range lines[i+1 : end] {
+			// a blank line or the next error ends the error before its
+			// context does
+			if strings.TrimSpace(line) == "" || errorPattern(line) != -1 {
+				break
+			}
+			snippet = append(snippet, strings.TrimRight(line, " \t"))
+		}
+
+		if text := strings.Join(snippet, "\n"); !slices.Contains(snippets, text) {
+			snippets = append(snippets, text)
+		}
+		i += len(snippet) - 1
+	}
+
+	return snippets
+}
+
+func errorPattern(line string) int {
+	return slices.IndexFunc(ERROR_PATTERNS, func(pattern ErrorPattern) bool {
+		return pattern.Pattern.MatchString(line)
+	})
+}
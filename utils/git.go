@@ -0,0 +1,83 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"path"
+	"strings"
+
+	"jrmd.dev/qk/types"
+)
+
+// GitRoot walks up from dir to find the enclosing git repository.
+func GitRoot(dir string) (string, bool) {
+	for {
+		if exists, _ := FileExists(path.Join(dir, ".git")); exists {
+			return dir, true
+		}
+
+		parent := path.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// GitState is the checked out branch of a project's repository and whether
+// the project's files have uncommitted changes.
+type GitState struct {
+	Branch string `json:"branch"`
+	Dirty  bool   `json:"dirty"`
+}
+
+// String renders the state as the branch, marked with * when dirty.
+func (s GitState) String() string {
+	if s.Dirty {
+		return s.Branch + "*"
+	}
+
+	return s.Branch
+}
+
+// ProjectGitState reads the branch of dir's repository and whether anything
+// below dir changed, ok is false outside of a repository.
+func ProjectGitState(dir string) (GitState, bool) {
+	out, err := git(dir, "status", "--porcelain=v1", "--branch", "--", ".")
+	if err != nil {
+		return GitState{}, false
+	}
+
+	lines := strings.Split(out, "\n")
+	state := GitState{Dirty: len(lines) > 1}
+
+	// ## main...origin/main [ahead 1], ## No commits yet on main or
+	// ## HEAD (no branch) when detached
+	header := strings.TrimPrefix(lines[0], "## ")
+	header = strings.TrimPrefix(header, "No commits yet on ")
+	header, _, _ = strings.Cut(header, "...")
+	header, _, _ = strings.Cut(header, " ")
+	state.Branch = header
+	if header == "HEAD" {
+		if sha, err := git(dir, "rev-parse", "--short", "HEAD"); err == nil {
+			state.Branch = sha
+		}
+	}
+
+	return state, true
+}
+
+// FirstInRepo returns a predicate matching only the first project seen in
+// each git repository, so repo-wide operations run once per repo.
+func FirstInRepo() func(types.Project) bool {
+	seen := map[string]bool{}
+	return func(project types.Project) bool {
+		root, ok := GitRoot(project.Dir)
+		if !ok || seen[root] {
+			return false
+		}
+		seen[root] = true
+		return true
+	}
+}
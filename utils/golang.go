@@ -0,0 +1,16 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"path"
+
+	"jrmd.dev/qk/types"
+)
+
+// HasGoMod matches go modules.
+func HasGoMod(project types.Project) bool {
+	exists, _ := project.FS.FileExists(path.Join(project.Dir, "go.mod"))
+	return exists
+}
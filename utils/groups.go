@@ -0,0 +1,31 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"path"
+	"slices"
+)
+
+// ProjectGroup returns the group a project is listed under: the first
+// configured group naming it, otherwise the workspace it belongs to.
+func ProjectGroup(conf Config, project File) string {
+	names := []string{}
+	for name := range conf.Groups {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if slices.Contains(conf.Groups[name], project.Name) {
+			return name
+		}
+	}
+
+	if project.Workspace != "" {
+		return path.Base(project.Workspace)
+	}
+
+	return ""
+}
@@ -5,63 +5,301 @@ package utils
 
 import (
 	"encoding/json"
-	"errors"
 	"log"
+	"log/slog"
 	"os"
 	"path"
 	"slices"
 
+	"jrmd.dev/qk/discovery"
+	"jrmd.dev/qk/fsys"
 	"jrmd.dev/qk/types"
 )
 
 type File struct {
 	Name string
 	Dir  string
+	// FS is the filesystem the project was found in.
+	FS fsys.FS `json:"-"`
+	// Workspace is the root directory of the yarn, npm or pnpm workspace
+	// the project belongs to, the root itself included.
+	Workspace string
 }
 
 type Config struct {
 	ShowTimer   bool
 	ShowScripts bool
 	ShowStdout  bool
+	Ports       map[string]int
+	// Detect selects which manifests make a project: node, php, both or any.
+	Detect string
+	// Markers are extra files, such as go.mod, that make a project.
+	Markers []string
+	LogDir  string
+	// KeepLogs is how many runs keep their log directories under LogDir,
+	// the oldest are removed once a run finishes. 50 by default, negative
+	// values keep them all.
+	KeepLogs int
+	// GracePeriod is a duration such as "5s" given to commands to shut
+	// down before they are killed.
+	GracePeriod string
+	// MaxRefresh is a duration such as "1s" capping how slowly the TUI
+	// refreshes while nothing is happening.
+	MaxRefresh string
+	// Env is set on every spawned command, ProjectEnv overrides it per
+	// project name.
+	Env        map[string]string
+	ProjectEnv map[string]map[string]string
+	// MaxLineRate and MaxOutputBytes bound the output of flooding
+	// commands, negative values disable the limit.
+	MaxLineRate    int
+	MaxOutputBytes int
+	// FailureLines is how many lines of each failed command's output are
+	// printed once a run is done, 20 by default, negative values print
+	// none.
+	FailureLines int
+	// Concurrency caps how many commands run at once, like --concurrency.
+	// Long-running commands such as dev servers keep their slot, so it
+	// suits builds and installs best.
+	Concurrency int
+	// Dependencies maps a project name to the projects it has to wait for,
+	// on top of the ones inferred from its package.json and composer.json.
+	Dependencies map[string][]string
+	// Groups maps a group name to the projects listed under it.
+	Groups map[string][]string
+	// Tags maps a tag to the projects, or name patterns such as "app-*",
+	// carrying it. Unlike groups a project can have many tags, --tag runs
+	// the projects with any of the given ones.
+	Tags map[string][]string
+	// WatchIgnore are extra patterns, such as "*.cache", ignored by
+	// watch --restart-on-change.
+	WatchIgnore []string
+	// ToolFlags appends arguments to matching commands, keyed by the tool
+	// and optionally its leading arguments, e.g. "composer install".
+	ToolFlags map[string][]string
+	// Blacklist are directory names or .qkignore style patterns skipped
+	// during discovery on top of BLACKLIST.
+	Blacklist []string
+	// Skip names the projects left out of every run while still listed by
+	// qk ls, kept in the repo-level config by qk skip and qk unskip.
+	Skip []string
+	// Gitignore skips the directories ignored by .gitignore files during
+	// discovery, like --gitignore.
+	Gitignore bool
+	// Primary maps a project name, or "*" for every project, to the URL
+	// opened or command run by pressing o in the runner. Template
+	// variables such as {{.Name}} are expanded.
+	Primary map[string]string
+	// NotifyOnFailure is "bell" to ring the terminal bell, or a command,
+	// such as one playing a sound, run whenever a command fails.
+	NotifyOnFailure string
+	// Notify sends a summary of every run to a webhook, such as a Slack
+	// channel's, once it finishes.
+	Notify Notify
+	// AuditLevel is the lowest severity failing qk audit, like --fail-on.
+	AuditLevel string
+	// Hooks maps preInstall, postInstall, preBuild and postBuild to shell
+	// snippets run in each project before or after its commands.
+	Hooks map[string]string
+	// DangerousPatterns are extra regular expressions of commands qk cmd
+	// asks to confirm, on top of DANGEROUS_PATTERNS.
+	DangerousPatterns []string
+	// AssignPorts gives every project qk watch runs a PORT of its own, like
+	// --assign-ports, taken from PortRange such as "3000-3999" unless Ports
+	// configures one.
+	AssignPorts bool
+	PortRange   string
+	// Php maps a project name, or "*" for every project, to the php binary
+	// its commands run, such as "php8.1" or "/opt/php/8.1/bin/php".
+	Php map[string]string
+	// Images maps a service name given to --in-container to the image
+	// docker run uses for projects without a compose file, such as
+	// "composer:2".
+	Images map[string]string
+	// ProjectTypes adds kinds of projects by name, such as "mix" for
+	// Elixir, see ProjectTypeConfig.
+	ProjectTypes map[string]ProjectTypeConfig
+	// Theme is the built-in theme qk renders with, such as "nord", and
+	// ThemeColors replaces some of its colours, such as "Subtle": "#777777"
+	// or "Error": "#aa0000,#ff5555" for a light and a dark variant.
+	Theme       string
+	ThemeColors map[string]string
+	// Spinner is the spinner shown while a project's commands run, such as
+	// "line" when the font lacks the braille dots of the default "dot".
+	// CheckGlyph and CrossGlyph replace the ✓ and x shown once they finish
+	// or fail.
+	Spinner    string
+	CheckGlyph string
+	CrossGlyph string
+	// Flags are defaults for command line flags by name, such as
+	// "depth": 5, used unless the flag is given.
+	Flags map[string]any
+	// Profiles are named sets of config applied on top of the rest, by
+	// --profile or QK_PROFILE.
+	Profiles map[string]json.RawMessage
 }
 
 type PackageJSON struct {
 	Scripts map[string]string `json:"scripts"`
 }
 
+// GetConfig reads ~/.qk.json, then the repo-level .qk.json nearest to the
+// working directory on top of it, then the active profile on top of both.
 func GetConfig() Config {
-	cfg := Config{true, true, false}
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return cfg
-	}
+	cfg := Config{ShowTimer: true, ShowScripts: true, ShowStdout: false, Ports: map[string]int{}}
 
-	if ok, err := FileExists(path.Join(home, ".qk.json")); !ok || err != nil {
-		return cfg
-	}
+	for _, file := range []string{GlobalConfigPath(), LocalConfigPath()} {
+		if file == "" {
+			continue
+		}
 
-	conf, err := os.ReadFile(path.Join(home, ".qk.json"))
+		conf, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
 
-	if err != nil {
-		return cfg
+		if err := json.Unmarshal(conf, &cfg); err != nil {
+			slog.Warn("ignoring invalid config", "file", file, "err", err)
+		}
+	}
+
+	if profile, ok := cfg.Profiles[ActiveProfile()]; ok {
+		if err := json.Unmarshal(profile, &cfg); err != nil {
+			slog.Warn("ignoring invalid profile", "profile", ActiveProfile(), "err", err)
+		}
 	}
 
-	_ = json.Unmarshal(conf, &cfg)
 	return cfg
 }
 
 var BLACKLIST = []string{"node_modules", ".git", ".idea", "vendor"}
 
+// GetAllProjects finds the projects under dir on the real filesystem, see
+// GetAllProjectsIn.
 func GetAllProjects(dir string, depth int, level int) []File {
-	files, err := os.ReadDir(dir)
+	return GetAllProjectsIn(fsys.OS, dir, depth, level)
+}
+
+// GetAllProjectsIn finds the projects under dir in fsys, down to depth
+// levels below it or everywhere when depth is -1. It exits when a directory
+// can't be read, see FindProjects.
+func GetAllProjectsIn(fsys fsys.FS, dir string, depth int, level int) []File {
+	projects, err := findProjects(fsys, dir, depth, level)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	return projects
+}
+
+// FindProjects finds the projects under dir in fsys like GetAllProjectsIn,
+// returning the first directory that can't be read as an error.
+func FindProjects(fsys fsys.FS, dir string, depth int) ([]File, error) {
+	return findProjects(fsys, dir, depth, 0)
+}
+
+func findProjects(fsys fsys.FS, dir string, depth int, level int) ([]File, error) {
+	s := newScan(fsys, dir)
+	if level == 0 && discoveryCache.enabled {
+		projects := s.cached(depth)
+		return projects, s.err
+	}
+
+	projects := s.walk(dir, depth, level)
+	return projects, s.err
+}
+
+// scan holds what a single discovery walk needs besides its position.
+type scan struct {
+	fsys   fsys.FS
+	root   string
+	ignore IgnoreRules
+	// gitignores maps the walked directories with a .gitignore to its
+	// rules, which apply to everything below them.
+	gitignores map[string]IgnoreRules
+	// ancestors are the directories above root up to its repository's
+	// root, whose .gitignore files apply too.
+	ancestors []string
+	// visit is called with every directory whose contents the result
+	// depends on.
+	visit func(string)
+	// err is the first directory that couldn't be read.
+	err error
+}
+
+func newScan(fsys fsys.FS, root string) *scan {
+	s := &scan{fsys: fsys, root: root, ignore: LoadIgnore(fsys, GetConfig(), root), gitignores: map[string]IgnoreRules{}}
+
+	// the .gitignore files between root and its repository's root apply
+	// too, root's own is read by walk
+	for dir := root; dir != path.Dir(dir); dir = path.Dir(dir) {
+		if dir != root {
+			s.ancestors = append(s.ancestors, dir)
+			if rules, ok := loadGitignore(fsys, dir); ok {
+				s.gitignores[dir] = rules
+			}
+		}
+		if fsys.Exists(path.Join(dir, ".git")) {
+			break
+		}
+	}
+
+	return s
+}
+
+// skip reports whether discovery leaves dir alone, falling back to BLACKLIST
+// when there is no scan.
+func (s *scan) skip(dir string) bool {
+	if s == nil {
+		return slices.Contains(BLACKLIST, path.Base(dir))
+	}
+
+	if s.ignore.Ignored(dir) {
+		slog.Debug("skipping ignored directory", "dir", dir)
+		return true
+	}
+
+	for parent := path.Dir(dir); ; parent = path.Dir(parent) {
+		if rules, ok := s.gitignores[parent]; ok && rules.Ignored(dir) {
+			slog.Debug("skipping gitignored directory", "dir", dir, "gitignore", path.Join(parent, ".gitignore"))
+			return true
+		}
+		if path.Dir(parent) == parent {
+			return false
+		}
+	}
+}
+
+func (s *scan) visited(dir string) {
+	if s != nil && s.visit != nil {
+		s.visit(dir)
+	}
+}
+
+// walk finds the projects under dir.
+func (s *scan) walk(dir string, depth int, level int) []File {
+	files, err := s.fsys.ReadDir(dir)
+	if err != nil {
+		if s.err == nil {
+			s.err = err
+		}
+		return nil
+	}
+	s.visited(dir)
+	if rules, ok := loadGitignore(s.fsys, dir); ok {
+		s.gitignores[dir] = rules
+	}
+
+	if members := workspaceMembers(s.fsys, dir, s); len(members) > 0 {
+		slog.Debug("found workspace", "dir", dir, "members", len(members))
+		return workspaceProjects(s.fsys, dir, members)
+	}
+
 	projects := []File{}
 
-	if IsProject(dir) {
-		projects = append(projects, File{path.Base(dir), dir})
+	if project, ok := discovery.Detect(s.fsys, dir); ok {
+		slog.Debug("found project", "name", project.Name, "dir", dir)
+		projects = append(projects, File{Name: project.Name, Dir: dir, FS: s.fsys})
 	}
 
 	for _, file := range files {
@@ -70,39 +308,48 @@ func GetAllProjects(dir string, depth int, level int) []File {
 		}
 
 		projectDir := path.Join(dir, file.Name())
+		if s.skip(projectDir) {
+			continue
+		}
+		s.visited(projectDir)
 
-		if !IsProject(projectDir) && ( depth == -1 || level <= depth ) {
-			if !slices.Contains(BLACKLIST, file.Name()) {
-				projects = append(projects, GetAllProjects(projectDir, depth, level + 1)...)
-			}
+		if len(WorkspaceGlobs(s.fsys, projectDir)) > 0 {
+			projects = append(projects, s.walk(projectDir, depth, level+1)...)
+			continue
+		}
+
+		project, isProject := discovery.Detect(s.fsys, projectDir)
+
+		// walk adds the orchestrating project itself
+		if isProject && discovery.Orchestrates(project.Type) && (depth == -1 || level < depth) {
+			projects = append(projects, s.walk(projectDir, depth, level+1)...)
+			continue
+		}
+
+		if !isProject && (depth == -1 || level <= depth) {
+			projects = append(projects, s.walk(projectDir, depth, level+1)...)
 			continue
 		}
 
 		if depth != -1 && level >= depth {
+			slog.Debug("skipping project below --depth", "name", project.Name, "dir", projectDir, "depth", depth)
 			continue
 		}
 
-		projects = append(projects, File{file.Name(), projectDir})
+		slog.Debug("found project", "name", project.Name, "dir", projectDir)
+		projects = append(projects, File{Name: project.Name, Dir: projectDir, FS: s.fsys})
 	}
 
 	return projects
 }
 
-func IsProject(dir string) bool {
-	hasComposer, _ := FileExists(path.Join(dir, "composer.json"))
-	hasPackage, _ := FileExists(path.Join(dir, "package.json"))
-	return hasComposer && hasPackage
+func IsProject(fsys fsys.FS, dir string) bool {
+	_, ok := discovery.Detect(fsys, dir)
+	return ok
 }
 
 func FileExists(name string) (bool, error) {
-	_, err := os.Stat(name)
-	if err == nil {
-		return true, nil
-	}
-	if errors.Is(err, os.ErrNotExist) {
-		return false, nil
-	}
-	return false, err
+	return fsys.OS.FileExists(name)
 }
 
 func All[T any](ts []T, pred func(T) bool) bool {
@@ -118,8 +365,18 @@ func Some[T any](ts []T, pred func(T) bool) bool {
 	return slices.ContainsFunc(ts, pred)
 }
 
+func HasPackageJSON(project types.Project) bool {
+	exists, _ := project.FS.FileExists(path.Join(project.Dir, "package.json"))
+	return exists
+}
+
+func HasComposerJSON(project types.Project) bool {
+	exists, _ := project.FS.FileExists(path.Join(project.Dir, "composer.json"))
+	return exists
+}
+
 func HasYarn(project types.Project) bool {
-	exists, _ := FileExists(path.Join(project.Dir, "yarn.lock"))
+	exists, _ := project.FS.FileExists(path.Join(project.Dir, "yarn.lock"))
 	return exists
 }
 
@@ -131,22 +388,37 @@ func Not[T any](pred func(T) bool) func(T) bool {
 
 func And[T any](preds ...func(T) bool) func(T) bool {
 	return func(thing T) bool {
-		return All(preds, func (pred func(T) bool) bool {
+		return All(preds, func(pred func(T) bool) bool {
+			return pred(thing)
+		})
+	}
+}
+
+func Or[T any](preds ...func(T) bool) func(T) bool {
+	return func(thing T) bool {
+		return Some(preds, func(pred func(T) bool) bool {
 			return pred(thing)
 		})
 	}
 }
 
 func HasScript(script string) func(p types.Project) bool {
-	return func (project types.Project) bool {
-		file, err := os.ReadFile(path.Join(project.Dir, "package.json"))
-		if err != nil {
-			return false
-		}
-		pkg := PackageJSON{}
-		_ = json.Unmarshal(file, &pkg)
-		_, exists := pkg.Scripts[script]
+	return func(project types.Project) bool {
+		_, exists := GetScript(project.FS, project.Dir, script)
 
 		return exists
 	}
 }
+
+// GetScript returns the body of a package.json script in dir.
+func GetScript(fsys fsys.FS, dir string, script string) (string, bool) {
+	file, err := fsys.ReadFile(path.Join(dir, "package.json"))
+	if err != nil {
+		return "", false
+	}
+	pkg := PackageJSON{}
+	_ = json.Unmarshal(file, &pkg)
+	body, exists := pkg.Scripts[script]
+
+	return body, exists
+}
@@ -0,0 +1,185 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"path"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"jrmd.dev/qk/discovery"
+	"jrmd.dev/qk/fsys"
+	"jrmd.dev/qk/types"
+)
+
+func file(data string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(data)}
+}
+
+func projectNames(projects []File) []string {
+	names := []string{}
+	for _, project := range projects {
+		names = append(names, project.Name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+func TestGetAllProjectsIn(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	workspace := fsys.New(fstest.MapFS{
+		"work/api/composer.json":                 file(`{}`),
+		"work/api/package.json":                  file(`{}`),
+		"work/web/package.json":                  file(`{}`),
+		"work/web/node_modules/dep/package.json": file(`{}`),
+		"work/libs/ui/package.json":              file(`{}`),
+		"work/libs/ui/deep/x/package.json":       file(`{}`),
+		"work/docs/README.md":                    file(`docs`),
+	})
+
+	tests := []struct {
+		name  string
+		mode  string
+		depth int
+		want  []string
+	}{
+		{"node", discovery.ModeNode, -1, []string{"api", "ui", "web"}},
+		{"php", discovery.ModePHP, -1, []string{"api"}},
+		{"both", discovery.ModeBoth, -1, []string{"api"}},
+		{"depth", discovery.ModeNode, 1, []string{"api", "web"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := discovery.SetMode(tt.mode); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = discovery.SetMode(discovery.ModeBoth) })
+
+			projects := GetAllProjectsIn(workspace, "/work", tt.depth, 0)
+			if got := projectNames(projects); !slices.Equal(got, tt.want) {
+				t.Errorf("found %v, want %v", got, tt.want)
+			}
+			for _, project := range projects {
+				if !project.FS.Exists(path.Join(project.Dir, "package.json")) {
+					t.Errorf("%s does not keep the filesystem it was found in", project.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestHasScript(t *testing.T) {
+	workspace := fsys.New(fstest.MapFS{
+		"work/app/package.json":    file(`{"scripts": {"build": "tsc", "dev": "vite"}}`),
+		"work/bare/package.json":   file(`{}`),
+		"work/broken/package.json": file(`{"scripts":`),
+	})
+
+	tests := []struct {
+		dir    string
+		script string
+		want   bool
+	}{
+		{"/work/app", "build", true},
+		{"/work/app", "dev", true},
+		{"/work/app", "test", false},
+		{"/work/bare", "build", false},
+		{"/work/broken", "build", false},
+		{"/work/missing", "build", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir+" "+tt.script, func(t *testing.T) {
+			project := types.Project{Name: "p", Dir: tt.dir, FS: workspace}
+			if got := HasScript(tt.script)(project); got != tt.want {
+				t.Errorf("HasScript(%q) = %t, want %t", tt.script, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindProjectsUnreadable(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	_, err := FindProjects(fsys.New(fstest.MapFS{"work/app/package.json": file(`{}`)}), "/missing", -1)
+	if err == nil {
+		t.Error("FindProjects returned no error for a missing directory")
+	}
+}
+
+func TestGetAllProjectsInAny(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := discovery.SetMode(discovery.ModeAny); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = discovery.SetMode(discovery.ModeBoth) })
+
+	workspace := fsys.New(fstest.MapFS{
+		"work/stack/Makefile":                 file("up:\n\tdocker compose up\n"),
+		"work/stack/services/api/go.mod":      file("module api\n"),
+		"work/stack/services/api/Makefile":    file("build:\n\tgo build\n"),
+		"work/stack/services/worker/go.mod":   file("module worker\n"),
+		"work/tools/Taskfile.yml":             file("version: '3'\n"),
+		"work/tools/cli/Cargo.toml":           file("[package]\n"),
+		"work/tools/cli/nested/x/Cargo.toml":  file("[package]\n"),
+		"work/web/package.json":               file(`{}`),
+		"work/web/examples/demo/package.json": file(`{}`),
+	})
+
+	tests := []struct {
+		name  string
+		depth int
+		want  []string
+	}{
+		{"below orchestrating projects", -1, []string{"api", "cli", "stack", "tools", "web", "worker"}},
+		{"depth", 1, []string{"stack", "tools", "web"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projects := GetAllProjectsIn(workspace, "/work", tt.depth, 0)
+			if got := projectNames(projects); !slices.Equal(got, tt.want) {
+				t.Errorf("found %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoveryCacheAncestorGitignore(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	UseGitignore(true)
+	UseDiscoveryCache(true, "test")
+	t.Cleanup(func() {
+		UseGitignore(false)
+		UseDiscoveryCache(false, "")
+	})
+
+	files := fstest.MapFS{
+		"repo/.git/HEAD":                file(""),
+		"repo/.gitignore":               file("legacy\n"),
+		"repo/apps/web/package.json":    file(`{}`),
+		"repo/apps/api/package.json":    file(`{}`),
+		"repo/apps/legacy/package.json": file(`{}`),
+	}
+	workspace := fsys.New(files)
+
+	if err := discovery.SetMode(discovery.ModeNode); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = discovery.SetMode(discovery.ModeBoth) })
+
+	if got, want := projectNames(GetAllProjectsIn(workspace, "/repo/apps", -1, 0)), []string{"api", "web"}; !slices.Equal(got, want) {
+		t.Fatalf("found %v, want %v", got, want)
+	}
+
+	files["repo/.gitignore"] = &fstest.MapFile{Data: []byte("legacy\napi\n"), ModTime: time.Now()}
+	if got, want := projectNames(GetAllProjectsIn(workspace, "/repo/apps", -1, 0)), []string{"web"}; !slices.Equal(got, want) {
+		t.Errorf("found %v after editing the repository's .gitignore, want %v", got, want)
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path"
+	"slices"
+	"time"
+)
+
+// HISTORY_LIMIT is how many runs the history keeps, older ones are dropped
+// as new ones are recorded.
+var HISTORY_LIMIT = 1000
+
+// HistoryCommand is the outcome of a command in a recorded run.
+type HistoryCommand struct {
+	Command  string  `json:"command"`
+	Status   string  `json:"status"`
+	Duration float64 `json:"duration"`
+	ExitCode int     `json:"exitCode"`
+	LogFile  string  `json:"logFile,omitempty"`
+	// Cached commands were skipped by the build cache, they took no time.
+	Cached bool `json:"cached,omitempty"`
+	// Tail is the end of a failed command's output, sent to webhooks.
+	// RecordHistory drops it, the history points at LogFile instead.
+	Tail string `json:"tail,omitempty"`
+}
+
+// HistoryProject is the outcome of a project in a recorded run.
+type HistoryProject struct {
+	Project  string           `json:"project"`
+	Dir      string           `json:"dir"`
+	Commands []HistoryCommand `json:"commands"`
+}
+
+// Status rolls the statuses of the project's commands up into one.
+func (p HistoryProject) Status() string {
+	statuses := []string{}
+	for _, command := range p.Commands {
+		statuses = append(statuses, command.Status)
+	}
+
+	return CombinedStatus(statuses)
+}
+
+// CombinedStatus rolls command statuses up into one: failed when any
+// failed or was skipped, finished when all finished.
+func CombinedStatus(statuses []string) string {
+	status := "finished"
+	for _, s := range statuses {
+		if s == "failed" || s == "skipped" {
+			return "failed"
+		}
+		if s != "finished" {
+			status = s
+		}
+	}
+
+	return status
+}
+
+// HistoryEntry records a run: what was run where, and how it went.
+type HistoryEntry struct {
+	ID       int       `json:"id"`
+	Time     time.Time `json:"time"`
+	Command  string    `json:"command"`
+	Dir      string    `json:"dir"`
+	Status   string    `json:"status"`
+	Duration float64   `json:"duration"`
+	// LogDir holds the output of every command, when logging is enabled.
+	LogDir   string           `json:"logDir,omitempty"`
+	Projects []HistoryProject `json:"projects"`
+}
+
+func historyFile() (string, error) {
+	return stateFile("history.jsonl")
+}
+
+// ReadHistory returns the recorded runs, oldest first.
+func ReadHistory() []HistoryEntry {
+	entries := []HistoryEntry{}
+
+	file, err := historyFile()
+	if err != nil {
+		return entries
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return entries
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		entry := HistoryEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// RecordHistory appends a run to the history, numbering it after the last
+// one, and returns it with its ID. Concurrent runs take turns, so IDs stay
+// unique and no run is lost.
+func RecordHistory(entry HistoryEntry) (HistoryEntry, error) {
+	file, err := historyFile()
+	if err != nil {
+		return entry, err
+	}
+	if err := os.MkdirAll(path.Dir(file), 0o755); err != nil {
+		return entry, err
+	}
+
+	// output stays in the log files the commands point at
+	projects := []HistoryProject{}
+	for _, project := range entry.Projects {
+		project.Commands = slices.Clone(project.Commands)
+		for i := range project.Commands {
+			project.Commands[i].Tail = ""
+		}
+		projects = append(projects, project)
+	}
+	entry.Projects = projects
+
+	err = withLock(file, func() error {
+		entries := ReadHistory()
+		entry.ID = 1
+		if len(entries) > 0 {
+			entry.ID = entries[len(entries)-1].ID + 1
+		}
+
+		if len(entries) >= HISTORY_LIMIT {
+			return writeHistory(file, append(entries[len(entries)-HISTORY_LIMIT+1:], entry))
+		}
+
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = f.Write(append(data, '\n'))
+		return err
+	})
+
+	return entry, err
+}
+
+func writeHistory(file string, entries []HistoryEntry) error {
+	lines := []byte{}
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		lines = append(append(lines, data...), '\n')
+	}
+
+	return writeAtomic(file, lines)
+}
+
+// ProjectRun is a project's part of a recorded run.
+type ProjectRun struct {
+	HistoryProject
+	Time time.Time
+}
+
+// LastRun finds the most recent recorded run of the project in dir.
+func LastRun(dir string) (ProjectRun, bool) {
+	entries := ReadHistory()
+	for i := len(entries) - 1; i >= 0; i-- {
+		for _, project := range entries[i].Projects {
+			if project.Dir == dir {
+				return ProjectRun{HistoryProject: project, Time: entries[i].Time}, true
+			}
+		}
+	}
+
+	return ProjectRun{}, false
+}
+
+// FindHistory returns the recorded run with the given ID.
+func FindHistory(id int) (HistoryEntry, bool) {
+	for _, entry := range ReadHistory() {
+		if entry.ID == id {
+			return entry, true
+		}
+	}
+
+	return HistoryEntry{}, false
+}
@@ -0,0 +1,93 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRecordHistoryConcurrently(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := RecordHistory(HistoryEntry{Command: "build"}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	ids := []int{}
+	for _, entry := range ReadHistory() {
+		ids = append(ids, entry.ID)
+	}
+	slices.Sort(ids)
+	if len(ids) != 20 || ids[0] != 1 || ids[19] != 20 || len(slices.Compact(ids)) != 20 {
+		t.Errorf("recorded ids %v, want 1 to 20", ids)
+	}
+}
+
+func TestLastRun(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	first, second := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	for _, entry := range []HistoryEntry{
+		{Time: first, Projects: []HistoryProject{
+			{Dir: "/work/app", Commands: []HistoryCommand{{Status: "failed"}}},
+			{Dir: "/work/lib", Commands: []HistoryCommand{{Status: "finished"}}},
+		}},
+		{Time: second, Projects: []HistoryProject{
+			{Dir: "/work/app", Commands: []HistoryCommand{{Status: "finished"}}},
+		}},
+	} {
+		if _, err := RecordHistory(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		dir    string
+		ok     bool
+		status string
+		time   time.Time
+	}{
+		{"/work/app", true, "finished", second},
+		{"/work/lib", true, "finished", first},
+		{"/work/new", false, "", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			run, ok := LastRun(tt.dir)
+			if ok != tt.ok || (ok && (run.Status() != tt.status || !run.Time.Equal(tt.time))) {
+				t.Errorf("LastRun(%q) = %s at %s, %t, want %s at %s, %t", tt.dir, run.Status(), run.Time, ok, tt.status, tt.time, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRecordHistoryKeepsLogFilesOnly(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	command := HistoryCommand{Command: "yarn build", Status: "failed", LogFile: "/logs/0-yarn_build.log", Tail: "error TS2322"}
+	entry := HistoryEntry{Command: "build", Projects: []HistoryProject{{Project: "app", Commands: []HistoryCommand{command}}}}
+	if _, err := RecordHistory(entry); err != nil {
+		t.Fatal(err)
+	}
+
+	recorded := ReadHistory()[0].Projects[0].Commands[0]
+	if recorded.Tail != "" || recorded.LogFile != command.LogFile {
+		t.Errorf("recorded %+v, want the log file without output", recorded)
+	}
+	if entry.Projects[0].Commands[0].Tail == "" {
+		t.Error("recording dropped the caller's output")
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	"jrmd.dev/qk/fsys"
+)
+
+// IgnoreFile lists gitignore-style patterns of directories discovery skips.
+const IgnoreFile = ".qkignore"
+
+var useGitignore = false
+
+// UseGitignore makes discovery skip the directories ignored by the
+// .gitignore files it walks past.
+func UseGitignore(enabled bool) {
+	useGitignore = enabled
+}
+
+// loadGitignore reads dir's .gitignore when discovery respects them.
+func loadGitignore(fsys fsys.FS, dir string) (IgnoreRules, bool) {
+	if !useGitignore {
+		return IgnoreRules{}, false
+	}
+
+	file, err := fsys.ReadFile(path.Join(dir, ".gitignore"))
+	if err != nil {
+		return IgnoreRules{}, false
+	}
+
+	return ParseIgnore(dir, strings.Split(string(file), "\n")), true
+}
+
+type ignoreRule struct {
+	pattern  string
+	negate   bool
+	anchored bool
+}
+
+// IgnoreRules are gitignore-style patterns matched against directories
+// relative to Base. The last matching rule wins and ! negates a rule.
+type IgnoreRules struct {
+	Base  string
+	rules []ignoreRule
+}
+
+// ParseIgnore reads gitignore-style lines. Patterns containing a slash are
+// anchored to base, others match a directory name at any depth and **
+// spans any number of directories.
+func ParseIgnore(base string, lines []string) IgnoreRules {
+	rules := IgnoreRules{Base: base}
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{}
+		if negated, ok := strings.CutPrefix(line, "!"); ok {
+			rule.negate = true
+			line = negated
+		}
+
+		line = strings.TrimSuffix(line, "/")
+		rule.anchored = strings.Contains(line, "/")
+		rule.pattern = strings.TrimPrefix(line, "/")
+		if rule.pattern != "" {
+			rules.rules = append(rules.rules, rule)
+		}
+	}
+
+	return rules
+}
+
+// LoadIgnore combines BLACKLIST, the configured Blacklist and the nearest
+// .qkignore at or above root in fsys.
+func LoadIgnore(fsys fsys.FS, conf Config, root string) IgnoreRules {
+	base, lines := root, []string{}
+	for dir := root; ; dir = path.Dir(dir) {
+		if file, err := fsys.ReadFile(path.Join(dir, IgnoreFile)); err == nil {
+			base, lines = dir, strings.Split(string(file), "\n")
+			break
+		}
+		if path.Dir(dir) == dir {
+			break
+		}
+	}
+
+	patterns := append(append([]string{}, BLACKLIST...), conf.Blacklist...)
+	return ParseIgnore(base, append(patterns, lines...))
+}
+
+// Ignored reports whether dir is skipped by the rules.
+func (r IgnoreRules) Ignored(dir string) bool {
+	rel, err := filepath.Rel(r.Base, dir)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		rel = path.Base(dir)
+	}
+	rel = filepath.ToSlash(rel)
+
+	ignored := false
+	for _, rule := range r.rules {
+		if rule.matches(rel) {
+			ignored = !rule.negate
+		}
+	}
+
+	return ignored
+}
+
+func (rule ignoreRule) matches(rel string) bool {
+	if rule.anchored {
+		return globMatch(rule.pattern, rel)
+	}
+
+	return globMatch(rule.pattern, path.Base(rel))
+}
+
+// Patterns returns the rules as written, for telling rule sets apart.
+func (r IgnoreRules) Patterns() []string {
+	patterns := []string{}
+	for _, rule := range r.rules {
+		pattern := rule.pattern
+		if rule.anchored {
+			pattern = "/" + pattern
+		}
+		if rule.negate {
+			pattern = "!" + pattern
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"encoding/json"
+	"path"
+	"slices"
+
+	"jrmd.dev/qk/discovery"
+	"jrmd.dev/qk/fsys"
+)
+
+// ProjectInfo describes what qk knows about a discovered project.
+type ProjectInfo struct {
+	Name           string   `json:"name"`
+	Dir            string   `json:"dir"`
+	Type           string   `json:"type"`
+	PackageManager string   `json:"packageManager"`
+	Scripts        []string `json:"scripts"`
+	// ComposerScripts are the scripts of composer.json, Scripts the ones
+	// of package.json.
+	ComposerScripts []string `json:"composerScripts"`
+	// Tasks and MakeTargets are what qk run falls back to without a
+	// script of the name.
+	Tasks       []string  `json:"tasks"`
+	MakeTargets []string  `json:"makeTargets"`
+	Lockfiles   []string  `json:"lockfiles"`
+	Workspace   string    `json:"workspace,omitempty"`
+	Group       string    `json:"group,omitempty"`
+	Git         *GitState `json:"git,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	// Skipped projects are left out of runs until qk unskip.
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+func GetProjectInfo(conf Config, project File) ProjectInfo {
+	lockfiles := []string{}
+	for _, name := range LOCKFILES {
+		if exists, _ := project.FS.FileExists(path.Join(project.Dir, name)); exists {
+			lockfiles = append(lockfiles, name)
+		}
+	}
+
+	manager := PackageManager(project.FS, project.Dir)
+	if project.Workspace != "" && project.Workspace != project.Dir {
+		manager = PackageManager(project.FS, project.Workspace)
+	}
+
+	var git *GitState
+	if state, ok := ProjectGitState(project.Dir); ok {
+		git = &state
+	}
+
+	return ProjectInfo{
+		Name:            project.Name,
+		Dir:             project.Dir,
+		Type:            ProjectType(project.FS, project.Dir),
+		PackageManager:  manager,
+		Scripts:         GetScripts(project.FS, project.Dir),
+		ComposerScripts: GetComposerScripts(project.FS, project.Dir),
+		Tasks:           Tasks(project.FS, project.Dir),
+		MakeTargets:     MakeTargets(project.FS, project.Dir),
+		Lockfiles:       lockfiles,
+		Workspace:       project.Workspace,
+		Group:           ProjectGroup(conf, project),
+		Git:             git,
+		Tags:            ProjectTags(conf, project.Name),
+		Skipped:         IsSkipped(conf, project.Name),
+	}
+}
+
+// ProjectType reports the kind of project the detectors recognised in dir,
+// such as node, php, both or go.
+func ProjectType(fsys fsys.FS, dir string) string {
+	if project, ok := discovery.Detect(fsys, dir); ok {
+		return project.Type
+	}
+
+	return ""
+}
+
+// GetScripts returns the sorted names of the package.json scripts in dir.
+func GetScripts(fsys fsys.FS, dir string) []string {
+	scripts := []string{}
+	file, err := fsys.ReadFile(path.Join(dir, "package.json"))
+	if err != nil {
+		return scripts
+	}
+
+	pkg := PackageJSON{}
+	_ = json.Unmarshal(file, &pkg)
+	for name := range pkg.Scripts {
+		scripts = append(scripts, name)
+	}
+	slices.Sort(scripts)
+
+	return scripts
+}
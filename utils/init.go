@@ -0,0 +1,109 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"jrmd.dev/qk/discovery"
+)
+
+// UNLIKELY_PROJECT_DIRS are directories whose manifests are rarely real
+// projects, such as copies in build output or test fixtures.
+var UNLIKELY_PROJECT_DIRS = append([]string{"fixtures", "__fixtures__", "testdata", "examples"}, BUILD_OUTPUTS...)
+
+// Starter is what qk init suggests for a directory of projects.
+type Starter struct {
+	// Config are the suggested repo-level settings by name, starting out
+	// from a few defaults worth editing such as GracePeriod.
+	Config map[string]any
+	// Ignore are .qkignore patterns for the directories of projects which
+	// are likely not real ones.
+	Ignore []string
+	// Projects are the projects left once Ignore applies.
+	Projects []File
+}
+
+// SuggestStarter looks at the projects found under root with every kind of
+// manifest detected, and suggests the manifests projects are detected by
+// and groups of projects sharing a top-level directory.
+func SuggestStarter(root string, projects []File) Starter {
+	starter := Starter{
+		Config: map[string]any{
+			"Detect":      discovery.ModeBoth,
+			"GracePeriod": "3s",
+			"Env":         map[string]any{},
+			"Groups":      map[string]any{},
+		},
+		Ignore:   []string{},
+		Projects: []File{},
+	}
+
+	for _, project := range projects {
+		rel, err := filepath.Rel(root, project.Dir)
+		if err != nil {
+			rel = project.Dir
+		}
+
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		unlikely := slices.IndexFunc(parts, func(part string) bool { return slices.Contains(UNLIKELY_PROJECT_DIRS, part) })
+		if unlikely == -1 {
+			starter.Projects = append(starter.Projects, project)
+			continue
+		}
+
+		pattern := parts[unlikely] + "/"
+		if !slices.Contains(starter.Ignore, pattern) {
+			starter.Ignore = append(starter.Ignore, pattern)
+		}
+	}
+	slices.Sort(starter.Ignore)
+
+	types := map[string]int{}
+	others := 0
+	for _, project := range starter.Projects {
+		projectType := ProjectType(project.FS, project.Dir)
+		types[projectType]++
+		if !slices.Contains([]string{discovery.ModeNode, discovery.ModePHP, discovery.ModeBoth}, projectType) {
+			others++
+		}
+	}
+	switch {
+	case others > 0:
+		// go modules, rust crates, Makefiles and Taskfiles
+		starter.Config["Detect"] = discovery.ModeAny
+	case types[discovery.ModeNode]+types[discovery.ModePHP] == 0:
+		// every project has both manifests, or is found by markers
+	case types[discovery.ModePHP] == 0:
+		starter.Config["Detect"] = discovery.ModeNode
+	case types[discovery.ModeNode] == 0 && types[discovery.ModeBoth] == 0:
+		starter.Config["Detect"] = discovery.ModePHP
+	default:
+		starter.Config["Detect"] = discovery.ModeAny
+	}
+
+	groups := map[string][]string{}
+	for _, project := range starter.Projects {
+		rel, err := filepath.Rel(root, project.Dir)
+		if err != nil {
+			continue
+		}
+		top, _, nested := strings.Cut(filepath.ToSlash(rel), "/")
+		if nested {
+			groups[top] = append(groups[top], project.Name)
+		}
+	}
+	for name, members := range groups {
+		if len(members) < 2 {
+			delete(groups, name)
+		}
+	}
+	if len(groups) > 1 {
+		starter.Config["Groups"] = groups
+	}
+
+	return starter
+}
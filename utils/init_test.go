@@ -0,0 +1,73 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"jrmd.dev/qk/discovery"
+	"jrmd.dev/qk/fsys"
+)
+
+func TestSuggestStarterDetect(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := discovery.SetMode(discovery.ModeAny); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = discovery.SetMode(discovery.ModeBoth) })
+
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+		want  any
+	}{
+		{
+			name: "node only",
+			files: fstest.MapFS{
+				"work/web/package.json": file(`{}`),
+				"work/app/package.json": file(`{}`),
+			},
+			want: discovery.ModeNode,
+		},
+		{
+			name: "node and php",
+			files: fstest.MapFS{
+				"work/web/package.json":  file(`{}`),
+				"work/api/composer.json": file(`{}`),
+			},
+			want: discovery.ModeAny,
+		},
+		{
+			name: "node and go",
+			files: fstest.MapFS{
+				"work/web/package.json": file(`{}`),
+				"work/api/go.mod":       file("module api\n"),
+			},
+			want: discovery.ModeAny,
+		},
+		{
+			name: "php and a Makefile",
+			files: fstest.MapFS{
+				"work/shop/composer.json": file(`{}`),
+				"work/ops/Makefile":       file("deploy:\n"),
+			},
+			want: discovery.ModeAny,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projects, err := FindProjects(fsys.New(tt.files), "/work", 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			starter := SuggestStarter("/work", projects)
+			if len(starter.Projects) != 2 || starter.Config["Detect"] != tt.want {
+				t.Errorf("suggested Detect %v for %d projects, want %v for 2", starter.Config["Detect"], len(starter.Projects), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+
+	"jrmd.dev/qk/fsys"
+)
+
+var LOCKFILES = []string{"yarn.lock", "package-lock.json", "bun.lockb", "bun.lock", "pnpm-lock.yaml", "composer.lock"}
+
+// HashLockfiles returns the sha256 of each known lockfile in dir, missing
+// lockfiles hash to an empty string.
+func HashLockfiles(fsys fsys.FS, dir string) map[string]string {
+	return hashFiles(fsys, dir, LOCKFILES)
+}
+
+func hashFiles(fsys fsys.FS, dir string, names []string) map[string]string {
+	hashes := map[string]string{}
+	for _, name := range names {
+		data, err := fsys.ReadFile(path.Join(dir, name))
+		if err != nil {
+			hashes[name] = ""
+			continue
+		}
+
+		sum := sha256.Sum256(data)
+		hashes[name] = hex.EncodeToString(sum[:])
+	}
+
+	return hashes
+}
+
+// ChangedLockfiles compares two sets of lockfile hashes and returns the names
+// of the lockfiles that differ.
+func ChangedLockfiles(before map[string]string, after map[string]string) []string {
+	changed := []string{}
+	for _, name := range LOCKFILES {
+		if before[name] != after[name] {
+			changed = append(changed, name)
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,75 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// LOG_LEVELS are the values accepted by --log-level.
+var LOG_LEVELS = []string{"debug", "info", "warn", "error"}
+
+// logOutput is where qk's own diagnostics go. It starts out as stderr, the
+// runner holds them in a buffer while the TUI owns the terminal and writes
+// them out once it's gone.
+var logOutput = struct {
+	sync.Mutex
+	w io.Writer
+}{w: os.Stderr}
+
+type logWriter struct{}
+
+func (logWriter) Write(p []byte) (int, error) {
+	logOutput.Lock()
+	defer logOutput.Unlock()
+	return logOutput.w.Write(p)
+}
+
+var logLevel = new(slog.LevelVar)
+
+func init() {
+	logLevel.Set(slog.LevelWarn)
+	slog.SetDefault(slog.New(slog.NewTextHandler(logWriter{}, &slog.HandlerOptions{Level: logLevel})))
+}
+
+// ParseLogLevel reads one of LOG_LEVELS.
+func ParseLogLevel(level string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return l, fmt.Errorf("log level must be one of %s, got %q", strings.Join(LOG_LEVELS, ", "), level)
+	}
+
+	return l, nil
+}
+
+// SetLogLevel sets the lowest level of diagnostics written.
+func SetLogLevel(level slog.Level) {
+	logLevel.Set(level)
+}
+
+// LogEnabled reports whether diagnostics at level are written.
+func LogEnabled(level slog.Level) bool {
+	return level >= logLevel.Level()
+}
+
+// LogTo sends diagnostics to w and returns a function restoring the
+// previous destination.
+func LogTo(w io.Writer) func() {
+	logOutput.Lock()
+	defer logOutput.Unlock()
+
+	previous := logOutput.w
+	logOutput.w = w
+
+	return func() {
+		logOutput.Lock()
+		defer logOutput.Unlock()
+		logOutput.w = previous
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"jrmd.dev/qk/discovery"
+	"jrmd.dev/qk/fsys"
+	"jrmd.dev/qk/types"
+)
+
+// makeRule matches the targets of a rule, but not variable assignments
+// such as FOO := bar or FOO ::= bar.
+var makeRule = regexp.MustCompile(`^([^\s:=#][^:=#]*?)\s*::?([^:=]|$)`)
+
+func readFirst(fsys fsys.FS, dir string, names []string) ([]byte, bool) {
+	for _, name := range names {
+		if data, err := fsys.ReadFile(path.Join(dir, name)); err == nil {
+			return data, true
+		}
+	}
+
+	return nil, false
+}
+
+// MakeTargets lists the targets of the Makefile in dir which can be run by
+// name, leaving out special targets such as .PHONY and pattern rules.
+func MakeTargets(fsys fsys.FS, dir string) []string {
+	data, ok := readFirst(fsys, dir, discovery.MAKEFILES)
+	if !ok {
+		return []string{}
+	}
+
+	targets := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		match := makeRule.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+
+		for _, target := range strings.Fields(match[1]) {
+			if strings.HasPrefix(target, ".") || strings.ContainsAny(target, "%$") || slices.Contains(targets, target) {
+				continue
+			}
+			targets = append(targets, target)
+		}
+	}
+
+	return targets
+}
+
+// Tasks lists the tasks of the Taskfile in dir, the keys under its top
+// level tasks.
+func Tasks(fsys fsys.FS, dir string) []string {
+	data, ok := readFirst(fsys, dir, discovery.TASKFILES)
+	if !ok {
+		return []string{}
+	}
+
+	tasks := []string{}
+	inTasks, indent := false, ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		// a top level key ends the tasks
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			inTasks = strings.HasPrefix(line, "tasks:")
+			indent = ""
+			continue
+		}
+		if !inTasks {
+			continue
+		}
+
+		current := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if indent == "" {
+			indent = current
+		}
+		if current != indent {
+			continue
+		}
+
+		if name, ok := taskName(trimmed); ok {
+			tasks = append(tasks, name)
+		}
+	}
+
+	return tasks
+}
+
+// taskName reads the key of a line such as build: or "db:migrate":.
+func taskName(line string) (string, bool) {
+	if quote := line[0]; quote == '"' || quote == '\'' {
+		name, rest, ok := strings.Cut(line[1:], string(quote))
+		return name, ok && strings.HasPrefix(strings.TrimSpace(rest), ":")
+	}
+
+	name, _, ok := strings.Cut(line, ":")
+	return name, ok
+}
+
+// HasMakeTarget matches projects whose Makefile has the target name.
+func HasMakeTarget(name string) func(types.Project) bool {
+	return func(project types.Project) bool {
+		return slices.Contains(MakeTargets(project.FS, project.Dir), name)
+	}
+}
+
+// HasTask matches projects whose Taskfile has the task name.
+func HasTask(name string) func(types.Project) bool {
+	return func(project types.Project) bool {
+		return slices.Contains(Tasks(project.FS, project.Dir), name)
+	}
+}
+
+// TaskArgs returns the arguments task needs to run name, passing args on
+// as the task's CLI_ARGS.
+func TaskArgs(name string, args ...string) []string {
+	if len(args) == 0 {
+		return []string{name}
+	}
+
+	return append([]string{name, "--"}, args...)
+}
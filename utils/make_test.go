@@ -0,0 +1,103 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"jrmd.dev/qk/fsys"
+)
+
+func TestMakeTargets(t *testing.T) {
+	tests := []struct {
+		name     string
+		makefile string
+		want     []string
+	}{
+		{
+			name:     "rules",
+			makefile: "build: deps\n\tgo build\n\ntest:\n\tgo test\n",
+			want:     []string{"build", "test"},
+		},
+		{
+			name:     "several targets and double colon rules",
+			makefile: "lint vet: \n\ttrue\nclean::\n\trm -rf dist\n",
+			want:     []string{"lint", "vet", "clean"},
+		},
+		{
+			name:     "variables are not targets",
+			makefile: "GO := go\nFLAGS = -v\nOUT ?= bin\nCC::=gcc\nbuild:\n\t$(GO) build\n",
+			want:     []string{"build"},
+		},
+		{
+			name:     "special, pattern and variable targets",
+			makefile: ".PHONY: build\n%.o: %.c\n$(OUT): main.go\nbuild:\n",
+			want:     []string{"build"},
+		},
+		{
+			name:     "recipes and comments",
+			makefile: "# build: not a rule\nbuild:\n\techo a:b\n\t@printf 'x: y'\n",
+			want:     []string{"build"},
+		},
+		{
+			name:     "duplicates",
+			makefile: "build: a\nbuild: b\n",
+			want:     []string{"build"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MakeTargets(fsys.New(fstest.MapFS{"app/Makefile": file(tt.makefile)}), "/app")
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("MakeTargets = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTasks(t *testing.T) {
+	tests := []struct {
+		name     string
+		taskfile string
+		want     []string
+	}{
+		{
+			name:     "tasks",
+			taskfile: "version: '3'\n\ntasks:\n  build:\n    cmds:\n      - go build\n  test:\n    cmds:\n      - go test\n",
+			want:     []string{"build", "test"},
+		},
+		{
+			name:     "quoted names and comments",
+			taskfile: "tasks:\n  # helpers\n  \"db:migrate\":\n    cmds: [migrate]\n  'lint':\n    cmds: [golangci-lint run]\n",
+			want:     []string{"db:migrate", "lint"},
+		},
+		{
+			name:     "other top level keys",
+			taskfile: "vars:\n  GREETING: hello\ntasks:\n  hello:\n    cmds:\n      - echo {{.GREETING}}\nincludes:\n  docs: ./docs\n",
+			want:     []string{"hello"},
+		},
+		{
+			name:     "tab indented",
+			taskfile: "tasks:\n\tbuild:\n\t\tcmds: [make]\n",
+			want:     []string{"build"},
+		},
+		{
+			name:     "no tasks",
+			taskfile: "version: '3'\n",
+			want:     []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Tasks(fsys.New(fstest.MapFS{"app/Taskfile.yml": file(tt.taskfile)}), "/app")
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Tasks = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
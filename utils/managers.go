@@ -0,0 +1,99 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"path"
+
+	"jrmd.dev/qk/fsys"
+	"jrmd.dev/qk/types"
+)
+
+// NODE_MANAGERS lists the node package managers qk knows, in order of
+// precedence when a project has several lockfiles.
+var NODE_MANAGERS = []string{"bun", "pnpm", "yarn", "npm"}
+
+// PackageManager returns the node package manager qk would use in dir.
+func PackageManager(fsys fsys.FS, dir string) string {
+	if exists, _ := fsys.FileExists(path.Join(dir, "package.json")); !exists {
+		return ""
+	}
+
+	for _, lockfile := range []string{"bun.lockb", "bun.lock"} {
+		if exists, _ := fsys.FileExists(path.Join(dir, lockfile)); exists {
+			return "bun"
+		}
+	}
+
+	if exists, _ := fsys.FileExists(path.Join(dir, "pnpm-lock.yaml")); exists {
+		return "pnpm"
+	}
+
+	if exists, _ := fsys.FileExists(path.Join(dir, "yarn.lock")); exists {
+		return "yarn"
+	}
+
+	return "npm"
+}
+
+// ProjectManager returns the node package manager of a project, workspace
+// members use the manager of their workspace root.
+func ProjectManager(project types.Project) string {
+	if IsWorkspaceMember(project) {
+		return PackageManager(project.FS, project.Workspace)
+	}
+
+	return PackageManager(project.FS, project.Dir)
+}
+
+// UsesManager matches projects whose node package manager is manager.
+func UsesManager(manager string) func(types.Project) bool {
+	return func(project types.Project) bool {
+		return ProjectManager(project) == manager
+	}
+}
+
+func HasBun(project types.Project) bool {
+	return ProjectManager(project) == "bun"
+}
+
+// RunArgs returns the arguments manager needs to run a package.json script.
+func RunArgs(manager string, script string) []string {
+	if manager == "yarn" {
+		return []string{script}
+	}
+
+	return []string{"run", script}
+}
+
+// ExecArgs returns the arguments manager needs to run a binary installed
+// in node_modules, which follows them along with its own arguments.
+func ExecArgs(manager string) []string {
+	switch manager {
+	case "npm":
+		return []string{"exec", "--"}
+	case "pnpm":
+		return []string{"exec"}
+	case "bun":
+		return []string{"x"}
+	}
+
+	return []string{}
+}
+
+// InstallArgs returns the arguments manager needs to install dependencies.
+func InstallArgs(manager string) []string {
+	if manager == "yarn" {
+		return []string{}
+	}
+
+	return []string{"install"}
+}
+
+// YarnBerry reports whether dir uses yarn 2 or later, configured by a
+// .yarnrc.yml rather than the .yarnrc of yarn classic.
+func YarnBerry(fsys fsys.FS, dir string) bool {
+	exists, _ := fsys.FileExists(path.Join(dir, ".yarnrc.yml"))
+	return exists
+}
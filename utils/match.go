@@ -0,0 +1,57 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// isSubsequence reports whether every rune of query appears in s in order.
+func isSubsequence(query string, s string) bool {
+	want := []rune(query)
+	i := 0
+	for _, r := range s {
+		if i < len(want) && want[i] == r {
+			i++
+		}
+	}
+	return i == len(want)
+}
+
+// FindProject resolves a project by name, preferring exact, then prefix,
+// then substring and finally fuzzy subsequence matches.
+func FindProject(projects []File, query string) (File, error) {
+	q := strings.ToLower(query)
+	matchers := []func(string) bool{
+		func(name string) bool { return name == q },
+		func(name string) bool { return strings.HasPrefix(name, q) },
+		func(name string) bool { return strings.Contains(name, q) },
+		func(name string) bool { return isSubsequence(q, name) },
+	}
+
+	for _, matches := range matchers {
+		found := []File{}
+		for _, project := range projects {
+			if matches(strings.ToLower(project.Name)) {
+				found = append(found, project)
+			}
+		}
+
+		switch len(found) {
+		case 0:
+			continue
+		case 1:
+			return found[0], nil
+		default:
+			names := []string{}
+			for _, project := range found {
+				names = append(names, project.Name)
+			}
+			return File{}, fmt.Errorf("%q matches multiple projects: %s", query, strings.Join(names, ", "))
+		}
+	}
+
+	return File{}, fmt.Errorf("no project matches %q", query)
+}
@@ -0,0 +1,29 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import "testing"
+
+func TestIsSubsequence(t *testing.T) {
+	tests := []struct {
+		query, s string
+		want     bool
+	}{
+		{"apa", "api-gateway", true},
+		{"gw", "api-gateway", true},
+		{"wg", "api-gateway", false},
+		{"cfé", "café-frontend", true},
+		{"éc", "café-frontend", false},
+		{"caf", "café-frontend", true},
+		{"éf", "café-frontend", true},
+		{"日本", "日x本", true},
+		{"", "anything", true},
+	}
+
+	for _, test := range tests {
+		if got := isSubsequence(test.query, test.s); got != test.want {
+			t.Errorf("isSubsequence(%q, %q) = %v, want %v", test.query, test.s, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MatrixCombinations expands KEY=a,b pairs into every combination of their
+// values, each as a list of KEY=VALUE pairs in the order the keys were given.
+func MatrixCombinations(pairs []string) ([][]string, error) {
+	combinations := [][]string{{}}
+
+	for _, pair := range pairs {
+		key, values, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid matrix entry %q, expected KEY=VALUE[,VALUE...]", pair)
+		}
+
+		expanded := [][]string{}
+		for _, combination := range combinations {
+			for _, value := range strings.Split(values, ",") {
+				next := append(append([]string{}, combination...), key+"="+value)
+				expanded = append(expanded, next)
+			}
+		}
+		combinations = expanded
+	}
+
+	return combinations, nil
+}
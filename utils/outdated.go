@@ -0,0 +1,140 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path"
+	"slices"
+	"strings"
+)
+
+// OutdatedPackage is a dependency with a newer version available.
+type OutdatedPackage struct {
+	Name    string `json:"name"`
+	Manager string `json:"manager"`
+	Current string `json:"current"`
+	// Wanted is the newest version the declared constraint allows.
+	Wanted string `json:"wanted"`
+	Latest string `json:"latest"`
+}
+
+// OUTDATED_ARGS are the arguments making each tool list outdated
+// dependencies as JSON.
+var OUTDATED_ARGS = map[string][]string{
+	"npm":      {"outdated", "--json"},
+	"pnpm":     {"outdated", "--format", "json"},
+	"yarn":     {"outdated", "--json"},
+	"composer": {"outdated", "--direct", "--format=json"},
+}
+
+// Outdated lists the outdated node and composer dependencies of the project
+// in dir using its package managers. manager is its node package manager.
+func Outdated(dir string, manager string) ([]OutdatedPackage, error) {
+	packages := []OutdatedPackage{}
+	tools := []string{}
+	if manager != "" {
+		tools = append(tools, manager)
+	}
+	if exists, _ := FileExists(path.Join(dir, "composer.json")); exists {
+		tools = append(tools, "composer")
+	}
+
+	for _, tool := range tools {
+		args, ok := OUTDATED_ARGS[tool]
+		if !ok {
+			return packages, fmt.Errorf("%s can't list outdated packages as JSON", tool)
+		}
+
+		c := exec.Command(tool, args...)
+		c.Dir = dir
+		// the tools exit non-zero when something is outdated
+		out, err := c.Output()
+		if len(out) == 0 && err != nil {
+			return packages, fmt.Errorf("%s %s: %w", tool, strings.Join(args, " "), err)
+		}
+
+		found, err := ParseOutdated(tool, out)
+		if err != nil {
+			return packages, fmt.Errorf("%s %s: %w", tool, strings.Join(args, " "), err)
+		}
+		packages = append(packages, found...)
+	}
+
+	return packages, nil
+}
+
+// ParseOutdated reads the JSON output of a tool's outdated command.
+func ParseOutdated(tool string, out []byte) ([]OutdatedPackage, error) {
+	packages := []OutdatedPackage{}
+
+	switch tool {
+	case "npm", "pnpm":
+		report := map[string]struct {
+			Current string `json:"current"`
+			Wanted  string `json:"wanted"`
+			Latest  string `json:"latest"`
+		}{}
+		if err := json.Unmarshal(out, &report); err != nil {
+			return nil, err
+		}
+		for name, dep := range report {
+			packages = append(packages, OutdatedPackage{name, tool, dep.Current, dep.Wanted, dep.Latest})
+		}
+	case "yarn":
+		// yarn prints a stream of JSON objects, the table holds the result
+		scanner := bufio.NewScanner(strings.NewReader(string(out)))
+		scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+		for scanner.Scan() {
+			line := struct {
+				Type string `json:"type"`
+				Data struct {
+					Head []string   `json:"head"`
+					Body [][]string `json:"body"`
+				} `json:"data"`
+			}{}
+			if json.Unmarshal(scanner.Bytes(), &line) != nil || line.Type != "table" {
+				continue
+			}
+			for _, row := range line.Data.Body {
+				if len(row) >= 4 {
+					packages = append(packages, OutdatedPackage{row[0], tool, row[1], row[2], row[3]})
+				}
+			}
+		}
+	case "composer":
+		report := struct {
+			Installed []struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+				Latest  string `json:"latest"`
+				Status  string `json:"latest-status"`
+			} `json:"installed"`
+		}{}
+		if err := json.Unmarshal(out, &report); err != nil {
+			return nil, err
+		}
+		for _, dep := range report.Installed {
+			if dep.Status == "up-to-date" {
+				continue
+			}
+			wanted := dep.Latest
+			if dep.Status == "update-possible" {
+				wanted = dep.Version
+			}
+			packages = append(packages, OutdatedPackage{dep.Name, tool, dep.Version, wanted, dep.Latest})
+		}
+	default:
+		return nil, fmt.Errorf("unknown tool %q", tool)
+	}
+
+	slices.SortFunc(packages, func(a OutdatedPackage, b OutdatedPackage) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return packages, nil
+}
@@ -0,0 +1,258 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"jrmd.dev/qk/fsys"
+)
+
+// PhpRequirement is what a project's composer.json asks of PHP.
+type PhpRequirement struct {
+	// Constraint is require.php, such as "^8.1".
+	Constraint string
+	// Platform is config.platform.php, the version composer resolves
+	// dependencies for instead of the one running it.
+	Platform string
+}
+
+// ReadPhpRequirement reads the PHP requirement of the project in dir, ok is
+// false when composer.json doesn't state one.
+func ReadPhpRequirement(fsys fsys.FS, dir string) (PhpRequirement, bool) {
+	data, err := fsys.ReadFile(path.Join(dir, "composer.json"))
+	if err != nil {
+		return PhpRequirement{}, false
+	}
+
+	var manifest struct {
+		Require map[string]string `json:"require"`
+		Config  struct {
+			Platform map[string]string `json:"platform"`
+		} `json:"config"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return PhpRequirement{}, false
+	}
+
+	req := PhpRequirement{Constraint: manifest.Require["php"], Platform: manifest.Config.Platform["php"]}
+	return req, req.Constraint != "" || req.Platform != ""
+}
+
+// Mismatch explains why a PHP version doesn't fit the requirement, it is
+// empty when it does. The platform version only has to match up to the
+// minor version, patch releases don't change what dependencies resolve to.
+func (r PhpRequirement) Mismatch(version string) string {
+	if r.Constraint != "" && !PhpSatisfies(version, r.Constraint) {
+		return fmt.Sprintf("requires php %s", r.Constraint)
+	}
+
+	if r.Platform != "" {
+		platform, n, ok := parsePhpVersion(r.Platform)
+		current, _, _ := parsePhpVersion(version)
+		if ok && (platform[0] != current[0] || (n > 1 && platform[1] != current[1])) {
+			return fmt.Sprintf("platform is php %s", r.Platform)
+		}
+	}
+
+	return ""
+}
+
+// PhpBinary returns the php binary configured for a project in Php, falling
+// back to the one configured for "*" and then to php on the PATH.
+func PhpBinary(conf Config, project string) string {
+	binary, ok := conf.Php[project]
+	if !ok {
+		binary, ok = conf.Php["*"]
+	}
+	if !ok || binary == "" {
+		return "php"
+	}
+
+	return binary
+}
+
+var (
+	phpVersions     = map[string]string{}
+	phpVersionsLock sync.Mutex
+)
+
+// PhpVersion asks a php binary for its version, such as 8.3.6.
+func PhpVersion(binary string) (string, error) {
+	phpVersionsLock.Lock()
+	defer phpVersionsLock.Unlock()
+	if version, ok := phpVersions[binary]; ok {
+		return version, nil
+	}
+
+	out, err := exec.Command(binary, "-r", "echo PHP_VERSION;").Output()
+	if err != nil {
+		return "", fmt.Errorf("could not run %s: %w", binary, err)
+	}
+
+	version := strings.TrimSpace(string(out))
+	phpVersions[binary] = version
+	return version, nil
+}
+
+// PhpPathEnv returns a PATH variable putting the php binary configured for
+// a project first, so composer and scripts calling php run it. The binary is
+// linked as php under ~/.qk/php since it may be named php8.1 or such. ok is
+// false when no binary is configured.
+func PhpPathEnv(conf Config, project string) (string, bool) {
+	binary := PhpBinary(conf, project)
+	if binary == "php" {
+		return "", false
+	}
+
+	resolved, err := exec.LookPath(binary)
+	if err != nil {
+		slog.Warn("configured php binary not found", "project", project, "binary", binary)
+		return "", false
+	}
+
+	sum := sha256.Sum256([]byte(resolved))
+	dir, err := stateFile(path.Join("php", hex.EncodeToString(sum[:4])))
+	if err != nil {
+		return "", false
+	}
+
+	link := path.Join(dir, "php")
+	if target, err := os.Readlink(link); err != nil || target != resolved {
+		_ = os.MkdirAll(dir, 0o755)
+		_ = os.Remove(link)
+		if err := os.Symlink(resolved, link); err != nil {
+			return "", false
+		}
+	}
+
+	return "PATH=" + dir + string(os.PathListSeparator) + os.Getenv("PATH"), true
+}
+
+var (
+	phpAlternatives = regexp.MustCompile(`\s*\|\|?\s*`)
+	phpOperators    = regexp.MustCompile(`([<>=!~^]+)\s+`)
+	phpHyphenRange  = regexp.MustCompile(`(\S+)\s+-\s+(\S+)`)
+)
+
+// PhpSatisfies reports whether a version such as 8.2.12 satisfies a composer
+// constraint such as "^8.1 || ~7.4.0". Versions or constraints that can't be
+// parsed are taken to be satisfied rather than flagging false mismatches.
+func PhpSatisfies(version string, constraint string) bool {
+	v, _, ok := parsePhpVersion(version)
+	if !ok {
+		return true
+	}
+
+	constraint = phpHyphenRange.ReplaceAllString(constraint, ">=$1 <=$2")
+	constraint = phpOperators.ReplaceAllString(constraint, "$1")
+	for _, alternative := range phpAlternatives.Split(strings.TrimSpace(constraint), -1) {
+		all := true
+		for _, term := range strings.FieldsFunc(alternative, func(r rune) bool { return r == ' ' || r == ',' }) {
+			all = all && phpTermSatisfied(v, term)
+		}
+		if all {
+			return true
+		}
+	}
+
+	return false
+}
+
+func phpTermSatisfied(v [3]int, term string) bool {
+	operator := term[:len(term)-len(strings.TrimLeft(term, "<>=!~^"))]
+	rest := strings.TrimPrefix(term[len(operator):], "v")
+	if rest == "*" || rest == "x" {
+		return true
+	}
+
+	wildcard := strings.HasSuffix(rest, ".*") || strings.HasSuffix(rest, ".x")
+	want, n, ok := parsePhpVersion(strings.TrimSuffix(strings.TrimSuffix(rest, ".*"), ".x"))
+	if !ok {
+		return true
+	}
+
+	cmp := comparePhpVersions(v, want)
+	switch {
+	case wildcard:
+		return cmp >= 0 && comparePhpVersions(v, bumpPhpVersion(want, n-1)) < 0
+	case operator == "^":
+		// the first non-zero part is the one which may not change
+		i := 0
+		for i < n-1 && want[i] == 0 {
+			i++
+		}
+		return cmp >= 0 && comparePhpVersions(v, bumpPhpVersion(want, i)) < 0
+	case operator == "~":
+		return cmp >= 0 && comparePhpVersions(v, bumpPhpVersion(want, max(n-2, 0))) < 0
+	case operator == ">=":
+		return cmp >= 0
+	case operator == ">":
+		return cmp > 0
+	case operator == "<=":
+		return cmp <= 0
+	case operator == "<":
+		return cmp < 0
+	case operator == "!=" || operator == "<>":
+		return cmp != 0
+	default:
+		return cmp == 0
+	}
+}
+
+// parsePhpVersion reads up to three numeric parts of a version, ignoring
+// suffixes such as RC1 or -1ubuntu2, and returns how many were given.
+func parsePhpVersion(version string) ([3]int, int, bool) {
+	v := [3]int{}
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if end := strings.IndexFunc(version, func(r rune) bool { return r != '.' && (r < '0' || r > '9') }); end >= 0 {
+		version = version[:end]
+	}
+
+	parts := strings.Split(strings.TrimSuffix(version, "."), ".")
+	n := 0
+	for i, part := range parts {
+		if i == len(v) {
+			break
+		}
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return v, 0, false
+		}
+		v[i] = number
+		n++
+	}
+
+	return v, n, n > 0
+}
+
+func comparePhpVersions(a [3]int, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+
+	return 0
+}
+
+func bumpPhpVersion(v [3]int, i int) [3]int {
+	v[i]++
+	for j := i + 1; j < len(v); j++ {
+		v[j] = 0
+	}
+
+	return v
+}
@@ -0,0 +1,68 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import "testing"
+
+func TestPhpSatisfies(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"8.2.12", "^8.1", true},
+		{"9.0.0", "^8.1", false},
+		{"8.0.30", "^8.1", false},
+		{"0.3.1", "^0.3", true},
+		{"0.4.0", "^0.3", false},
+		{"7.4.33", "~7.4.0", true},
+		{"7.5.0", "~7.4.0", false},
+		{"8.3.0", "~8.1", true},
+		{"9.0.0", "~8.1", false},
+		{"7.4.33", "^8.1 || ~7.4.0", true},
+		{"7.3.0", "^8.1 || ~7.4.0", false},
+		{"8.2.0", "^7.4|^8.0", true},
+		{"8.2.0", ">=8.0 <8.2", false},
+		{"8.1.9", ">=8.0,<8.2", true},
+		{"8.1.9", ">= 8.0 < 8.2", true},
+		{"8.1.0", "8.0 - 8.1", true},
+		{"8.2.0", "8.0 - 8.1", false},
+		{"8.1.4", "8.1.*", true},
+		{"8.2.0", "8.1.x", false},
+		{"8.1.4", "*", true},
+		{"8.1.4", "8.1.4", true},
+		{"8.1.5", "8.1.4", false},
+		{"8.1.4", "!=8.1.4", false},
+		{"8.3.6-1ubuntu2", "^8.3", true},
+		{"8.4.0RC1", ">8.3", true},
+		{"unknown", "^8.1", true},
+		{"8.1.0", "dev-main", true},
+	}
+
+	for _, test := range tests {
+		if got := PhpSatisfies(test.version, test.constraint); got != test.want {
+			t.Errorf("PhpSatisfies(%q, %q) = %v, want %v", test.version, test.constraint, got, test.want)
+		}
+	}
+}
+
+func TestPhpRequirementMismatch(t *testing.T) {
+	tests := []struct {
+		req     PhpRequirement
+		version string
+		want    string
+	}{
+		{PhpRequirement{Constraint: "^8.1"}, "8.3.6", ""},
+		{PhpRequirement{Constraint: "^8.1"}, "7.4.33", "requires php ^8.1"},
+		{PhpRequirement{Platform: "8.1.2"}, "8.1.27", ""},
+		{PhpRequirement{Platform: "8.1"}, "8.2.0", "platform is php 8.1"},
+		{PhpRequirement{Platform: "8"}, "8.2.0", ""},
+	}
+
+	for _, test := range tests {
+		if got := test.req.Mismatch(test.version); got != test.want {
+			t.Errorf("%+v.Mismatch(%q) = %q, want %q", test.req, test.version, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// PLUGIN_PREFIX starts the name of the executables on the PATH which
+// become qk subcommands, qk-deploy being run by qk deploy.
+const PLUGIN_PREFIX = "qk-"
+
+// Plugin is an executable on the PATH run as a qk subcommand.
+type Plugin struct {
+	Name string
+	Path string
+}
+
+// FindPlugin looks up the executable on the PATH providing the subcommand
+// name, the first one found winning like in the shell.
+func FindPlugin(name string) (Plugin, bool) {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, `/\`) {
+		return Plugin{}, false
+	}
+
+	file, err := exec.LookPath(PLUGIN_PREFIX + name)
+	if err != nil {
+		return Plugin{}, false
+	}
+
+	return Plugin{Name: name, Path: file}, true
+}
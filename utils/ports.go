@@ -0,0 +1,220 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"jrmd.dev/qk/fsys"
+)
+
+// ListeningPids returns the pids of processes listening on the given TCP
+// port. It relies on lsof and returns nothing when it is unavailable.
+func ListeningPids(port int) []int {
+	out, err := exec.Command("lsof", "-t", "-i", fmt.Sprintf("tcp:%d", port), "-sTCP:LISTEN").Output()
+	if err != nil {
+		return []int{}
+	}
+
+	pids := []int{}
+	for _, field := range strings.Fields(string(out)) {
+		pid, err := strconv.Atoi(field)
+		if err != nil {
+			continue
+		}
+		pids = append(pids, pid)
+	}
+
+	return pids
+}
+
+// ProcessName returns the command name of pid, or an empty string when it
+// cannot be determined.
+func ProcessName(pid int) string {
+	out, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "comm=").Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+var (
+	// localURL matches URLs dev servers print once they listen, such as
+	// "Local: http://localhost:5173/".
+	localURL = regexp.MustCompile(`https?://(localhost|127\.0\.0\.1|0\.0\.0\.0|\[::1?\]|[A-Za-z0-9.-]+\.(test|localhost)):\d+[^\s'"]*`)
+	// listeningPort matches servers announcing just their port, such as
+	// "listening on port 3000" or "Listening on :8080".
+	listeningPort = regexp.MustCompile(`(?i)listening (?:on|at)\s+(?:port\s+|[^\s:]*:)(\d{2,5})\b`)
+)
+
+// DetectURL finds the URL a dev server announces in a line of its output.
+// Wildcard addresses are reported as localhost so they can be opened.
+func DetectURL(line string) (string, bool) {
+	line = ansi.Strip(line)
+
+	if url := localURL.FindString(line); url != "" {
+		url = strings.TrimRight(url, ".,;)")
+		url = strings.Replace(url, "://0.0.0.0:", "://localhost:", 1)
+		return strings.Replace(url, "://[::]:", "://localhost:", 1), true
+	}
+
+	if match := listeningPort.FindStringSubmatch(line); match != nil {
+		return "http://localhost:" + match[1], true
+	}
+
+	return "", false
+}
+
+// ListeningPorts returns the TCP ports processes of the process group pgid
+// listen on. It relies on lsof and returns nothing when it is unavailable.
+func ListeningPorts(pgid int) []int {
+	out, err := exec.Command("lsof", "-a", "-g", strconv.Itoa(pgid), "-iTCP", "-sTCP:LISTEN", "-P", "-n", "-Fn").Output()
+	if err != nil {
+		return []int{}
+	}
+
+	ports := []int{}
+	for _, line := range strings.Split(string(out), "\n") {
+		name, ok := strings.CutPrefix(line, "n")
+		if !ok {
+			continue
+		}
+
+		port, err := strconv.Atoi(name[strings.LastIndex(name, ":")+1:])
+		if err == nil && !slices.Contains(ports, port) {
+			ports = append(ports, port)
+		}
+	}
+
+	return ports
+}
+
+// DEFAULT_PORT_RANGE is where assigned ports come from unless PortRange is
+// configured.
+const DEFAULT_PORT_RANGE = "3000-3999"
+
+// ParsePortRange reads a range such as 3000-3999.
+func ParsePortRange(portRange string) (int, int, error) {
+	from, to, ok := strings.Cut(portRange, "-")
+	low, lowErr := strconv.Atoi(strings.TrimSpace(from))
+	high, highErr := strconv.Atoi(strings.TrimSpace(to))
+	if !ok || lowErr != nil || highErr != nil || low < 1 || high > 65535 || low > high {
+		return 0, 0, fmt.Errorf("port range must look like 3000-3999, got %q", portRange)
+	}
+
+	return low, high, nil
+}
+
+// PortFree reports whether nothing listens on port.
+func PortFree(port int) bool {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+
+	_ = listener.Close()
+	return true
+}
+
+// AssignPorts gives every project a port of its own, in the order of names:
+// the configured one when there is one, otherwise the next free port of the
+// configured range. Projects sharing a name still get ports of their own.
+func AssignPorts(conf Config, names []string) ([]int, error) {
+	portRange := conf.PortRange
+	if portRange == "" {
+		portRange = DEFAULT_PORT_RANGE
+	}
+	low, high, err := ParsePortRange(portRange)
+	if err != nil {
+		return nil, err
+	}
+
+	assigned := make([]int, len(names))
+	taken := map[int]bool{}
+	for i, name := range names {
+		if port, ok := conf.Ports[name]; ok {
+			assigned[i] = port
+			taken[port] = true
+		}
+	}
+
+	next := low
+	for i := range names {
+		if assigned[i] != 0 {
+			continue
+		}
+
+		for next <= high && (taken[next] || !PortFree(next)) {
+			next++
+		}
+		if next > high {
+			return assigned, fmt.Errorf("no free port left in %s", portRange)
+		}
+
+		assigned[i] = next
+		taken[next] = true
+	}
+
+	return assigned, nil
+}
+
+func portsFile() (string, error) {
+	return stateFile("ports.json")
+}
+
+// LastPorts returns the ports last assigned to projects, keyed by their
+// directory.
+func LastPorts() map[string]int {
+	ports := map[string]int{}
+
+	file, err := portsFile()
+	if err != nil {
+		return ports
+	}
+
+	data, err := fsys.OS.ReadFile(file)
+	if err != nil {
+		return ports
+	}
+
+	_ = json.Unmarshal(data, &ports)
+	return ports
+}
+
+// SavePorts records the ports assigned to projects, keyed by their
+// directory, on top of the ones recorded before.
+func SavePorts(ports map[string]int) error {
+	file, err := portsFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Dir(file), 0o755); err != nil {
+		return err
+	}
+
+	return withLock(file, func() error {
+		recorded := LastPorts()
+		for dir, port := range ports {
+			recorded[dir] = port
+		}
+
+		data, err := json.MarshalIndent(recorded, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		return writeAtomic(file, data)
+	})
+}
@@ -0,0 +1,25 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestSavePorts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if err := SavePorts(map[string]int{"/ws/api": 3000, "/ws/web": 3001}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SavePorts(map[string]int{"/ws/web": 3002}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]int{"/ws/api": 3000, "/ws/web": 3002}
+	if got := LastPorts(); !maps.Equal(got, want) {
+		t.Errorf("LastPorts() = %v, want %v", got, want)
+	}
+}
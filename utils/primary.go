@@ -0,0 +1,48 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"jrmd.dev/qk/types"
+)
+
+// PrimaryAction returns the configured primary URL or command of a project,
+// falling back to the one configured for "*", with template variables such
+// as {{.Name}} expanded.
+func PrimaryAction(conf Config, root string, project types.Project) (string, bool) {
+	action, ok := conf.Primary[project.Name]
+	if !ok {
+		action, ok = conf.Primary["*"]
+	}
+	if !ok || action == "" {
+		return "", false
+	}
+
+	expanded, err := ExpandTemplate(action, ProjectVars(root, project))
+	if err != nil {
+		return "", false
+	}
+
+	return expanded, true
+}
+
+// IsURL reports whether a primary action is a URL to open rather than a
+// command to run.
+func IsURL(action string) bool {
+	return strings.HasPrefix(action, "http://") || strings.HasPrefix(action, "https://")
+}
+
+// OpenURL opens url in the default browser.
+func OpenURL(url string) error {
+	opener := "xdg-open"
+	if runtime.GOOS == "darwin" {
+		opener = "open"
+	}
+
+	return exec.Command(opener, url).Start()
+}
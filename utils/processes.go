@@ -0,0 +1,285 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Process is a command spawned by qk, recorded under ~/.qk/run while it
+// runs so qk ps and qk kill can find it again, even once the qk which
+// started it was killed.
+type Process struct {
+	Project string
+	Dir     string
+	// Root is the directory qk was started from.
+	Root    string
+	Command string
+	// Pid leads the process group, unless the process is Attached.
+	Pid     int
+	Started time.Time
+	// StartTime and OwnerStartTime tell Pid and Owner apart from processes
+	// which got their pid once they exited, see StartTime.
+	StartTime      string `json:",omitempty"`
+	OwnerStartTime string `json:",omitempty"`
+	// Attached processes run in the foreground process group of qk -i, so
+	// only Pid itself is signalled.
+	Attached bool `json:",omitempty"`
+	// Owner is the pid of the qk running the process, 0 for processes
+	// started by qk watch --detach.
+	Owner int
+	// LogFile holds the output of the process, if it is logged.
+	LogFile string
+}
+
+var unsafeRunChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// RunDir returns ~/.qk/run.
+func RunDir() (string, error) {
+	return stateFile("run")
+}
+
+// processID names the files of a project's processes, unique across
+// workspaces holding projects of the same name.
+func processID(project string, dir string) string {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(dir))
+
+	return fmt.Sprintf("%s-%08x", unsafeRunChars.ReplaceAllString(project, "_"), hash.Sum32())
+}
+
+func (p Process) file() (string, error) {
+	runDir, err := RunDir()
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(runDir, fmt.Sprintf("%s-%d.json", processID(p.Project, p.Dir), p.Pid)), nil
+}
+
+// DetachedLogFile returns the log file of the project in dir when started
+// by qk watch --detach.
+func DetachedLogFile(project string, dir string) (string, error) {
+	runDir, err := RunDir()
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(runDir, processID(project, dir)+".log"), nil
+}
+
+// StartTime identifies the process pid across pid reuse by when it started:
+// the clock ticks since boot of /proc/<pid>/stat, or the start ps prints
+// where there is no /proc. It is empty once the process is gone.
+func StartTime(pid int) string {
+	if data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid)); err == nil {
+		// the command in parentheses may contain spaces, the fields after
+		// it start with the third, starttime is the 22nd
+		if end := strings.LastIndexByte(string(data), ')'); end != -1 {
+			if fields := strings.Fields(string(data[end+1:])); len(fields) > 19 {
+				return fields[19]
+			}
+		}
+		return ""
+	}
+
+	out, err := exec.Command("ps", "-o", "lstart=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+// SaveProcess records a started process, along with the start times of it
+// and its owner.
+func SaveProcess(p Process) error {
+	if p.StartTime == "" {
+		p.StartTime = StartTime(p.Pid)
+	}
+	if p.Owner != 0 && p.OwnerStartTime == "" {
+		p.OwnerStartTime = StartTime(p.Owner)
+	}
+
+	file, err := p.file()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(path.Dir(file), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, data, 0o644)
+}
+
+// Processes lists the recorded processes, running or not, by project name.
+func Processes() ([]Process, error) {
+	runDir, err := RunDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(runDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Process{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	processes := []Process{}
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		data, err := os.ReadFile(path.Join(runDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		p := Process{}
+		if err := json.Unmarshal(data, &p); err == nil {
+			processes = append(processes, p)
+		}
+	}
+
+	slices.SortFunc(processes, func(a, b Process) int {
+		return strings.Compare(a.Project, b.Project)
+	})
+
+	return processes, nil
+}
+
+// signal sends sig to the process group, or to the process when attached.
+func (p Process) signal(sig syscall.Signal) error {
+	if p.Attached {
+		return syscall.Kill(p.Pid, sig)
+	}
+
+	return syscall.Kill(-p.Pid, sig)
+}
+
+// alive reports whether pid is running and started at startTime, when it
+// is known.
+func alive(pid int, startTime string) bool {
+	if syscall.Kill(pid, 0) != nil {
+		return false
+	}
+
+	return startTime == "" || StartTime(pid) == startTime
+}
+
+// Running reports whether any process of the group is alive. A leader
+// started at another time than recorded is another process which got the
+// pid, while a group which outlived its leader is still the recorded one,
+// as pids aren't reused while a group of that id exists.
+func (p Process) Running() bool {
+	if p.Pid <= 0 {
+		return false
+	}
+	if p.Attached {
+		return alive(p.Pid, p.StartTime)
+	}
+	if syscall.Kill(-p.Pid, 0) != nil {
+		return false
+	}
+
+	started := StartTime(p.Pid)
+	return p.StartTime == "" || started == "" || started == p.StartTime
+}
+
+// Detached reports whether the process was started by qk watch --detach.
+func (p Process) Detached() bool {
+	return p.Owner == 0
+}
+
+// Orphaned reports whether the qk which started the process is gone.
+func (p Process) Orphaned() bool {
+	return !p.Detached() && !alive(p.Owner, p.OwnerStartTime)
+}
+
+// Stop asks the process group to exit, killing it once grace has passed,
+// and forgets the process. Its log file is kept.
+func (p Process) Stop(grace time.Duration) error {
+	if p.Running() {
+		if err := p.signal(syscall.SIGTERM); err != nil {
+			return err
+		}
+
+		deadline := time.Now().Add(grace)
+		for p.Running() && time.Now().Before(deadline) {
+			time.Sleep(50 * time.Millisecond)
+		}
+
+		if p.Running() {
+			if err := p.signal(syscall.SIGKILL); err != nil {
+				return err
+			}
+		}
+	}
+
+	return p.Forget()
+}
+
+// Forget removes the record of the process.
+func (p Process) Forget() error {
+	file, err := p.file()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// GroupMemory sums the resident memory of every process by process group,
+// in KiB, as reported by ps.
+func GroupMemory() map[int]int {
+	memory := map[int]int{}
+
+	out, err := exec.Command("ps", "-A", "-o", "pgid=,rss=").Output()
+	if err != nil {
+		return memory
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		pgid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		rss, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		memory[pgid] += rss
+	}
+
+	return memory
+}
@@ -0,0 +1,36 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"os"
+	"testing"
+)
+
+func TestProcessRunning(t *testing.T) {
+	pid := os.Getpid()
+	started := StartTime(pid)
+	if started == "" {
+		t.Skip("no start time for the test process")
+	}
+
+	tests := []struct {
+		name    string
+		process Process
+		want    bool
+	}{
+		{"same start", Process{Pid: pid, StartTime: started, Attached: true}, true},
+		{"unrecorded start", Process{Pid: pid, Attached: true}, true},
+		{"reused pid", Process{Pid: pid, StartTime: "1", Attached: true}, false},
+		{"no pid", Process{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.process.Running(); got != tt.want {
+				t.Errorf("Running() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
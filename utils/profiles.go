@@ -0,0 +1,49 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// activeProfile is the profile GetConfig applies, see UseProfile.
+var activeProfile = os.Getenv("QK_PROFILE")
+
+// UseProfile makes GetConfig apply the named profile, overriding
+// QK_PROFILE. An empty name applies none.
+func UseProfile(name string) {
+	activeProfile = name
+}
+
+// ActiveProfile returns the name of the profile GetConfig applies.
+func ActiveProfile() string {
+	return activeProfile
+}
+
+// CheckProfile fails when a profile is active which no config defines, so
+// a typo doesn't silently run with the wrong defaults.
+func CheckProfile() error {
+	if activeProfile == "" {
+		return nil
+	}
+
+	conf := GetConfig()
+	if _, ok := conf.Profiles[activeProfile]; ok {
+		return nil
+	}
+
+	names := []string{}
+	for name := range conf.Profiles {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("unknown profile %q, no config defines Profiles", activeProfile)
+	}
+	slices.Sort(names)
+
+	return fmt.Errorf("unknown profile %q, expected one of %s", activeProfile, strings.Join(names, ", "))
+}
@@ -0,0 +1,36 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ProjectTypeConfig adds a kind of project qk knows how to install, build,
+// test and watch. Projects containing any of Files are found and run the
+// shell snippets, extra arguments are passed on to them as "$@". A snippet
+// left empty runs nothing for that command.
+type ProjectTypeConfig struct {
+	Files   []string
+	Install string
+	Build   string
+	Test    string
+	Watch   string
+}
+
+// ValidateProjectTypes makes sure every configured project type detects
+// something and runs something.
+func ValidateProjectTypes(projectTypes map[string]ProjectTypeConfig) error {
+	for name, projectType := range projectTypes {
+		if len(projectType.Files) == 0 || slices.Contains(projectType.Files, "") {
+			return fmt.Errorf("ProjectTypes.%s.Files must list the files of its projects", name)
+		}
+		if projectType.Install == "" && projectType.Build == "" && projectType.Test == "" && projectType.Watch == "" {
+			return fmt.Errorf("ProjectTypes.%s needs at least one of Install, Build, Test or Watch", name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"os"
+	"path"
+	"slices"
+	"time"
+
+	"jrmd.dev/qk/fsys"
+)
+
+// DEFAULT_KEEP_LOGS is how many runs keep their logs unless KeepLogs is
+// configured.
+const DEFAULT_KEEP_LOGS = 50
+
+// RunDirFormat names the directory of a run's logs after its start.
+const RunDirFormat = "20060102-150405"
+
+// LogBaseDir returns the directory run logs are stored in, ~/.qk/logs unless
+// LogDir is configured.
+func LogBaseDir(conf Config) string {
+	if conf.LogDir != "" {
+		return conf.LogDir
+	}
+
+	dir, err := QkDir()
+	if err != nil {
+		return ""
+	}
+
+	return path.Join(dir, "logs")
+}
+
+// PruneLogs removes the log directories of all but the latest runs, as many
+// as KeepLogs configures. Other directories under LogDir are left alone.
+func PruneLogs(conf Config) error {
+	keep := conf.KeepLogs
+	if keep == 0 {
+		keep = DEFAULT_KEEP_LOGS
+	}
+	base := LogBaseDir(conf)
+	if keep < 0 || base == "" {
+		return nil
+	}
+
+	entries, err := fsys.OS.ReadDir(base)
+	if err != nil {
+		return nil
+	}
+
+	runs := []string{}
+	for _, entry := range entries {
+		if _, err := time.Parse(RunDirFormat, entry.Name()); err == nil && entry.IsDir() {
+			runs = append(runs, entry.Name())
+		}
+	}
+	if len(runs) <= keep {
+		return nil
+	}
+
+	// the names sort by the time the runs started
+	slices.Sort(runs)
+	for _, run := range runs[:len(runs)-keep] {
+		if err := os.RemoveAll(path.Join(base, run)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
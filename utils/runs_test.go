@@ -0,0 +1,45 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"os"
+	"path"
+	"slices"
+	"testing"
+)
+
+func TestPruneLogs(t *testing.T) {
+	base := t.TempDir()
+	for _, dir := range []string{"20250101-090000", "20250102-090000", "20250103-090000", "20250104-090000", "notes"} {
+		if err := os.MkdirAll(path.Join(base, dir, "app"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := PruneLogs(Config{LogDir: base, KeepLogs: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, entry := range entries {
+		got = append(got, entry.Name())
+	}
+
+	want := []string{"20250103-090000", "20250104-090000", "notes"}
+	if !slices.Equal(got, want) {
+		t.Errorf("kept %v, want %v", got, want)
+	}
+
+	if err := PruneLogs(Config{LogDir: base, KeepLogs: -1}); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := os.ReadDir(base); len(entries) != 3 {
+		t.Errorf("KeepLogs -1 removed logs, %d entries left", len(entries))
+	}
+}
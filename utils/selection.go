@@ -0,0 +1,132 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path"
+	"slices"
+)
+
+// QkDir returns the directory qk uses for its own state, ~/.qk.
+func QkDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(home, ".qk"), nil
+}
+
+func stateFile(name string) (string, error) {
+	dir, err := QkDir()
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(dir, name), nil
+}
+
+// readRootState reads a state file under ~/.qk mapping root directories to
+// lists of project names.
+func readRootState(name string) map[string][]string {
+	state := map[string][]string{}
+
+	file, err := stateFile(name)
+	if err != nil {
+		return state
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return state
+	}
+
+	_ = json.Unmarshal(data, &state)
+	return state
+}
+
+func writeRootState(name string, root string, names []string) error {
+	file, err := stateFile(name)
+	if err != nil {
+		return err
+	}
+
+	state := readRootState(name)
+	state[root] = names
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(path.Dir(file), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, data, 0o644)
+}
+
+// LoadSelection returns the project names last selected when running from
+// root, or nil when nothing has been recorded yet.
+func LoadSelection(root string) []string {
+	return readRootState("selections.json")[root]
+}
+
+// SaveSelection remembers the selected project names for root so they can be
+// reused with --last.
+func SaveSelection(root string, names []string) error {
+	return writeRootState("selections.json", root, names)
+}
+
+// LoadOrder returns the project order saved for root.
+func LoadOrder(root string) []string {
+	return readRootState("order.json")[root]
+}
+
+// SaveOrder remembers the order projects are displayed in for root.
+func SaveOrder(root string, names []string) error {
+	return writeRootState("order.json", root, names)
+}
+
+// SortProjects orders projects by the given names, keeping projects which
+// aren't named at the end in discovery order.
+func SortProjects(projects []File, names []string) []File {
+	sorted := slices.Clone(projects)
+	slices.SortStableFunc(sorted, func(a File, b File) int {
+		ai, bi := slices.Index(names, a.Name), slices.Index(names, b.Name)
+		if ai == -1 {
+			ai = len(names)
+		}
+		if bi == -1 {
+			bi = len(names)
+		}
+		return ai - bi
+	})
+
+	return sorted
+}
+
+// FilterProjects keeps only the projects whose name is in names, preserving
+// discovery order.
+func FilterProjects(projects []File, names []string) []File {
+	filtered := []File{}
+	for _, project := range projects {
+		if slices.Contains(names, project.Name) {
+			filtered = append(filtered, project)
+			continue
+		}
+		slog.Info("skipping project outside the selection", "project", project.Name)
+	}
+
+	for _, name := range names {
+		if !slices.ContainsFunc(projects, func(project File) bool { return project.Name == name }) {
+			slog.Warn("no project matches the selection", "name", name)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,29 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"log/slog"
+	"slices"
+)
+
+// IsSkipped reports whether the project named name is in Skip.
+func IsSkipped(conf Config, name string) bool {
+	return slices.Contains(conf.Skip, name)
+}
+
+// WithoutSkipped leaves out the skipped projects, unless they are named in
+// only: asking for a project by name runs it anyway.
+func WithoutSkipped(conf Config, projects []File, only []string) []File {
+	kept := []File{}
+	for _, project := range projects {
+		if IsSkipped(conf, project.Name) && !slices.Contains(only, project.Name) {
+			slog.Info("skipping project in the skip list", "project", project.Name)
+			continue
+		}
+		kept = append(kept, project)
+	}
+
+	return kept
+}
@@ -0,0 +1,205 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"time"
+
+	"jrmd.dev/qk/fsys"
+)
+
+// INSTALL_MARKERS are files package managers write when installing, their
+// hashes stand in for the installed dependency state.
+var INSTALL_MARKERS = []string{
+	"node_modules/.package-lock.json",
+	"node_modules/.yarn-integrity",
+	"node_modules/.yarn-state.yml",
+	"node_modules/.modules.yaml",
+	"vendor/composer/installed.json",
+}
+
+// Snapshot records the dependency state of every project in a workspace.
+type Snapshot struct {
+	Name     string
+	Root     string
+	Created  time.Time
+	Projects []ProjectSnapshot
+}
+
+// ProjectSnapshot holds the lockfile and install marker hashes of a project.
+type ProjectSnapshot struct {
+	Name      string
+	Dir       string
+	Lockfiles map[string]string
+	Markers   map[string]string
+}
+
+// SnapshotDrift describes how a project differs from its snapshot.
+type SnapshotDrift struct {
+	Project   ProjectSnapshot
+	Lockfiles []string
+	Markers   []string
+}
+
+var snapshotName = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// SnapshotDir returns where the snapshot called name is stored.
+func SnapshotDir(name string) (string, error) {
+	if !snapshotName.MatchString(name) {
+		return "", fmt.Errorf("invalid snapshot name %q, use letters, digits, '.', '_' and '-'", name)
+	}
+
+	dir, err := QkDir()
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(dir, "snapshots", name), nil
+}
+
+// SaveSnapshot records the state of projects under name, keeping a copy of
+// every lockfile so it can be restored.
+func SaveSnapshot(name string, root string, projects []File) (Snapshot, error) {
+	dir, err := SnapshotDir(name)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snapshot := Snapshot{Name: name, Root: root, Created: time.Now()}
+	for i, project := range projects {
+		state := ProjectSnapshot{
+			Name:      project.Name,
+			Dir:       project.Dir,
+			Lockfiles: HashLockfiles(project.FS, project.Dir),
+			Markers:   hashFiles(project.FS, project.Dir, INSTALL_MARKERS),
+		}
+
+		for lockfile, hash := range state.Lockfiles {
+			if hash == "" {
+				continue
+			}
+
+			data, err := project.FS.ReadFile(path.Join(project.Dir, lockfile))
+			if err != nil {
+				return Snapshot{}, err
+			}
+
+			backup := path.Join(dir, "files", fmt.Sprint(i), lockfile)
+			if err := os.MkdirAll(path.Dir(backup), 0o755); err != nil {
+				return Snapshot{}, err
+			}
+			if err := os.WriteFile(backup, data, 0o644); err != nil {
+				return Snapshot{}, err
+			}
+		}
+
+		snapshot.Projects = append(snapshot.Projects, state)
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	return snapshot, os.WriteFile(path.Join(dir, "snapshot.json"), data, 0o644)
+}
+
+// LoadSnapshot reads the snapshot called name.
+func LoadSnapshot(name string) (Snapshot, error) {
+	snapshot := Snapshot{}
+	dir, err := SnapshotDir(name)
+	if err != nil {
+		return snapshot, err
+	}
+
+	data, err := os.ReadFile(path.Join(dir, "snapshot.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return snapshot, fmt.Errorf("no snapshot called %q", name)
+	} else if err != nil {
+		return snapshot, err
+	}
+
+	return snapshot, json.Unmarshal(data, &snapshot)
+}
+
+// ListSnapshots returns the names of the saved snapshots.
+func ListSnapshots() []string {
+	names := []string{}
+	dir, err := QkDir()
+	if err != nil {
+		return names
+	}
+
+	entries, _ := os.ReadDir(path.Join(dir, "snapshots"))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// Drift compares the current state of every project in the snapshot with
+// what was recorded, returning only the projects which differ.
+func (s Snapshot) Drift() []SnapshotDrift {
+	drift := []SnapshotDrift{}
+	for _, project := range s.Projects {
+		d := SnapshotDrift{
+			Project:   project,
+			Lockfiles: ChangedLockfiles(project.Lockfiles, HashLockfiles(fsys.OS, project.Dir)),
+		}
+
+		markers := hashFiles(fsys.OS, project.Dir, INSTALL_MARKERS)
+		for _, name := range INSTALL_MARKERS {
+			if project.Markers[name] != markers[name] {
+				d.Markers = append(d.Markers, name)
+			}
+		}
+
+		if len(d.Lockfiles) > 0 || len(d.Markers) > 0 {
+			drift = append(drift, d)
+		}
+	}
+
+	return drift
+}
+
+// RestoreLockfiles writes the snapshot's copies of the changed lockfiles back
+// into the project, removing lockfiles which didn't exist at the time.
+func (s Snapshot) RestoreLockfiles(d SnapshotDrift) error {
+	dir, err := SnapshotDir(s.Name)
+	if err != nil {
+		return err
+	}
+
+	index := slices.IndexFunc(s.Projects, func(p ProjectSnapshot) bool { return p.Dir == d.Project.Dir })
+	for _, lockfile := range d.Lockfiles {
+		target := path.Join(d.Project.Dir, lockfile)
+		if d.Project.Lockfiles[lockfile] == "" {
+			if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			continue
+		}
+
+		data, err := os.ReadFile(path.Join(dir, "files", fmt.Sprint(index), lockfile))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, data, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
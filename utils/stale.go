@@ -0,0 +1,53 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"fmt"
+	"path"
+
+	"jrmd.dev/qk/fsys"
+)
+
+// LOCKFILE_INSTALLS maps a lockfile to what installing from it writes, most
+// precise first, ending with the dependency directory. The first one found
+// is compared with the lockfile.
+var LOCKFILE_INSTALLS = map[string][]string{
+	"yarn.lock":         {"node_modules/.yarn-state.yml", "node_modules/.yarn-integrity", "node_modules"},
+	"package-lock.json": {"node_modules/.package-lock.json", "node_modules"},
+	"pnpm-lock.yaml":    {"node_modules/.modules.yaml", "node_modules"},
+	"bun.lockb":         {"node_modules"},
+	"bun.lock":          {"node_modules"},
+	"composer.lock":     {"vendor/composer/installed.json", "vendor"},
+}
+
+// StaleDependencies explains, per lockfile, why the dependencies of dir look
+// out of date: they were never installed or the lockfile changed since.
+func StaleDependencies(fsys fsys.FS, dir string) map[string]string {
+	stale := map[string]string{}
+
+	for _, lockfile := range LOCKFILES {
+		locked, ok := mtime(fsys, path.Join(dir, lockfile))
+		if !ok {
+			continue
+		}
+
+		markers := LOCKFILE_INSTALLS[lockfile]
+		installed, found := int64(0), false
+		for _, marker := range markers {
+			if installed, found = mtime(fsys, path.Join(dir, marker)); found {
+				break
+			}
+		}
+
+		switch {
+		case !found:
+			stale[lockfile] = fmt.Sprintf("%s is missing", markers[len(markers)-1])
+		case locked > installed:
+			stale[lockfile] = fmt.Sprintf("%s changed since the last install", lockfile)
+		}
+	}
+
+	return stale
+}
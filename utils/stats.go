@@ -0,0 +1,107 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"math"
+	"slices"
+)
+
+// DurationStats sums up how long a command took in a project across the
+// recorded runs in which it finished, in seconds.
+type DurationStats struct {
+	Project string  `json:"project"`
+	Dir     string  `json:"dir"`
+	Command string  `json:"command"`
+	Runs    int     `json:"runs"`
+	Mean    float64 `json:"mean"`
+	P95     float64 `json:"p95"`
+	// Recent is the mean of the latest runs, Baseline the mean of the ones
+	// before them. Both are 0 without enough runs to compare.
+	Recent   float64 `json:"recent"`
+	Baseline float64 `json:"baseline"`
+	// Regressed is set when Recent exceeds Baseline by more than the
+	// threshold.
+	Regressed bool `json:"regressed"`
+}
+
+// Change is how much slower, or faster when negative, the recent runs are
+// than the ones before, as a fraction of the latter.
+func (s DurationStats) Change() float64 {
+	if s.Baseline == 0 {
+		return 0
+	}
+
+	return s.Recent/s.Baseline - 1
+}
+
+// HistoryStats computes the duration statistics of every command of every
+// project in entries, which are ordered oldest first. The last recent runs
+// of a command are compared with the ones before, which need to be at
+// least as many, and flagged as regressed when slower by more than
+// threshold, such as 0.25 for 25%.
+func HistoryStats(entries []HistoryEntry, recent int, threshold float64) []DurationStats {
+	type key struct{ dir, command string }
+	durations := map[key][]float64{}
+	names := map[key]string{}
+	order := []key{}
+
+	for _, entry := range entries {
+		for _, project := range entry.Projects {
+			for _, command := range project.Commands {
+				if command.Status != "finished" || command.Cached {
+					continue
+				}
+
+				k := key{project.Dir, command.Command}
+				if _, ok := durations[k]; !ok {
+					order = append(order, k)
+				}
+				durations[k] = append(durations[k], command.Duration)
+				names[k] = project.Project
+			}
+		}
+	}
+
+	stats := []DurationStats{}
+	for _, k := range order {
+		runs := durations[k]
+		s := DurationStats{Project: names[k], Dir: k.dir, Command: k.command, Runs: len(runs), Mean: mean(runs), P95: percentile(runs, 0.95)}
+
+		if recent > 0 && len(runs) >= 2*recent {
+			s.Recent = mean(runs[len(runs)-recent:])
+			s.Baseline = mean(runs[:len(runs)-recent])
+			s.Regressed = s.Change() > threshold
+		}
+		stats = append(stats, s)
+	}
+
+	return stats
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum / float64(len(values))
+}
+
+// percentile picks the nearest-rank percentile p, such as 0.95, of values.
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+
+	return sorted[max(rank, 0)]
+}
@@ -0,0 +1,71 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import "testing"
+
+func TestHistoryStats(t *testing.T) {
+	run := func(status string, duration float64) HistoryEntry {
+		return HistoryEntry{Projects: []HistoryProject{{
+			Project:  "app",
+			Dir:      "/ws/app",
+			Commands: []HistoryCommand{{Command: "yarn build", Status: status, Duration: duration}},
+		}}}
+	}
+
+	tests := []struct {
+		name      string
+		entries   []HistoryEntry
+		recent    int
+		threshold float64
+		want      DurationStats
+	}{
+		{
+			name:    "mean and p95",
+			entries: []HistoryEntry{run("finished", 1), run("finished", 2), run("finished", 3), run("finished", 10)},
+			want:    DurationStats{Runs: 4, Mean: 4, P95: 10},
+		},
+		{
+			name:    "failed runs are left out",
+			entries: []HistoryEntry{run("finished", 2), run("failed", 60), run("finished", 4)},
+			want:    DurationStats{Runs: 2, Mean: 3, P95: 4},
+		},
+		{
+			name:      "regressed",
+			entries:   []HistoryEntry{run("finished", 10), run("finished", 10), run("finished", 20), run("finished", 20)},
+			recent:    2,
+			threshold: 0.25,
+			want:      DurationStats{Runs: 4, Mean: 15, P95: 20, Recent: 20, Baseline: 10, Regressed: true},
+		},
+		{
+			name:      "within the threshold",
+			entries:   []HistoryEntry{run("finished", 10), run("finished", 10), run("finished", 12), run("finished", 12)},
+			recent:    2,
+			threshold: 0.25,
+			want:      DurationStats{Runs: 4, Mean: 11, P95: 12, Recent: 12, Baseline: 10},
+		},
+		{
+			name:      "too few runs to compare",
+			entries:   []HistoryEntry{run("finished", 10), run("finished", 10), run("finished", 30)},
+			recent:    2,
+			threshold: 0.25,
+			want:      DurationStats{Runs: 3, Mean: 50.0 / 3, P95: 30},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := HistoryStats(tt.entries, tt.recent, tt.threshold)
+			if len(stats) != 1 {
+				t.Fatalf("HistoryStats returned %d stats, want 1", len(stats))
+			}
+
+			want := tt.want
+			want.Project, want.Dir, want.Command = "app", "/ws/app", "yarn build"
+			if stats[0] != want {
+				t.Errorf("HistoryStats = %+v, want %+v", stats[0], want)
+			}
+		})
+	}
+}
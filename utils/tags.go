@@ -0,0 +1,55 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+// ProjectTags returns the sorted tags of the project named name. Tags list
+// project names or patterns such as "app-*".
+func ProjectTags(conf Config, name string) []string {
+	tags := []string{}
+	for tag, patterns := range conf.Tags {
+		if slices.ContainsFunc(patterns, func(pattern string) bool {
+			matched, _ := path.Match(pattern, name)
+			return matched
+		}) {
+			tags = append(tags, tag)
+		}
+	}
+	slices.Sort(tags)
+
+	return tags
+}
+
+// FilterTagged keeps the projects with any of tags, failing on tags the
+// config doesn't define.
+func FilterTagged(conf Config, projects []File, tags []string) ([]File, error) {
+	for _, tag := range tags {
+		if _, ok := conf.Tags[tag]; !ok {
+			known := slices.Sorted(maps.Keys(conf.Tags))
+			if len(known) == 0 {
+				return nil, fmt.Errorf("unknown tag %q, no Tags are configured", tag)
+			}
+			return nil, fmt.Errorf("unknown tag %q, expected one of %s", tag, strings.Join(known, ", "))
+		}
+	}
+
+	filtered := []File{}
+	for _, project := range projects {
+		if slices.ContainsFunc(ProjectTags(conf, project.Name), func(tag string) bool { return slices.Contains(tags, tag) }) {
+			filtered = append(filtered, project)
+			continue
+		}
+		slog.Info("skipping project without the tags", "project", project.Name, "tags", tags)
+	}
+
+	return filtered, nil
+}
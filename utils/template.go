@@ -0,0 +1,141 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"jrmd.dev/qk/types"
+)
+
+// TemplateVars are the per-project values available to command templates,
+// e.g. {{.Name}} or {{.Manager}}.
+type TemplateVars struct {
+	Name string
+	Dir  string
+	// Path is the project directory relative to where qk runs.
+	Path    string
+	Manager string
+	Type    string
+	Group   string
+}
+
+// ProjectVars collects the template variables of a project run from root.
+func ProjectVars(root string, project types.Project) TemplateVars {
+	rel, err := filepath.Rel(root, project.Dir)
+	if err != nil {
+		rel = project.Dir
+	}
+
+	return TemplateVars{
+		Name:    project.Name,
+		Dir:     project.Dir,
+		Path:    rel,
+		Manager: ProjectManager(project),
+		Type:    ProjectType(project.FS, project.Dir),
+		Group:   project.Group,
+	}
+}
+
+// Quoted returns the variables quoted for sh, for templates whose result
+// is split into words or run by a shell.
+func (v TemplateVars) Quoted() TemplateVars {
+	return TemplateVars{
+		Name:    ShellQuote(v.Name),
+		Dir:     ShellQuote(v.Dir),
+		Path:    ShellQuote(v.Path),
+		Manager: ShellQuote(v.Manager),
+		Type:    ShellQuote(v.Type),
+		Group:   ShellQuote(v.Group),
+	}
+}
+
+// ShellQuote quotes s for sh unless it only holds safe characters.
+func ShellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_=./:@%+,") == "" {
+		return s
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// SplitWords splits line into words like sh does, honouring single and
+// double quotes and backslashes but expanding nothing.
+func SplitWords(line string) ([]string, error) {
+	words := []string{}
+	var word strings.Builder
+	inWord := false
+	var quote rune
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else if r == '\\' && i+1 < len(runes) && strings.ContainsRune(`"\$`+"`", runes[i+1]) {
+				i++
+				word.WriteRune(runes[i])
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == '\\':
+			if i+1 < len(runes) {
+				i++
+				word.WriteRune(runes[i])
+			}
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in %s", quote, line)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
+
+// ExpandTemplate substitutes vars into text, leaving text without
+// placeholders untouched.
+func ExpandTemplate(text string, vars TemplateVars) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New("command").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, vars); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,41 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"npm run build", []string{"npm", "run", "build"}},
+		{"  spaced\tout  ", []string{"spaced", "out"}},
+		{"ls '/my projects/app'", []string{"ls", "/my projects/app"}},
+		{`echo "it's \"here\""`, []string{"echo", `it's "here"`}},
+		{`echo a\ b`, []string{"echo", "a b"}},
+		{"echo ''", []string{"echo", ""}},
+		{"ls " + ShellQuote("/it's here"), []string{"ls", "/it's here"}},
+		{"", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := SplitWords(tt.line)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SplitWords(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := SplitWords("echo 'open"); err == nil {
+		t.Error("an unterminated quote is not an error")
+	}
+}
@@ -0,0 +1,40 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"jrmd.dev/qk/types"
+)
+
+// ConfiguredTheme returns the theme named by Theme, the default one when
+// unset, with the colours of ThemeColors replaced.
+func ConfiguredTheme(conf Config) (types.Theme, error) {
+	name := conf.Theme
+	if name == "" {
+		name = "default"
+	}
+
+	theme, ok := types.THEMES[name]
+	if !ok {
+		return types.DefaultTheme(), fmt.Errorf("Theme must be one of %s, got %q", strings.Join(types.ThemeNames(), ", "), conf.Theme)
+	}
+
+	t := theme()
+	colours := []string{}
+	for colour := range conf.ThemeColors {
+		colours = append(colours, colour)
+	}
+	slices.Sort(colours)
+	for _, colour := range colours {
+		if err := t.SetColor(colour, conf.ThemeColors[colour]); err != nil {
+			return types.DefaultTheme(), fmt.Errorf("ThemeColors: %w", err)
+		}
+	}
+
+	return t, nil
+}
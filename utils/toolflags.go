@@ -0,0 +1,70 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+
+	"jrmd.dev/qk/types"
+)
+
+// TUNED_FLAGS are the performance flags added with --tuned, keyed by the
+// command they apply to. The ToolFlags config replaces a key's flags.
+var TUNED_FLAGS = map[string][]string{
+	"composer install": {"--prefer-dist", "--no-progress", "--no-interaction"},
+	"npm install":      {"--prefer-offline", "--no-audit", "--no-fund"},
+	// yarn classic only, berry configures this in .yarnrc.yml
+	"yarn install": {fmt.Sprintf("--network-concurrency=%d", runtime.NumCPU()*2)},
+}
+
+// ToolFlags returns the extra arguments configured for a command run in
+// project. Keys name a tool, optionally followed by the leading arguments
+// they apply to, e.g. "composer install" or "yarn". A bare yarn installs,
+// so "yarn install" applies to it too.
+func ToolFlags(conf Config, tuned bool, project types.Project, script string, args []string) []string {
+	flags := map[string][]string{}
+	if tuned {
+		for key, value := range TUNED_FLAGS {
+			flags[key] = value
+		}
+
+		dir := project.Dir
+		if IsWorkspaceMember(project) {
+			dir = project.Workspace
+		}
+		if YarnBerry(project.FS, dir) {
+			delete(flags, "yarn install")
+		}
+	}
+	for key, value := range conf.ToolFlags {
+		flags[key] = value
+	}
+
+	keys := []string{}
+	for key := range flags {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	if script == "yarn" && len(args) == 0 {
+		args = []string{"install"}
+	}
+
+	extra := []string{}
+	for _, key := range keys {
+		words := strings.Fields(key)
+		if len(words) == 0 || words[0] != script || len(words)-1 > len(args) {
+			continue
+		}
+
+		if slices.Equal(words[1:], args[:len(words)-1]) {
+			extra = append(extra, flags[key]...)
+		}
+	}
+
+	return extra
+}
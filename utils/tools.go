@@ -0,0 +1,111 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"encoding/json"
+	"path"
+
+	"jrmd.dev/qk/types"
+)
+
+// Tool is a linter or formatter, run in the projects which configure it.
+type Tool struct {
+	Name string
+	// Configs are the files configuring the tool, looked for in the
+	// project and the root of its workspace.
+	Configs []string
+	// PackageKey is the package.json key configuring the tool, if it can
+	// be configured there.
+	PackageKey string
+	// Php tools run from vendor/bin, or the PATH when composer didn't
+	// install them, node tools through the project's package manager.
+	Php bool
+	// Check and Fix are the command lines checking the project and fixing
+	// it, starting with the tool's binary.
+	Check []string
+	Fix   []string
+}
+
+var LINTERS = []Tool{
+	{
+		Name:       "eslint",
+		Configs:    []string{"eslint.config.js", "eslint.config.mjs", "eslint.config.cjs", "eslint.config.ts", ".eslintrc", ".eslintrc.js", ".eslintrc.cjs", ".eslintrc.json", ".eslintrc.yml", ".eslintrc.yaml"},
+		PackageKey: "eslintConfig",
+		Check:      []string{"eslint", "."},
+		Fix:        []string{"eslint", ".", "--fix"},
+	},
+	{
+		Name:    "phpcs",
+		Configs: []string{"phpcs.xml", "phpcs.xml.dist", ".phpcs.xml", ".phpcs.xml.dist"},
+		Php:     true,
+		Check:   []string{"phpcs"},
+		Fix:     []string{"phpcbf"},
+	},
+}
+
+var FORMATTERS = []Tool{
+	{
+		Name:       "prettier",
+		Configs:    []string{".prettierrc", ".prettierrc.json", ".prettierrc.json5", ".prettierrc.yml", ".prettierrc.yaml", ".prettierrc.toml", ".prettierrc.js", ".prettierrc.cjs", ".prettierrc.mjs", "prettier.config.js", "prettier.config.cjs", "prettier.config.mjs", "prettier.config.ts"},
+		PackageKey: "prettier",
+		Check:      []string{"prettier", "--check", "."},
+		Fix:        []string{"prettier", "--write", "."},
+	},
+	{
+		Name:    "php-cs-fixer",
+		Configs: []string{".php-cs-fixer.php", ".php-cs-fixer.dist.php", ".php_cs", ".php_cs.dist"},
+		Php:     true,
+		Check:   []string{"php-cs-fixer", "fix", "--dry-run", "--diff"},
+		Fix:     []string{"php-cs-fixer", "fix"},
+	},
+	{
+		Name:    "pint",
+		Configs: []string{"pint.json"},
+		Php:     true,
+		Check:   []string{"pint", "--test"},
+		Fix:     []string{"pint"},
+	},
+}
+
+// ConfiguresTool matches the projects which configure tool, or whose
+// workspace root does.
+func ConfiguresTool(tool Tool) func(types.Project) bool {
+	return func(project types.Project) bool {
+		dirs := []string{project.Dir}
+		if IsWorkspaceMember(project) {
+			dirs = append(dirs, project.Workspace)
+		}
+
+		for _, dir := range dirs {
+			for _, config := range tool.Configs {
+				if exists, _ := project.FS.FileExists(path.Join(dir, config)); exists {
+					return true
+				}
+			}
+
+			if tool.PackageKey != "" {
+				file, err := project.FS.ReadFile(path.Join(dir, "package.json"))
+				if err != nil {
+					continue
+				}
+				pkg := map[string]json.RawMessage{}
+				_ = json.Unmarshal(file, &pkg)
+				if _, ok := pkg[tool.PackageKey]; ok {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+}
+
+// HasVendorBin matches the projects in which composer installed bin.
+func HasVendorBin(bin string) func(types.Project) bool {
+	return func(project types.Project) bool {
+		exists, _ := project.FS.FileExists(path.Join(project.Dir, "vendor", "bin", bin))
+		return exists
+	}
+}
@@ -0,0 +1,32 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"path"
+	"slices"
+	"strings"
+)
+
+// WATCH_IGNORE are the patterns never watched for changes, on top of the
+// configured WatchIgnore and --ignore patterns.
+var WATCH_IGNORE = append(slices.Clone(BLACKLIST), "dist", "build", "storage", ".cache", "*.log", "*.swp", "*~")
+
+// IgnoredPath reports whether a path relative to a project matches one of
+// patterns, either by any of its segments or as a whole.
+func IgnoredPath(rel string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+
+		for _, segment := range strings.Split(rel, "/") {
+			if ok, _ := path.Match(pattern, segment); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
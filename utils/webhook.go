@@ -0,0 +1,151 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Notify configures where the summary of a run is sent once it finishes.
+type Notify struct {
+	// Webhook is the URL the summary is POSTed to.
+	Webhook string
+	// Template renders the request body instead of the summary's JSON: a
+	// name from WEBHOOK_TEMPLATES, such as "slack", or a text/template with
+	// the summary's fields, .Text and a json function quoting values.
+	Template string
+	// On is "always", the default, or "failure" to only send failed runs.
+	On string
+}
+
+// WEBHOOK_TEMPLATES are the payloads of the chat services Notify.Template
+// can name.
+var WEBHOOK_TEMPLATES = map[string]string{
+	"slack":   `{"text": {{json .Text}}}`,
+	"discord": `{"content": {{json .Text}}}`,
+}
+
+// WebhookSummary is what a webhook is sent once a run finishes: its
+// history entry, with the tail of each failed command's output, and the
+// host it ran on.
+type WebhookSummary struct {
+	HistoryEntry
+	Host string `json:"host"`
+}
+
+// Text sums the run up as a chat message: a line for the run, one for each
+// failed command and the tail of its output.
+func (s WebhookSummary) Text() string {
+	var b strings.Builder
+
+	icon := "✅"
+	if s.Status != "finished" {
+		icon = "❌"
+	}
+	duration := time.Duration(s.Duration * float64(time.Second))
+	if duration < time.Second {
+		duration = duration.Round(time.Millisecond)
+	} else {
+		duration = duration.Round(100 * time.Millisecond)
+	}
+	fmt.Fprintf(&b, "%s `%s` %s on %s in %s after %s\n", icon, s.Command, s.Status, s.Host, s.Dir, duration)
+
+	for _, project := range s.Projects {
+		for _, command := range project.Commands {
+			if command.Status != "failed" {
+				continue
+			}
+
+			fmt.Fprintf(&b, "• *%s* `%s` exited with %d\n", project.Project, command.Command, command.ExitCode)
+			if command.Tail != "" {
+				fmt.Fprintf(&b, "```\n%s\n```\n", command.Tail)
+			}
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func webhookTemplate(text string) (*template.Template, error) {
+	if named, ok := WEBHOOK_TEMPLATES[text]; ok {
+		text = named
+	}
+
+	return template.New("webhook").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(text)
+}
+
+// ValidateNotify checks the webhook URL, the template and when to send.
+func ValidateNotify(notify Notify) error {
+	if notify.Webhook != "" {
+		if u, err := url.Parse(notify.Webhook); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("Notify.Webhook must be an http or https URL, got %q", notify.Webhook)
+		}
+	}
+
+	if notify.Template != "" {
+		if _, err := webhookTemplate(notify.Template); err != nil {
+			return fmt.Errorf("Notify.Template: %w", err)
+		}
+	}
+
+	switch notify.On {
+	case "", "always", "failure":
+	default:
+		return fmt.Errorf("Notify.On must be always or failure, got %q", notify.On)
+	}
+
+	return nil
+}
+
+// SendWebhook POSTs the summary of a run to the configured webhook, unless
+// there is none or the run didn't fail and only failures are sent.
+func SendWebhook(notify Notify, summary WebhookSummary) error {
+	if notify.Webhook == "" || (notify.On == "failure" && summary.Status != "failed") {
+		return nil
+	}
+
+	var body []byte
+	if notify.Template == "" {
+		data, err := json.Marshal(summary)
+		if err != nil {
+			return err
+		}
+		body = data
+	} else {
+		tmpl, err := webhookTemplate(notify.Template)
+		if err != nil {
+			return err
+		}
+
+		var b bytes.Buffer
+		if err := tmpl.Execute(&b, summary); err != nil {
+			return err
+		}
+		body = b.Bytes()
+	}
+
+	client := http.Client{Timeout: 10 * time.Second}
+	res, err := client.Post(notify.Webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("%s answered %s", notify.Webhook, res.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendWebhook(t *testing.T) {
+	summary := WebhookSummary{
+		HistoryEntry: HistoryEntry{
+			ID:       7,
+			Command:  "build",
+			Dir:      "/work",
+			Status:   "failed",
+			Duration: 2.5,
+			Projects: []HistoryProject{{Project: "app", Dir: "/work/app", Commands: []HistoryCommand{
+				{Command: "yarn build", Status: "failed", ExitCode: 2, Tail: "error TS2322"},
+			}}},
+		},
+		Host: "ci",
+	}
+
+	tests := []struct {
+		name   string
+		notify Notify
+		status string
+		want   string
+	}{
+		{"json", Notify{}, "failed", ""},
+		{"slack", Notify{Template: "slack"}, "failed", "❌ `build` failed on ci in /work after 2.5s\n• *app* `yarn build` exited with 2\n```\nerror TS2322\n```"},
+		{"only failures", Notify{On: "failure"}, "finished", "not sent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := "not sent"
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				body = string(data)
+			}))
+			defer server.Close()
+
+			tt.notify.Webhook = server.URL
+			sent := summary
+			sent.Status = tt.status
+			if err := SendWebhook(tt.notify, sent); err != nil {
+				t.Fatal(err)
+			}
+
+			switch tt.name {
+			case "json":
+				got := WebhookSummary{}
+				if err := json.Unmarshal([]byte(body), &got); err != nil {
+					t.Fatal(err)
+				}
+				if got.ID != 7 || got.Host != "ci" || got.Projects[0].Commands[0].Tail != "error TS2322" {
+					t.Errorf("sent %s", body)
+				}
+			case "slack":
+				got := struct{ Text string }{}
+				if err := json.Unmarshal([]byte(body), &got); err != nil {
+					t.Fatal(err)
+				}
+				if got.Text != tt.want {
+					t.Errorf("sent %q, want %q", got.Text, tt.want)
+				}
+			default:
+				if body != tt.want {
+					t.Errorf("sent %q, want %q", body, tt.want)
+				}
+			}
+		})
+	}
+}
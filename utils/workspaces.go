@@ -0,0 +1,180 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"encoding/json"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"jrmd.dev/qk/discovery"
+	"jrmd.dev/qk/fsys"
+	"jrmd.dev/qk/types"
+)
+
+type workspacesPackageJSON struct {
+	Workspaces json.RawMessage `json:"workspaces"`
+}
+
+// WorkspaceGlobs returns the member globs declared by the yarn/npm
+// "workspaces" field of dir's package.json or by its pnpm-workspace.yaml.
+func WorkspaceGlobs(fsys fsys.FS, dir string) []string {
+	globs := []string{}
+
+	if file, err := fsys.ReadFile(path.Join(dir, "package.json")); err == nil {
+		pkg := workspacesPackageJSON{}
+		_ = json.Unmarshal(file, &pkg)
+
+		// workspaces is either a list of globs or {"packages": [...]}
+		if err := json.Unmarshal(pkg.Workspaces, &globs); err != nil {
+			nested := struct {
+				Packages []string `json:"packages"`
+			}{}
+			_ = json.Unmarshal(pkg.Workspaces, &nested)
+			globs = nested.Packages
+		}
+	}
+
+	if file, err := fsys.ReadFile(path.Join(dir, "pnpm-workspace.yaml")); err == nil {
+		globs = append(globs, pnpmPackages(string(file))...)
+	}
+
+	return globs
+}
+
+// pnpmPackages reads the packages list of a pnpm-workspace.yaml without
+// pulling in a yaml parser, only the plain list form is supported.
+func pnpmPackages(file string) []string {
+	packages := []string{}
+	inPackages := false
+
+	for _, line := range strings.Split(file, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "-") {
+			inPackages = strings.HasPrefix(trimmed, "packages:")
+			continue
+		}
+
+		if inPackages && strings.HasPrefix(trimmed, "-") {
+			glob := strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
+			packages = append(packages, strings.Trim(glob, `"'`))
+		}
+	}
+
+	return packages
+}
+
+// WorkspaceMembers resolves the workspace globs of root to the member
+// directories containing a package.json. Globs starting with ! exclude
+// members and ** matches any number of directories.
+func WorkspaceMembers(fsys fsys.FS, root string) []string {
+	return workspaceMembers(fsys, root, nil)
+}
+
+// workspaceMembers resolves the members of root, skipping and reporting the
+// directories walked on the way to s.
+func workspaceMembers(fsys fsys.FS, root string, s *scan) []string {
+	include, exclude := []string{}, []string{}
+	for _, glob := range WorkspaceGlobs(fsys, root) {
+		glob = strings.TrimSuffix(strings.TrimPrefix(glob, "./"), "/")
+		if negated, ok := strings.CutPrefix(glob, "!"); ok {
+			exclude = append(exclude, strings.TrimPrefix(negated, "./"))
+			continue
+		}
+		include = append(include, glob)
+	}
+
+	if len(include) == 0 {
+		return []string{}
+	}
+
+	members := []string{}
+	_ = fsys.WalkDir(root, func(dir string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return nil
+		}
+		if dir != root && s.skip(dir) {
+			return filepath.SkipDir
+		}
+		s.visited(dir)
+
+		rel, err := filepath.Rel(root, dir)
+		if err != nil || rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		matches := func(glob string) bool { return globMatch(glob, rel) }
+		if Some(include, matches) && !Some(exclude, matches) {
+			if exists, _ := fsys.FileExists(path.Join(dir, "package.json")); exists {
+				members = append(members, dir)
+			}
+		}
+
+		return nil
+	})
+
+	return members
+}
+
+// globMatch matches a slash separated path against a glob where ** spans
+// any number of path segments.
+func globMatch(glob string, name string) bool {
+	return matchSegments(strings.Split(glob, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(glob []string, name []string) bool {
+	if len(glob) == 0 {
+		return len(name) == 0
+	}
+
+	if glob[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(glob[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+
+	if ok, _ := path.Match(glob[0], name[0]); !ok {
+		return false
+	}
+
+	return matchSegments(glob[1:], name[1:])
+}
+
+// workspaceProjects lists a workspace root followed by its members. The root
+// is always included so installs have somewhere to run.
+func workspaceProjects(fsys fsys.FS, root string, members []string) []File {
+	name := path.Base(root)
+	if project, ok := discovery.Detect(fsys, root); ok {
+		name = project.Name
+	}
+
+	projects := []File{{Name: name, Dir: root, FS: fsys, Workspace: root}}
+	for _, member := range members {
+		if project, ok := discovery.Detect(fsys, member); ok {
+			projects = append(projects, File{Name: project.Name, Dir: member, FS: fsys, Workspace: root})
+		}
+	}
+
+	return projects
+}
+
+// IsWorkspaceMember matches projects belonging to a workspace other than its
+// root, their dependencies are installed by the root.
+func IsWorkspaceMember(project types.Project) bool {
+	return project.Workspace != "" && project.Workspace != project.Dir
+}
@@ -0,0 +1,68 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package utils
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"jrmd.dev/qk/fsys"
+)
+
+func TestWorkspaceMembers(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+		want  []string
+	}{
+		{
+			name: "yarn list",
+			files: fstest.MapFS{
+				"repo/package.json":             file(`{"workspaces": ["packages/*"]}`),
+				"repo/packages/a/package.json":  file(`{}`),
+				"repo/packages/b/package.json":  file(`{}`),
+				"repo/packages/notes/README.md": file(``),
+			},
+			want: []string{"/repo/packages/a", "/repo/packages/b"},
+		},
+		{
+			name: "nested packages and exclusions",
+			files: fstest.MapFS{
+				"repo/package.json":                file(`{"workspaces": {"packages": ["apps/**", "!apps/legacy"]}}`),
+				"repo/apps/web/package.json":       file(`{}`),
+				"repo/apps/tools/cli/package.json": file(`{}`),
+				"repo/apps/legacy/package.json":    file(`{}`),
+			},
+			want: []string{"/repo/apps/tools/cli", "/repo/apps/web"},
+		},
+		{
+			name: "pnpm",
+			files: fstest.MapFS{
+				"repo/package.json":           file(`{}`),
+				"repo/pnpm-workspace.yaml":    file("packages:\n  - 'libs/*'\n"),
+				"repo/libs/core/package.json": file(`{}`),
+			},
+			want: []string{"/repo/libs/core"},
+		},
+		{
+			name: "no workspace",
+			files: fstest.MapFS{
+				"repo/package.json":     file(`{}`),
+				"repo/sub/package.json": file(`{}`),
+			},
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WorkspaceMembers(fsys.New(tt.files), "/repo")
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("WorkspaceMembers = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
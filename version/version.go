@@ -0,0 +1,73 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+
+// Package version describes the running qk binary. Releases set it at build
+// time with
+//
+//	go build -ldflags "-X jrmd.dev/qk/version.Version=v0.2.0 \
+//	  -X jrmd.dev/qk/version.Commit=$(git rev-parse HEAD) \
+//	  -X jrmd.dev/qk/version.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// otherwise it falls back to what go install and go build record.
+package version
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+var (
+	Version = ""
+	Commit  = ""
+	Date    = ""
+)
+
+// Info is the version of qk and of the libraries drawing its TUI.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Date      string `json:"date,omitempty"`
+	Go        string `json:"go"`
+	Bubbletea string `json:"bubbletea,omitempty"`
+	Lipgloss  string `json:"lipgloss,omitempty"`
+}
+
+// Get returns the version of the running binary, worked out once.
+var Get = sync.OnceValue(func() Info {
+	info := Info{Version: Version, Commit: Commit, Date: Date}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		if info.Version == "" {
+			info.Version = "dev"
+		}
+		return info
+	}
+
+	info.Go = build.GoVersion
+	if info.Version == "" && build.Main.Version != "" && build.Main.Version != "(devel)" {
+		info.Version = build.Main.Version
+	}
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+	for _, setting := range build.Settings {
+		switch {
+		case setting.Key == "vcs.revision" && info.Commit == "":
+			info.Commit = setting.Value
+		case setting.Key == "vcs.time" && info.Date == "":
+			info.Date = setting.Value
+		}
+	}
+	for _, dep := range build.Deps {
+		switch {
+		case dep.Path == "github.com/charmbracelet/bubbletea":
+			info.Bubbletea = dep.Version
+		case dep.Path == "github.com/charmbracelet/lipgloss":
+			info.Lipgloss = dep.Version
+		}
+	}
+
+	return info
+})
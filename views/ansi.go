@@ -0,0 +1,60 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// escapeSequence matches CSI and OSC escape sequences.
+var escapeSequence = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)`)
+
+const resetColors = "\x1b[0m"
+
+// terminalLine turns a line of command output into what a terminal would
+// show: the part after the last carriage return, keeping only its colours so
+// cursor movement and the like can't break the TUI.
+func terminalLine(line string) string {
+	line = strings.TrimSuffix(line, "\r")
+	if i := strings.LastIndex(line, "\r"); i != -1 {
+		line = line[i+1:]
+	}
+
+	return escapeSequence.ReplaceAllStringFunc(line, func(sequence string) string {
+		if strings.HasPrefix(sequence, "\x1b[") && strings.HasSuffix(sequence, "m") {
+			return sequence
+		}
+		return ""
+	})
+}
+
+// withColors keeps a line's own colours from bleeding into what follows it,
+// or strips them when NO_COLOR is set.
+func withColors(line string) string {
+	if os.Getenv("NO_COLOR") != "" {
+		return ansi.Strip(line)
+	}
+
+	if !strings.Contains(line, "\x1b[") {
+		return line
+	}
+
+	return line + resetColors
+}
+
+// renderOutputLine renders a line of command output with its own colours,
+// or in the default output colour when it has none.
+func renderOutputLine(line string) string {
+	line = withColors(line)
+	if !strings.Contains(line, "\x1b[") {
+		return lipgloss.NewStyle().Foreground(output).Render(line)
+	}
+
+	return line
+}
@@ -0,0 +1,103 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sync"
+	"time"
+
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+const (
+	defaultMaxLineRate = 200
+	defaultMaxOutput   = 8 * 1024 * 1024
+)
+
+// outputLimiter applies backpressure to commands flooding output: lines over
+// the rate limit aren't forwarded to the TUI and output past the size limit
+// is only kept in the log file.
+type outputLimiter struct {
+	mu      sync.Mutex
+	command *types.Command
+	log     *bufio.Writer
+	window  time.Time
+	sent    int
+}
+
+func newOutputLimiter(command *types.Command, logFile io.Writer) *outputLimiter {
+	l := &outputLimiter{command: command}
+	if logFile != nil {
+		l.log = bufio.NewWriter(logFile)
+	}
+
+	return l
+}
+
+// write records a line in the log file and the command's buffers, and
+// reports whether it should be sent to the TUI.
+func (l *outputLimiter) write(line string, stream *bytes.Buffer) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.log != nil {
+		_, _ = l.log.WriteString(line + "\n")
+	}
+	l.command.OutputBytes.Add(int64(len(line) + 1))
+
+	limits := l.command.Limits
+	l.command.OutputLock.Lock()
+	if limits.MaxOutput <= 0 || l.command.Output.Len() < limits.MaxOutput {
+		l.command.Output.WriteString(line + "\n")
+		stream.WriteString(line + "\n")
+	} else {
+		l.command.Truncated.Store(true)
+	}
+	l.command.OutputLock.Unlock()
+
+	if limits.MaxLineRate <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	if now.Sub(l.window) >= time.Second {
+		l.window = now
+		l.sent = 0
+	}
+
+	if l.sent >= limits.MaxLineRate {
+		l.command.Truncated.Store(true)
+		return false
+	}
+
+	l.sent++
+	return true
+}
+
+var truncatedNotice string
+
+func (l *outputLimiter) flush() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.log != nil {
+		_ = l.log.Flush()
+	}
+}
+
+func outputLimits(conf utils.Config) types.OutputLimits {
+	limits := types.OutputLimits{MaxLineRate: defaultMaxLineRate, MaxOutput: defaultMaxOutput}
+	if conf.MaxLineRate != 0 {
+		limits.MaxLineRate = conf.MaxLineRate
+	}
+	if conf.MaxOutputBytes != 0 {
+		limits.MaxOutput = conf.MaxOutputBytes
+	}
+
+	return limits
+}
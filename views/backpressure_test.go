@@ -0,0 +1,38 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"jrmd.dev/qk/types"
+)
+
+// TestOutputStringWhileWriting reads a command's output the way the log
+// viewer does while the limiter appends to it, run with -race.
+func TestOutputStringWhileWriting(t *testing.T) {
+	command := &types.Command{Output: &bytes.Buffer{}, Stdout: &bytes.Buffer{}}
+	limiter := newOutputLimiter(command, nil)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range 1000 {
+			limiter.write("line", command.Stdout)
+		}
+	}()
+
+	for range 100 {
+		_ = command.OutputString()
+	}
+	wg.Wait()
+
+	if lines := strings.Count(command.OutputString(), "\n"); lines != 1000 {
+		t.Errorf("kept %d lines, want 1000", lines)
+	}
+}
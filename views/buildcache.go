@@ -0,0 +1,125 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+// UseBuildCache marks the commands of projects whose inputs haven't changed
+// since the last time all of them finished, and whose build outputs are
+// still there, as cached, finished without running, so unchanged projects
+// aren't built again while their dependents still start. The inputs are the project's files, its commands and the
+// inputs of the projects it depends on, so changing a dependency rebuilds
+// its dependents. With force every project runs, either way the inputs of
+// the projects whose commands all finish are recorded. Call it once the
+// build commands have been added and before the hooks.
+func (m *model) UseBuildCache(force bool) *model {
+	files := []utils.File{}
+	for _, proj := range m.projects {
+		files = append(files, utils.File{Name: proj.Name, Dir: proj.Dir, FS: proj.FS, Workspace: proj.Workspace})
+	}
+
+	hashes := map[int]string{}
+	visiting := map[int]bool{}
+	var hash func(i int) (string, error)
+	hash = func(i int) (string, error) {
+		if h, ok := hashes[i]; ok || visiting[i] {
+			return h, nil
+		}
+		visiting[i] = true
+
+		inputs, err := utils.InputsHash(m.projects[i].FS, m.projects[i].Dir)
+		if err != nil {
+			return "", err
+		}
+
+		sum := sha256.New()
+		fmt.Fprintf(sum, "inputs %s\n", inputs)
+		for _, script := range m.projects[i].Scripts {
+			fmt.Fprintf(sum, "command %s %q\n", commandLine(script), script.Env)
+		}
+		deps := utils.ProjectDependencies(m.conf, files[i], files)
+		slices.Sort(deps)
+		for _, name := range deps {
+			j := slices.IndexFunc(files, func(f utils.File) bool { return f.Name == name })
+			if j == -1 || j == i {
+				continue
+			}
+			upstream, err := hash(j)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(sum, "dependency %s %s\n", name, upstream)
+		}
+
+		hashes[i] = hex.EncodeToString(sum.Sum(nil))
+		return hashes[i], nil
+	}
+
+	m.buildHashes = map[int]string{}
+	for i, proj := range m.projects {
+		if len(proj.Scripts) == 0 {
+			continue
+		}
+
+		h, err := hash(i)
+		if err != nil {
+			slog.Warn("could not hash build inputs", "project", proj.Name, "err", err)
+			continue
+		}
+		m.buildHashes[i] = h
+	}
+
+	if force {
+		return m
+	}
+
+	cache := utils.ReadBuildCache()
+	for i, h := range m.buildHashes {
+		entry, ok := cache[m.projects[i].Dir]
+		if !ok || entry.Hash != h || !entry.OutputsExist(m.projects[i].FS) {
+			continue
+		}
+
+		fmt.Fprintf(os.Stderr, "%s %s\n", renderProjectName(m.projects[i].Name, i), lipgloss.NewStyle().Foreground(subtle).Render(fmt.Sprintf("unchanged since %s, skipped (--force rebuilds)", entry.Time.Format("2006-01-02 15:04"))))
+		now := time.Now()
+		for _, script := range m.projects[i].Scripts {
+			script.Status = "finished"
+			script.Cached = true
+			script.Queued, script.Start, script.Finish = now, now, now
+		}
+		delete(m.buildHashes, i)
+	}
+
+	return m
+}
+
+// recordBuilds remembers the inputs of the projects whose commands all
+// finished, for UseBuildCache to skip them next time.
+func (m *model) recordBuilds() {
+	entries := []utils.BuildCacheEntry{}
+	for i, h := range m.buildHashes {
+		if utils.All(m.projects[i].Scripts, func(script *types.Command) bool { return script.Status == "finished" }) {
+			entries = append(entries, utils.BuildCacheEntry{Dir: m.projects[i].Dir, Hash: h, Time: time.Now(), Outputs: utils.BuildOutputs(m.projects[i].FS, m.projects[i].Dir)})
+		}
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	if err := utils.RecordBuilds(entries...); err != nil {
+		fmt.Fprintln(os.Stderr, lipgloss.NewStyle().Foreground(errColor).Render(fmt.Sprintf("Error: could not record builds: %s", err)))
+	}
+}
@@ -4,22 +4,29 @@ Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
 package views
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"os/exec"
+	"path"
+	"slices"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
+	"jrmd.dev/qk/internal/process"
 	"jrmd.dev/qk/types"
 	"jrmd.dev/qk/utils"
+	"jrmd.dev/qk/version"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/stopwatch"
 	tea "github.com/charmbracelet/bubbletea"
@@ -27,42 +34,26 @@ import (
 )
 
 var (
-	normal    = lipgloss.Color("#EEEEEE")
-	subtle    = lipgloss.AdaptiveColor{Light: "#D9DCCF", Dark: "#383838"}
-	highlight = lipgloss.AdaptiveColor{Light: "#874BFD", Dark: "#7D56F4"}
-	special   = lipgloss.AdaptiveColor{Light: "#43BF6D", Dark: "#73F59F"}
-	errColor  = lipgloss.AdaptiveColor{Light: "#FF5555", Dark: "#FF5555"}
-	accent    = lipgloss.AdaptiveColor{Light: "#04a5e5", Dark: "#04a5e5"}
-
-	title = lipgloss.NewStyle().
-		Padding(0, 1).
-		Bold(true).
-		Foreground(normal).
-		Background(highlight)
-
-	subtitle = lipgloss.NewStyle().
-			Padding(0, 1).
-			Foreground(accent)
-
-	divider = lipgloss.NewStyle().
-		SetString("•").
-		Padding(0, 1).
-		Foreground(subtle).
-		String()
-
-	checkMark = lipgloss.NewStyle().SetString("✓").
-			Foreground(special).
-			PaddingRight(1).
-			String()
-	cross = lipgloss.NewStyle().SetString("x").
-		Foreground(errColor).
-		PaddingRight(1).
-		String()
+	normal    lipgloss.AdaptiveColor
+	output    lipgloss.AdaptiveColor
+	subtle    lipgloss.AdaptiveColor
+	muted     lipgloss.AdaptiveColor
+	highlight lipgloss.AdaptiveColor
+	special   lipgloss.AdaptiveColor
+	errColor  lipgloss.AdaptiveColor
+	warning   lipgloss.AdaptiveColor
+	accent    lipgloss.AdaptiveColor
+
+	title     lipgloss.Style
+	subtitle  lipgloss.Style
+	divider   string
+	checkMark string
+	cross     string
 
 	projectDone = func(s string) string {
 		return lipgloss.NewStyle().
 			Strikethrough(true).
-			Foreground(lipgloss.AdaptiveColor{Light: "#969B86", Dark: "#696969"}).
+			Foreground(muted).
 			Render(s)
 	}
 
@@ -72,28 +63,39 @@ var (
 			Render(s)
 	}
 
-	projectListColours = []lipgloss.Color {
-		lipgloss.Color("#15ec75"),
-		lipgloss.Color("#8310ec"),
-		lipgloss.Color("#da50e1"),
-		lipgloss.Color("#edc43e"),
-		lipgloss.Color("#b14e86"),
-		lipgloss.Color("#0d6ce9"),
-		lipgloss.Color("#f66582"),
+	projectSelected = func(s string) string {
+		return lipgloss.NewStyle().
+			Foreground(accent).
+			Bold(true).
+			Underline(true).
+			Render(s)
 	}
 
-	renderProjectName = func (s string, i int) string {
+	projectListColours []lipgloss.AdaptiveColor
+
+	renderProjectName = func(s string, i int) string {
 		index := i % len(projectListColours)
-		return lipgloss.NewStyle().Foreground(projectListColours[index]).Render(s)	
+		return lipgloss.NewStyle().Foreground(projectListColours[index]).Render(s)
 	}
 )
 
 type keyMap struct {
-	Scripts key.Binding
-	Timer   key.Binding
-	Debug   key.Binding
-	Help    key.Binding
-	Quit    key.Binding
+	Up       key.Binding
+	Down     key.Binding
+	MoveUp   key.Binding
+	MoveDown key.Binding
+	Open     key.Binding
+	Expand   key.Binding
+	Fold     key.Binding
+	Retry    key.Binding
+	Rebuild  key.Binding
+	Primary  key.Binding
+	Scripts  key.Binding
+	Timer    key.Binding
+	Debug    key.Binding
+	Joined   key.Binding
+	Help     key.Binding
+	Quit     key.Binding
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view. It's part
@@ -106,12 +108,55 @@ func (k keyMap) ShortHelp() []key.Binding {
 // key.Map interface.
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Debug, k.Scripts, k.Timer}, // first column
-		{k.Help, k.Quit},              // second column
+		{k.Up, k.Down, k.MoveUp, k.MoveDown},           // first column
+		{k.Open, k.Expand, k.Primary, k.Fold, k.Retry}, // second column
+		{k.Rebuild, k.Scripts, k.Timer, k.Debug},       // third column
+		{k.Joined, k.Help, k.Quit},                     // fourth column
 	}
 }
 
 var keys = keyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "select previous"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "select next"),
+	),
+	MoveUp: key.NewBinding(
+		key.WithKeys("shift+up", "K"),
+		key.WithHelp("⇧↑/K", "move project up"),
+	),
+	MoveDown: key.NewBinding(
+		key.WithKeys("shift+down", "J"),
+		key.WithHelp("⇧↓/J", "move project down"),
+	),
+	Open: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "view full log"),
+	),
+	Expand: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "expand output"),
+	),
+	Primary: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "open primary"),
+	),
+	Fold: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "fold group"),
+	),
+	Retry: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "retry failed"),
+	),
+	Rebuild: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "build selected"),
+		key.WithDisabled(),
+	),
 	Scripts: key.NewBinding(
 		key.WithKeys("s"),
 		key.WithHelp("s", "toggle scripts"),
@@ -124,6 +169,10 @@ var keys = keyMap{
 		key.WithKeys("d"),
 		key.WithHelp("d", "toggle debug"),
 	),
+	Joined: key.NewBinding(
+		key.WithKeys("l"),
+		key.WithHelp("l", "toggle joined logs"),
+	),
 	Help: key.NewBinding(
 		key.WithKeys("?"),
 		key.WithHelp("?", "toggle help"),
@@ -145,86 +194,53 @@ type commandFinishedMessage struct {
 	scriptIndex int
 	err         error
 }
+type scriptsStoppedMessage struct{}
+
 type programDoneMessage struct {
 	success bool
 	err     error
 }
 
-func runCommand(ctx context.Context, wg *sync.WaitGroup, program *tea.Program, projIndex int, project types.Project, scriptIndex int, command *types.Command) tea.Cmd {
+func runCommand(ctx context.Context, wg *sync.WaitGroup, program *tea.Program, events *eventStream, projIndex int, project types.Project, scriptIndex int, command *types.Command) tea.Cmd {
 	return func() tea.Msg {
 		defer wg.Done()
 
-		c := exec.CommandContext(ctx, command.Script, command.Args...)
-		c.Dir = project.Dir
-		c.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
-
-		stdout, err := c.StdoutPipe()
-		if err != nil {
-			return commandFinishedMessage{projIndex, scriptIndex, err}
+		logFile := openLog(command)
+		if logFile != nil {
+			defer logFile.Close()
 		}
 
-		stderr, err := c.StderrPipe()
-		if err != nil {
-			return commandFinishedMessage{projIndex, scriptIndex, err}
+		var logWriter io.Writer
+		if logFile != nil {
+			logWriter = logFile
 		}
-
-		if err := c.Start(); err != nil {
-			return commandFinishedMessage{projIndex, scriptIndex, err}
+		limiter := newOutputLimiter(command, logWriter)
+		tracked := trackProcess(project, command, false)
+		defer tracked.forget()
+		// Exec may wait for the command's Slot and Lock, it has only
+		// started once the process did
+		track := command.OnStart
+		command.OnStart = func(pid int) {
+			events.commandStarted(project, command)
+			track(pid)
 		}
+		err := process.Exec(ctx, project.Dir, command, func(line string, stderr bool) {
+			events.outputLine(project, command, line, stderr)
 
-		pid := c.Process.Pid
-
-		// Start goroutines to stream output
-		go func() {
-			scanner := bufio.NewScanner(stdout)
-			for scanner.Scan() {
-				select {
-				case <-ctx.Done():
-					return
-				default:
-					line := scanner.Text()
-					command.Output.WriteString(line + "\n")
-					// Send the message to the program
-					program.Send(commandOutputMessage{projIndex, scriptIndex, line})
-				}
-			}
-		}()
-
-		go func() {
-			scanner := bufio.NewScanner(stderr)
-			for scanner.Scan() {
-				select {
-				case <-ctx.Done():
-					return
-				default:
-					line := scanner.Text()
-					command.Output.WriteString(line + "\n")
-					// Send the message to the program
-					program.Send(commandOutputMessage{projIndex, scriptIndex, line})
-				}
+			buffer := command.Stdout
+			if stderr {
+				buffer = command.Stderr
 			}
-		}()
-
-		// Handle process termination
-		waitChan := make(chan error, 1)
-		go func() {
-			select {
-			case <-ctx.Done():
-				_ = syscall.Kill(-pid, syscall.SIGTERM)
-				time.Sleep(100 * time.Millisecond)
-				_ = syscall.Kill(-pid, syscall.SIGKILL)
-				waitChan <- ctx.Err()
-			case errWait := <-waitChan:
-				waitChan <- errWait
-				return
-			}
-		}()
 
-		errWait := c.Wait()
-		waitChan <- errWait
-		finalErr := <-waitChan
+			line = terminalLine(line)
+			// Send the message to the program unless it is being flooded
+			if limiter.write(line, buffer) {
+				program.Send(commandOutputMessage{projIndex, scriptIndex, line})
+			}
+		})
+		limiter.flush()
 
-		return commandFinishedMessage{projIndex, scriptIndex, finalErr}
+		return commandFinishedMessage{projIndex, scriptIndex, err}
 	}
 }
 
@@ -259,12 +275,89 @@ func wasKilledBySignal(err error) (bool, syscall.Signal) {
 	return false, 0
 }
 
+// exitCode maps the error returned by a finished command to a process exit
+// code, using -1 when the process never ran or was killed.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+
+	return -1
+}
+
 func done(success bool) tea.Cmd {
 	return func() tea.Msg {
 		return programDoneMessage{success, nil}
 	}
 }
 
+// Options configures a command runner. It is populated from the cobra flags
+// shared by every run-style command.
+type Options struct {
+	Depth  int
+	Joined bool
+	Output string
+	Only   []string
+	Last   bool
+	Pick   bool
+	Hold   bool
+	// Tags only keeps the projects with any of these configured tags.
+	Tags []string
+	// SharedCache points package managers at a cache shared by every
+	// project and serializes the ones which can't share it concurrently.
+	SharedCache bool
+	// Grace is how long commands get to shut down before being killed.
+	Grace time.Duration
+	// MaxRefresh caps how slow the TUI refreshes while idle.
+	MaxRefresh time.Duration
+	// Env holds KEY=VALUE pairs set on every command, overriding the
+	// configured environment.
+	Env []string
+	// Dotenv loads each project's .env and .env.local into its commands.
+	Dotenv bool
+	// Tuned adds the TUNED_FLAGS performance flags to known tools.
+	Tuned bool
+	// Triage walks through the failures one by one once the run is done.
+	Triage bool
+	// Pty runs commands under a pseudo-terminal so they keep their colours
+	// and progress output.
+	Pty bool
+	// Interactive runs projects one at a time attached to the terminal
+	// instead of showing the TUI, so commands can prompt for input.
+	Interactive bool
+	// Reports maps a report format, such as csv, to the file it is
+	// written to once the run is done.
+	Reports map[string]string
+	// ProbePorts inspects the sockets of running commands to find the
+	// port of dev servers which don't print their URL.
+	ProbePorts bool
+	// Events is where lifecycle events are written as NDJSON: a file, fd:N
+	// or - for stdout.
+	Events string
+	// Changed only keeps the projects with files changed since the
+	// merge-base of ChangedSince, or the default branch when it's empty,
+	// and HEAD.
+	Changed      bool
+	ChangedSince string
+	// Concurrency caps how many commands run at once, overriding the
+	// Concurrency config. Zero keeps the configured cap, negative values
+	// lift it.
+	Concurrency int
+	// FailureLines is how many lines of each failed command's output are
+	// printed once done, overriding the FailureLines config. Zero keeps
+	// the configured number, negative values print none.
+	FailureLines int
+	// InContainer is the compose service every command runs in instead of
+	// the host, or the Images entry docker run uses for projects without
+	// a compose file.
+	InContainer string
+}
+
 type model struct {
 	program       *tea.Program
 	projects      []types.Project
@@ -284,59 +377,211 @@ type model struct {
 	cancel        context.CancelFunc
 	cmdWg         sync.WaitGroup // Add WaitGroup to track running commands
 	depth         int
+	output        string
+	logDir        string
+	hold          bool
+	holding       bool
+	restart       *restartOptions
+	rebuild       *rebuildOptions
+	watching      bool
+	sharedCache   bool
+	grace         time.Duration
+	terminating   bool
+	lastActivity  time.Time
+	maxRefresh    time.Duration
+	conf          utils.Config
+	env           []string
+	dotenv        bool
+	tuned         bool
+	discovery     time.Duration
+	limits        types.OutputLimits
+	cacheLocks    map[string]*sync.Mutex
+	slots         sync.Locker
+	cursor        int
+	folded        map[string]bool
+	expanded      map[int]bool  // projects showing their live output
+	scroll        int           // first row shown when the projects don't fit
+	order         []int         // display order of projects, as indices into projects
+	deps          map[int][]int // project index to the indices it waits for
+	// installs are the commands of other projects a project waits for on
+	// top of deps, such as its workspace root's install
+	installs     map[int][]*types.Command
+	root         string
+	width        int
+	height       int
+	viewer       logViewer
+	triage       triageView
+	triaging     bool
+	reports      map[string]string
+	events       *eventStream
+	probe        bool
+	containers   bool     // poll the state of compose projects' containers
+	container    string   // compose service or image commands run in
+	matrix       []string // labels of the matrix combinations, if any
+	notice       string   // error shown until the next key press
+	interactive  bool
+	pty          bool
+	progress     progress.Model
+	buildHashes  map[int]string // project index to the hash of its build inputs
+	failureLines int
+	github       *githubOutput
+}
+
+func outputKey(projIndex int, scriptIndex int) string {
+	return fmt.Sprintf("%d-%d", projIndex, scriptIndex)
 }
 
 type outputLine struct {
-	projectName string
+	tag     string // the project's projectTag, rendered once
 	content string
 }
 
-func CreateCommandRunner(depth int, showJoined bool) model {
+func CreateCommandRunner(opts Options) model {
 	wd, err := os.Getwd()
 	if err != nil {
 		panic(err)
 	}
 
-	projects := utils.GetAllProjects(wd, depth, 0)
+	discoveryStart := time.Now()
+	projects := utils.SortProjects(utils.GetAllProjects(wd, opts.Depth, 0), utils.LoadOrder(wd))
+	discovery := time.Since(discoveryStart)
+
+	if opts.Last {
+		opts.Only = utils.LoadSelection(wd)
+		if len(opts.Only) == 0 {
+			fmt.Println(lipgloss.NewStyle().Foreground(errColor).Render("Error: no previous selection for this directory!"))
+			os.Exit(1)
+		}
+	}
+
+	conf := utils.GetConfig()
+	projects = utils.WithoutSkipped(conf, projects, opts.Only)
+
+	if len(opts.Only) > 0 {
+		projects = utils.FilterProjects(projects, opts.Only)
+		if !opts.Last {
+			_ = utils.SaveSelection(wd, opts.Only)
+		}
+	}
+
+	if len(opts.Tags) > 0 {
+		projects, err = utils.FilterTagged(conf, projects, opts.Tags)
+		if err != nil {
+			fmt.Println(lipgloss.NewStyle().Foreground(errColor).Render(fmt.Sprintf("Error: --tag: %s", err)))
+			os.Exit(1)
+		}
+	}
+
+	if opts.Pick {
+		projects, err = PickProjects(projects)
+		if err != nil {
+			fmt.Println(lipgloss.NewStyle().Foreground(errColor).Render(fmt.Sprintf("Error: %s", err)))
+			os.Exit(1)
+		}
+
+		names := []string{}
+		for _, project := range projects {
+			names = append(names, project.Name)
+		}
+		_ = utils.SaveSelection(wd, names)
+	}
 
 	if len(projects) == 0 {
 		fmt.Println(lipgloss.NewStyle().Foreground(errColor).Render("Error: no projects found!"))
 		os.Exit(1)
 	}
 
+	if opts.Changed {
+		projects, err = utils.ChangedProjects(projects, opts.ChangedSince)
+		if err != nil {
+			fmt.Println(lipgloss.NewStyle().Foreground(errColor).Render(fmt.Sprintf("Error: --changed: %s", err)))
+			os.Exit(1)
+		}
+		if len(projects) == 0 {
+			fmt.Println(lipgloss.NewStyle().Foreground(subtle).Render("No project changed"))
+			os.Exit(0)
+		}
+	}
+
+	events, err := openEvents(opts.Events)
+	if err != nil {
+		fmt.Println(lipgloss.NewStyle().Foreground(errColor).Render(fmt.Sprintf("Error: could not open --events: %s", err)))
+		os.Exit(1)
+	}
+
+	setGlyphs(conf.CheckGlyph, conf.CrossGlyph)
 	projs := []types.Project{}
+	order := []int{}
 
-	for _, project := range projects {
-		s := spinner.New()
-		s.Spinner = spinner.Dot
-		s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	for i, project := range projects {
+		order = append(order, i)
 		projs = append(projs, types.Project{
-			Spinner: s,
-			Name:    project.Name,
-			Dir:     project.Dir,
-			Scripts: []*types.Command{},
+			Spinner:   projectSpinner(conf),
+			Name:      project.Name,
+			Dir:       project.Dir,
+			FS:        project.FS,
+			Workspace: project.Workspace,
+			Group:     utils.ProjectGroup(conf, project),
+			Scripts:   []*types.Command{},
 		})
 	}
 
-	conf := utils.GetConfig()
+	if opts.InContainer != "" {
+		if err := utils.CheckContainer(conf, opts.InContainer, projs); err != nil {
+			fmt.Println(lipgloss.NewStyle().Foreground(errColor).Render(fmt.Sprintf("Error: --in-container: %s", err)))
+			os.Exit(1)
+		}
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Now()
 	return model{
 		projects:      projs,
-		start:         time.Now(),
+		start:         start,
 		finish:        time.Now(),
 		done:          false,
-		stopwatch:     stopwatch.NewWithInterval(time.Millisecond),
+		stopwatch:     stopwatch.NewWithInterval(minRefresh),
 		keys:          keys,
 		help:          help.New(),
 		showStopwatch: conf.ShowTimer,
 		showScripts:   conf.ShowScripts,
 		showStdout:    conf.ShowStdout,
-		showJoined:    showJoined,
+		showJoined:    opts.Joined,
 		ctx:           ctx,
 		cancel:        cancel,
 		liveOutput:    make(map[string][]string),
-		joinedOutput: []outputLine{},
-		depth: depth,
+		joinedOutput:  []outputLine{},
+		depth:         opts.Depth,
+		output:        opts.Output,
+		logDir:        runLogDir(conf, start),
+		hold:          opts.Hold && !headless(opts.Output),
+		sharedCache:   opts.SharedCache,
+		grace:         opts.Grace,
+		lastActivity:  start,
+		maxRefresh:    max(opts.MaxRefresh, minRefresh),
+		conf:          conf,
+		env:           opts.Env,
+		dotenv:        opts.Dotenv,
+		tuned:         opts.Tuned,
+		discovery:     discovery,
+		triaging:      opts.Triage && !headless(opts.Output),
+		reports:       opts.Reports,
+		events:        events,
+		probe:         opts.ProbePorts && !headless(opts.Output),
+		interactive:   opts.Interactive && !headless(opts.Output),
+		pty:           opts.Pty,
+		progress:      newProgressBar(),
+		limits:        outputLimits(conf),
+		failureLines:  failureLines(conf, opts.FailureLines),
+		container:     opts.InContainer,
+		cacheLocks:    map[string]*sync.Mutex{},
+		installs:      map[int][]*types.Command{},
+		slots:         newSlots(concurrency(conf, opts.Concurrency)),
+		viewer:        newLogViewer(),
+		order:         order,
+		folded:        map[string]bool{},
+		expanded:      map[int]bool{},
+		root:          wd,
 	}
 }
 
@@ -346,34 +591,139 @@ func (m *model) SetProgram(p *tea.Program) *model {
 }
 
 func (m *model) Run() {
-	p := tea.NewProgram(m)
+	if m.interactive {
+		m.runAttached()
+		m.events.runFinished(!m.anyFailed(), m.timing())
+		m.refreshContainers()
+		m.writeReports()
+		m.recordBuilds()
+		m.sendWebhook(m.recordHistory())
+		fmt.Print("\n" + m.fitWidth(m.Output(0)))
+		return
+	}
+
+	opts := []tea.ProgramOption{}
+	if headless(m.output) {
+		opts = append(opts, tea.WithoutRenderer(), tea.WithInput(nil))
+	}
+
+	p := tea.NewProgram(m, opts...)
 	m.SetProgram(p)
 
-	if _, err := p.Run(); err != nil {
+	// diagnostics would tear through the TUI, hold them until it is gone
+	diagnostics := &bytes.Buffer{}
+	restoreLog := func() {}
+	if !headless(m.output) {
+		restoreLog = utils.LogTo(diagnostics)
+	}
+
+	_, err := p.Run()
+	restoreLog()
+	_, _ = os.Stderr.Write(diagnostics.Bytes())
+	if err != nil {
 		fmt.Println("could not run program:", err)
 		os.Exit(1)
 	}
 
-	fmt.Print(m.Output(0))
+	m.events.runFinished(!m.anyFailed(), m.timing())
+	m.refreshContainers()
+
+	m.writeReports()
+	m.recordBuilds()
+	m.sendWebhook(m.recordHistory())
+
+	switch m.output {
+	case OutputJSON:
+		fmt.Print(m.JSON())
+		return
+	case OutputGitHub:
+		fmt.Print(m.GitHub())
+		return
+	}
+
+	if width := terminalWidth(); width > 0 {
+		m.width = width
+	}
+	fmt.Print(m.fitWidth(m.Output(0)))
 }
 
-func (m *model) AddCommand(render func(*types.Command, bool) string, script string, args ...string) *model {
+func (m *model) newCommand(projIndex int, renderer types.CommandRenderer, script string, args []string) *types.Command {
+	ctx, cancel := context.WithCancel(context.Background())
+	args = append(slices.Clone(args), utils.ToolFlags(m.conf, m.tuned, m.projects[projIndex], script, args)...)
+	cmd := &types.Command{Script: script, Args: args, Status: "running", Ctx: ctx, Cancel: cancel, Output: bytes.NewBuffer([]byte{}), Stdout: bytes.NewBuffer([]byte{}), Stderr: bytes.NewBuffer([]byte{}), Renderer: renderer, Reader: nil, Grace: m.grace, Limits: m.limits, Pty: m.pty}
+
+	if m.dotenv {
+		cmd.Env = append(cmd.Env, utils.LoadDotenv(m.projects[projIndex].FS, m.projects[projIndex].Dir)...)
+	}
+	if pathEnv, ok := utils.PhpPathEnv(m.conf, m.projects[projIndex].Name); ok {
+		cmd.Env = append(cmd.Env, pathEnv)
+	}
+	if port := m.projects[projIndex].Port; port > 0 {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("PORT=%d", port))
+	}
+	cmd.Env = append(cmd.Env, utils.ProjectEnv(m.conf, m.projects[projIndex].Name)...)
+	cmd.Env = append(cmd.Env, m.env...)
+	// the shared caches live on the host
+	containerEnv := slices.Clone(cmd.Env)
+
+	if m.sharedCache {
+		cmd.Env = append(cmd.Env, utils.SharedCacheEnv(script)...)
+		if spec, ok := utils.CACHES[script]; ok && spec.Serialize {
+			if m.cacheLocks[script] == nil {
+				m.cacheLocks[script] = &sync.Mutex{}
+			}
+			cmd.Lock = m.cacheLocks[script]
+		}
+	}
+
+	cmd.Slot = m.slots
+
+	if m.logDir != "" {
+		cmd.LogFile = path.Join(m.logDir, m.projects[projIndex].Name, logFileName(len(m.projects[projIndex].Scripts), cmd))
+	}
+
+	if m.container != "" {
+		cmd.Script, cmd.Args = utils.InContainer(m.conf, m.container, m.projects[projIndex].Dir, containerEnv, m.pty, cmd.Script, cmd.Args)
+	}
+
+	return cmd
+}
+
+func (m *model) AddCommand(renderer types.CommandRenderer, script string, args ...string) *model {
 	for i := range m.projects {
-		ctx, cancel := context.WithCancel(context.Background())
-		cmd := &types.Command{Script: script, Args: args, Status: "running", Ctx: ctx, Cancel: cancel, Output: bytes.NewBuffer([]byte{}), Render: render, Reader: nil}
+		cmd := m.newCommand(i, renderer, script, args)
 		m.projects[i].Scripts = append(m.projects[i].Scripts, cmd)
 	}
 	return m
 }
 
-func (m *model) AddOptionalCommand(shouldAdd func(types.Project) bool, render func(*types.Command, bool) string, script string, args ...string) *model {
+func (m *model) AddOptionalCommand(shouldAdd func(types.Project) bool, renderer types.CommandRenderer, script string, args ...string) *model {
 	for i, proj := range m.projects {
 		if shouldAdd(proj) {
-			ctx, cancel := context.WithCancel(context.Background())
-			cmd := &types.Command{Script: script, Args: args, Status: "running", Ctx: ctx, Cancel: cancel, Output: bytes.NewBuffer([]byte{}), Render: render, Reader: nil}
+			cmd := m.newCommand(i, renderer, script, args)
 
 			m.projects[i].Scripts = append(m.projects[i].Scripts, cmd)
+			continue
+		}
+		slog.Info("command does not apply to project", "project", proj.Name, "command", script)
+	}
+	return m
+}
+
+// AddOptionalCommandAfter adds a command like AddOptionalCommand, starting
+// it once the commands added to the project before it finished.
+func (m *model) AddOptionalCommandAfter(shouldAdd func(types.Project) bool, renderer types.CommandRenderer, script string, args ...string) *model {
+	for i, proj := range m.projects {
+		if !shouldAdd(proj) {
+			slog.Info("command does not apply to project", "project", proj.Name, "command", script)
+			continue
+		}
+
+		cmd := m.newCommand(i, renderer, script, args)
+		for _, earlier := range proj.Scripts {
+			cmd.Stage = max(cmd.Stage, earlier.Stage+1)
 		}
+		m.projects[i].Scripts = append(m.projects[i].Scripts, cmd)
 	}
 	return m
 }
@@ -382,9 +732,27 @@ func (m *model) Init() tea.Cmd {
 	cmds := []tea.Cmd{
 		m.stopwatch.Init(),
 	}
+	if m.restart != nil {
+		cmds = append(cmds, m.watchFiles())
+	}
+	if m.probe {
+		cmds = append(cmds, probePortsTick())
+	}
+	if m.containers {
+		cmds = append(cmds, m.readContainers(), containersTick())
+	}
 	for i, proj := range m.projects {
-		cmds = append(cmds, proj.Spinner.Tick)
+		cmds = append(cmds, proj.Spinner.Tick, m.readGitState(i))
 		for j, script := range proj.Scripts {
+			if script.Cached {
+				continue
+			}
+			script.Queued = time.Now()
+			if len(m.deps[i]) > 0 || len(m.installs[i]) > 0 || utils.Some(proj.Scripts, func(other *types.Command) bool { return other.Stage < script.Stage }) {
+				script.Status = "waiting"
+				continue
+			}
+
 			m.cmdWg.Add(1)
 			cmds = append(
 				cmds,
@@ -392,6 +760,7 @@ func (m *model) Init() tea.Cmd {
 					script.Ctx,
 					&m.cmdWg,
 					m.program,
+					m.events,
 					i,
 					proj,
 					j,
@@ -401,27 +770,88 @@ func (m *model) Init() tea.Cmd {
 
 		}
 	}
+	// dependents of projects with nothing to run never hear of a finished
+	// command, start them now
+	cmds = append(cmds, m.startReady()...)
+	if !utils.Some(m.projects, func(proj types.Project) bool {
+		return utils.Some(proj.Scripts, func(script *types.Command) bool { return !script.Cached })
+	}) && utils.Some(m.projects, func(proj types.Project) bool { return len(proj.Scripts) > 0 }) {
+		// every command was cached, there is nothing to wait for
+		cmds = append(cmds, done(true))
+	}
 	return tea.Batch(cmds...)
 }
 
 func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg.(type) {
+	case tea.KeyMsg, tea.WindowSizeMsg, commandOutputMessage, commandFinishedMessage, portsProbedMessage:
+		m.markActivity()
+	case stopwatch.TickMsg:
+		m.adjustRefresh()
+	}
+
 	var stopwatchCmd tea.Cmd
 	m.stopwatch, stopwatchCmd = m.stopwatch.Update(msg)
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.resizeViewer()
+		m.resizeTriage()
+		return m, stopwatchCmd
 	case tea.KeyMsg:
+		if m.viewer.open {
+			return m, tea.Batch(m.updateViewer(msg), stopwatchCmd)
+		}
+
+		if m.triage.open {
+			return m, tea.Batch(m.updateTriage(msg), stopwatchCmd)
+		}
+
+		m.notice = ""
 		switch {
+		case key.Matches(msg, m.keys.Up):
+			m.cursor = max(m.cursor-1, 0)
+		case key.Matches(msg, m.keys.Down):
+			m.cursor = min(m.cursor+1, len(m.rows())-1)
+		case key.Matches(msg, m.keys.MoveUp):
+			m.moveSelected(-1)
+		case key.Matches(msg, m.keys.MoveDown):
+			m.moveSelected(1)
+		case key.Matches(msg, m.keys.Fold):
+			m.toggleFold()
+		case key.Matches(msg, m.keys.Expand):
+			if i := m.selected(); i != -1 {
+				m.expanded[i] = !m.expanded[i]
+			}
+		case key.Matches(msg, m.keys.Open):
+			if m.selected() == -1 {
+				m.toggleFold()
+				break
+			}
+			return m, tea.Batch(m.openViewer(m.selected()), stopwatchCmd)
+		case key.Matches(msg, m.keys.Retry):
+			return m, tea.Batch(m.retry(), stopwatchCmd)
+		case key.Matches(msg, m.keys.Primary):
+			return m, tea.Batch(m.openPrimary(), stopwatchCmd)
+		case key.Matches(msg, m.keys.Rebuild):
+			return m, tea.Batch(m.rebuildSelected(), stopwatchCmd)
 		case key.Matches(msg, m.keys.Scripts):
 			m.showScripts = !m.showScripts
 		case key.Matches(msg, m.keys.Timer):
 			m.showStopwatch = !m.showStopwatch
 		case key.Matches(msg, m.keys.Debug):
 			m.showStdout = !m.showStdout
+		case key.Matches(msg, m.keys.Joined):
+			m.showJoined = !m.showJoined
 		case key.Matches(msg, m.keys.Help):
 			m.help.ShowAll = !m.help.ShowAll
 		case key.Matches(msg, m.keys.Quit):
-			m.CancelScripts()
-			m.cmdWg.Wait()
-			return m, tea.Quit
+			if m.terminating {
+				m.KillScripts()
+				return m, tea.Quit
+			}
+			return m, tea.Batch(m.terminate(), stopwatchCmd)
 		}
 		return m, stopwatchCmd
 	case spinner.TickMsg:
@@ -432,25 +862,52 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 		return m, tea.Batch(cmds...)
+	case filesChangedMessage:
+		cmds := m.restartProject(msg.index)
+		return m, tea.Batch(append(cmds, stopwatchCmd)...)
 	case commandFinishedMessage:
+		script := m.projects[msg.index].Scripts[msg.scriptIndex]
+		if script.Status == "restarting" && !m.terminating {
+			return m, tea.Batch(m.rerun(msg.index, msg.scriptIndex), stopwatchCmd)
+		}
+
 		status := "finished"
 		if msg.err != nil {
 			status = "failed"
 
 			wasKilled, _ := wasKilledBySignal(msg.err)
 
-			if wasKilled {
+			if wasKilled || script.Ctx.Err() != nil {
 				status = "exited"
 			}
 		}
 
-		m.projects[msg.index].Scripts[msg.scriptIndex].Status = status
+		script.Status = status
+		script.Finish = time.Now()
+		script.ExitCode = exitCode(msg.err)
+		m.events.commandFinished(m.projects[msg.index], script)
+		m.forgetURL(msg.index)
+		// once startReady skipped what the failure holds up
+		defer m.streamGitHub()
+
+		if status == "failed" {
+			if cmd, ok := m.remediate(msg.index, msg.scriptIndex); ok {
+				return m, tea.Batch(cmd, stopwatchCmd)
+			}
+			m.notifyFailure()
+		}
+
 		success := true
 		m.done = true
 
+		if cmds := m.startReady(); len(cmds) > 0 {
+			m.done = false
+			return m, tea.Batch(append(cmds, stopwatchCmd)...)
+		}
+
 		if utils.Some(m.projects, func(project types.Project) bool {
 			return utils.Some(project.Scripts, func(script *types.Command) bool {
-				return script.Status == "running"
+				return script.Status == "running" || script.Status == "terminating" || script.Status == "restarting" || script.Status == "remediating"
 			})
 		}) {
 			m.done = false
@@ -459,7 +916,7 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		if utils.Some(m.projects, func(project types.Project) bool {
 			return utils.Some(project.Scripts, func(script *types.Command) bool {
-				return script.Status == "failed"
+				return script.Status == "failed" || script.Status == "skipped"
 			})
 		}) {
 			success = false
@@ -469,33 +926,92 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, stopwatchCmd
 		}
 
+		// keep the runner open until the next change restarts a project
+		if m.restart != nil && !m.terminating {
+			m.done = false
+			m.watching = true
+			return m, stopwatchCmd
+		}
+
+		// walk through the failures before finishing
+		if !success && m.triaging && !m.terminating {
+			m.done = false
+			m.holding = true
+			return m, tea.Batch(m.openTriage(), stopwatchCmd)
+		}
+
+		// keep the runner open so failed commands can be retried
+		if !success && m.hold && !m.terminating {
+			m.done = false
+			m.holding = true
+			return m, stopwatchCmd
+		}
+
+		m.checkLockfiles()
 		return m, tea.Batch(done(success), stopwatchCmd)
 	case programDoneMessage:
 		m.CancelScripts()
 		return m, tea.Quit
-	case commandOutputMessage:
-		key := fmt.Sprintf("%d-%d", msg.index, msg.scriptIndex)
-
-		if m.showJoined {
-			m.joinedOutput = append(m.joinedOutput, outputLine {
-				projectName: fmt.Sprintf(
-					"%s (%s)", 
-					renderProjectName(m.projects[msg.index].Name, msg.index), 
-					m.projects[msg.index].Scripts[msg.scriptIndex].Render(m.projects[msg.index].Scripts[msg.scriptIndex], false),
-				),
-				content: msg.output,
-			})
-		} else {
-			if m.liveOutput[key] == nil {
-				m.liveOutput[key] = []string{}
+	case remediatedMessage:
+		if m.terminating {
+			m.projects[msg.index].Scripts[msg.scriptIndex].Status = "failed"
+			return m, stopwatchCmd
+		}
+		return m, tea.Batch(m.rerun(msg.index, msg.scriptIndex), stopwatchCmd)
+	case primaryDoneMessage:
+		m.notice = primaryNotice(msg)
+		return m, stopwatchCmd
+	case triageExecMessage:
+		m.refreshTriage()
+		return m, stopwatchCmd
+	case scriptsStoppedMessage:
+		return m, tea.Quit
+	case gitStateMessage:
+		m.projects[msg.index].Git = msg.state
+		return m, stopwatchCmd
+	case containersTickMessage:
+		if m.done {
+			return m, stopwatchCmd
+		}
+		return m, tea.Batch(m.readContainers(), containersTick(), stopwatchCmd)
+	case containersMessage:
+		m.projects[msg.index].Containers = msg.state
+		return m, stopwatchCmd
+	case probePortsMessage:
+		return m, tea.Batch(m.probePorts(), stopwatchCmd)
+	case portsProbedMessage:
+		for i, url := range msg.urls {
+			if m.projects[i].URL == "" {
+				m.projects[i].URL = url
 			}
-			m.liveOutput[key] = append(m.liveOutput[key], msg.output)
+		}
+		if m.done {
+			return m, stopwatchCmd
+		}
+		return m, tea.Batch(probePortsTick(), stopwatchCmd)
+	case commandOutputMessage:
+		key := outputKey(msg.index, msg.scriptIndex)
 
-			// Keep only last N lines to prevent memory issues
-			maxLines := 50
-			if len(m.liveOutput[key]) > maxLines {
-				m.liveOutput[key] = m.liveOutput[key][len(m.liveOutput[key])-maxLines:]
-			}
+		if m.viewer.open && m.viewer.project == msg.index {
+			m.refreshViewer()
+		}
+
+		if m.triage.open {
+			m.refreshTriage()
+		}
+
+		m.recordJoined(msg.index, msg.output)
+		m.detectURL(msg.index, msg.output)
+
+		if m.liveOutput[key] == nil {
+			m.liveOutput[key] = []string{}
+		}
+		m.liveOutput[key] = append(m.liveOutput[key], msg.output)
+
+		// Keep only last N lines to prevent memory issues
+		maxLines := 50
+		if len(m.liveOutput[key]) > maxLines {
+			m.liveOutput[key] = m.liveOutput[key][len(m.liveOutput[key])-maxLines:]
 		}
 
 		return m, stopwatchCmd
@@ -513,90 +1029,113 @@ func (m *model) CancelScripts() {
 
 }
 
-func (m *model) Output(maxLines int) (s string) {
-	gap := " "
+// KillScripts immediately kills every running process group without waiting
+// for the grace period.
+func (m *model) KillScripts() {
+	for _, p := range m.projects {
+		for _, c := range p.Scripts {
+			if c.Pid > 0 && (c.Status == "running" || c.Status == "terminating" || c.Status == "restarting") {
+				slog.Info("sending SIGKILL", "project", p.Name, "command", c.Script, "pid", c.Pid)
+				_ = syscall.Kill(-c.Pid, syscall.SIGKILL)
+			}
+		}
+	}
+}
 
-	if m.showJoined && !m.done {
-		for _, output := range m.joinedOutput {
-			s += fmt.Sprintf("%s: %s\n", output.projectName, output.content)
+// terminate asks all running commands to stop and waits for them in the
+// background so the TUI can show their progress.
+func (m *model) terminate() tea.Cmd {
+	m.terminating = true
+	m.holding = false
+	m.watching = false
+	m.cancel()
+	for _, p := range m.projects {
+		for _, c := range p.Scripts {
+			if c.Status == "running" || c.Status == "restarting" {
+				c.Status = "terminating"
+			}
 		}
-		return s
 	}
+	m.CancelScripts()
 
-	s += fmt.Sprintf("%s  %s\n\n", title.Render("QK Command Runner"), subtitle.Render("v0.1.0"))
+	return func() tea.Msg {
+		m.cmdWg.Wait()
+		return scriptsStoppedMessage{}
+	}
+}
 
-	for i, proj := range m.projects {
-		allFinished := utils.All(proj.Scripts, func(script *types.Command) bool {
-			return script.Status == "failed" || script.Status == "finished"
-		})
+func (m *model) Output(maxLines int) (s string) {
+	if m.showJoined && !m.done {
+		return m.joinedView()
+	}
 
-		hasError := utils.Some(proj.Scripts, func(script *types.Command) bool {
-			return script.Status == "failed"
-		})
-		spin := proj.Spinner.View()
+	s += fmt.Sprintf("%s  %s\n\n", title.Render("QK Command Runner"), subtitle.Render(version.Get().Version))
 
-		if hasError {
-			spin = cross
-		} else if allFinished {
-			spin = checkMark
-		}
+	if !m.done {
+		s += m.progressBar()
+	}
 
-		name := projectStyle(proj.Name)
-		if allFinished && !hasError {
-			name = projectDone(proj.Name)
+	rows := []string{}
+	for pos, row := range m.rows() {
+		selected := !m.done && pos == m.cursor
+		if row.project == -1 {
+			rows = append(rows, m.renderGroup(row.group, selected))
+			continue
 		}
 
-		s += fmt.Sprintf("%s%s%s\n", spin, gap, name)
-
-		if ((!allFinished || hasError) && (m.showScripts || m.done)) || m.showStdout {
-			for j, script := range proj.Scripts {
-				if m.done || m.showScripts {
-					if j > 0 && !m.showStdout {
-						s += divider
-					}
-					s += fmt.Sprintf("   %s", script.Render(script, true))
-				}
+		rows = append(rows, m.renderProject(row.project, selected, maxLines))
+	}
 
-				// Show live output if debug mode is on
-				if m.showStdout {
-					key := fmt.Sprintf("%d-%d", i, j)
-					stdOut := ""
-					if output, exists := m.liveOutput[key]; exists && len(output) > 0 {
-						data := output
-						if maxLines > 0 && len(data) > maxLines {
-							data = output[len(data)-maxLines:]
-						}
-
-						for _, line := range data {
-							stdOut += fmt.Sprintf("     %s\n",
-								lipgloss.NewStyle().
-									Foreground(normal).
-									Render(line))
-						}
-					}
+	footer := m.footer()
 
-					if len(stdOut) > 0 {
-						s += "\n"
-						s += stdOut
-					}
-				}
-			}
-			s += "\n"
-		}
+	// page through the projects when they don't fit the terminal
+	if !m.done && m.height > 0 {
+		rows = m.visibleRows(rows, m.height-lineCount(s)-lineCount(footer))
 	}
 
+	return s + strings.Join(rows, "") + footer
+}
+
+// footer renders the summary, status notices and key legend shown beneath
+// both the standard and joined views.
+func (m *model) footer() string {
+	footer := ""
 	if m.done {
-		s += fmt.Sprintf("\nFinished in %s\n", time.Since(m.start))
+		footer += m.changedLockfiles()
+		footer += m.remediations()
+		footer += m.failureTails()
+		footer += m.failedLogs()
+		footer += fmt.Sprintf("\nFinished in %s\n", time.Since(m.start))
+		if len(m.matrix) > 0 {
+			footer += m.matrixGrid()
+		} else {
+			footer += m.timingSummary()
+		}
 	} else if m.showStopwatch {
-		s += fmt.Sprintf("Elapsed: %s\n", m.stopwatch.View())
+		footer += fmt.Sprintf("Elapsed: %s\n", m.elapsed())
 	}
 
-	if !m.done {
-		s += m.help.View(m.keys)
+	if m.terminating && !m.done {
+		footer += lipgloss.NewStyle().Foreground(errColor).Render("Terminating… press q again to kill immediately") + "\n"
 	}
 
-	return s
+	if m.watching {
+		footer += lipgloss.NewStyle().Foreground(accent).Render("Waiting for changes, press q to quit") + "\n"
+	}
+
+	if m.holding {
+		footer += lipgloss.NewStyle().Foreground(errColor).Render("Finished with failures, press r to retry or q to quit") + "\n"
+	}
 
+	if m.notice != "" && !m.done {
+		footer += lipgloss.NewStyle().Foreground(errColor).Render(m.notice) + "\n"
+	}
+
+	if !m.done {
+		footer += m.help.View(m.legend())
+	}
+
+	return footer
 }
 
 func (m *model) View() (s string) {
@@ -604,5 +1143,100 @@ func (m *model) View() (s string) {
 		return s
 	}
 
-	return m.Output(10)
+	if m.viewer.open {
+		return m.viewerView()
+	}
+
+	if m.triage.open {
+		return m.triageView()
+	}
+
+	return m.fitScreen(m.Output(10))
+}
+
+// renderProject renders a project's status line, followed by its scripts and
+// live output when they're shown.
+func (m *model) renderProject(i int, selected bool, maxLines int) (s string) {
+	gap := " "
+	proj := m.projects[i]
+	allFinished := utils.All(proj.Scripts, func(script *types.Command) bool {
+		return script.Status == "failed" || script.Status == "finished" || script.Status == "skipped"
+	})
+
+	hasError := utils.Some(proj.Scripts, func(script *types.Command) bool {
+		return script.Status == "failed" || script.Status == "skipped"
+	})
+	spin := proj.Spinner.View()
+
+	if hasError {
+		spin = cross
+	} else if allFinished {
+		spin = checkMark
+	}
+
+	name := projectStyle(proj.Name)
+	if allFinished && !hasError {
+		name = projectDone(proj.Name)
+	}
+
+	if selected {
+		name = projectSelected(proj.Name)
+	}
+
+	s += fmt.Sprintf("%s%s%s", spin, gap, name)
+	if proj.Git != "" {
+		s += gap + lipgloss.NewStyle().Foreground(subtle).Render(proj.Git)
+	}
+	if proj.Containers != "" {
+		s += gap + lipgloss.NewStyle().Foreground(subtle).Render(proj.Containers)
+	}
+	if proj.URL != "" {
+		s += gap + lipgloss.NewStyle().Foreground(subtle).Render(proj.URL)
+	} else if proj.Port > 0 {
+		s += gap + lipgloss.NewStyle().Foreground(subtle).Render(fmt.Sprintf("PORT=%d", proj.Port))
+	}
+	s += "\n"
+
+	// an expanded project shows its output like debug mode, until done
+	expanded := m.expanded[i] && !m.done
+	if ((!allFinished || hasError) && (m.showScripts || m.done)) || m.showStdout || expanded {
+		for j, script := range proj.Scripts {
+			if m.done || m.showScripts {
+				if j > 0 && !m.showStdout {
+					s += divider
+				}
+				s += fmt.Sprintf("   %s", script.Renderer.Render(script, types.RenderOptions{ShowStatus: true, Width: m.width - 3}))
+				if script.Truncated.Load() {
+					s += truncatedNotice
+				}
+			}
+
+			// Show live output if debug mode is on or the project is expanded
+			if m.showStdout || expanded {
+				key := outputKey(i, j)
+				stdOut := ""
+				if output, exists := m.liveOutput[key]; exists && len(output) > 0 {
+					data := output
+					if maxLines > 0 && len(data) > maxLines {
+						data = output[len(data)-maxLines:]
+					}
+
+					for _, line := range data {
+						stdOut += fmt.Sprintf("     %s\n", renderOutputLine(line))
+					}
+				}
+
+				if len(stdOut) > 0 {
+					s += "\n"
+					s += stdOut
+				}
+			}
+		}
+		s += "\n"
+		if m.done && !m.showStdout {
+			s += renderErrors(proj)
+		}
+	}
+
+	return s
 }
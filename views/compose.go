@@ -0,0 +1,73 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"jrmd.dev/qk/utils"
+)
+
+// containerInterval is how often the containers of compose projects are
+// inspected.
+const containerInterval = 2 * time.Second
+
+type containersTickMessage struct{}
+
+type containersMessage struct {
+	index int
+	state string
+}
+
+// TrackContainers shows the state of each compose project's containers
+// next to it, updated while the commands run and once they are done.
+func (m *model) TrackContainers() *model {
+	m.containers = true
+	return m
+}
+
+func containersTick() tea.Cmd {
+	return tea.Tick(containerInterval, func(time.Time) tea.Msg { return containersTickMessage{} })
+}
+
+// readContainers inspects the containers of the compose projects with
+// commands without holding up the runner.
+func (m *model) readContainers() tea.Cmd {
+	cmds := []tea.Cmd{}
+	for i, proj := range m.projects {
+		if len(proj.Scripts) == 0 || !utils.HasComposeFile(proj) {
+			continue
+		}
+
+		index, dir := i, proj.Dir
+		cmds = append(cmds, func() tea.Msg {
+			state, ok := utils.ProjectContainers(dir)
+			if !ok {
+				return nil
+			}
+
+			return containersMessage{index, state.String()}
+		})
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// refreshContainers reads the final state of the containers once the run
+// is done, such as after compose up -d returned.
+func (m *model) refreshContainers() {
+	if !m.containers {
+		return
+	}
+
+	for i, proj := range m.projects {
+		if len(proj.Scripts) == 0 || !utils.HasComposeFile(proj) {
+			continue
+		}
+		if state, ok := utils.ProjectContainers(proj.Dir); ok {
+			m.projects[i].Containers = state.String()
+		}
+	}
+}
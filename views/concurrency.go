@@ -0,0 +1,40 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"sync"
+
+	"jrmd.dev/qk/utils"
+)
+
+// slots is a counting semaphore, each command holds one while it runs.
+type slots chan struct{}
+
+func (s slots) Lock() {
+	s <- struct{}{}
+}
+
+func (s slots) Unlock() {
+	<-s
+}
+
+// newSlots lets n commands run at once, nil lets any number.
+func newSlots(n int) sync.Locker {
+	if n <= 0 {
+		return nil
+	}
+
+	return make(slots, n)
+}
+
+// concurrency resolves how many commands may run at once from the flag and
+// the config, in that order. Zero or negative values lift the cap.
+func concurrency(conf utils.Config, flag int) int {
+	if flag != 0 {
+		return flag
+	}
+
+	return conf.Concurrency
+}
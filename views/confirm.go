@@ -0,0 +1,41 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+)
+
+// ConfirmDangerous lists the directories command is about to run in and
+// asks before going on, exiting when the user declines. Without a terminal
+// to ask on it refuses to run.
+func (m *model) ConfirmDangerous(command string, pattern string) *model {
+	errStyle := lipgloss.NewStyle().Foreground(errColor)
+	if headless(m.output) || !term.IsTerminal(os.Stdin.Fd()) {
+		fmt.Println(errStyle.Render(fmt.Sprintf("Error: %q matches the dangerous pattern %s, pass --yes to run it", command, pattern)))
+		os.Exit(1)
+	}
+
+	fmt.Println(errStyle.Render(fmt.Sprintf("%q matches the dangerous pattern %s and will run in:", command, pattern)))
+	for i, proj := range m.projects {
+		fmt.Printf("  %s %s\n", renderProjectName(proj.Name, i), lipgloss.NewStyle().Foreground(subtle).Render(proj.Dir))
+	}
+	fmt.Printf("Run it in %d projects? [y/N] ", len(m.projects))
+
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return m
+	}
+
+	fmt.Println("Aborted")
+	os.Exit(1)
+	return m
+}
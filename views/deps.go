@@ -0,0 +1,186 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+// OrderByDependencies makes each project wait for the projects it depends on
+// to finish before its commands start. Dependencies are inferred from
+// sibling packages in package.json and composer.json, plus the Dependencies
+// config. Downstream projects of a failed project are skipped.
+func (m *model) OrderByDependencies() *model {
+	files := []utils.File{}
+	for _, proj := range m.projects {
+		files = append(files, utils.File{Name: proj.Name, Dir: proj.Dir, FS: proj.FS, Workspace: proj.Workspace})
+	}
+
+	m.deps = map[int][]int{}
+	for i, file := range files {
+		for _, name := range utils.ProjectDependencies(m.conf, file, files) {
+			if j := slices.IndexFunc(files, func(f utils.File) bool { return f.Name == name }); j != -1 && j != i {
+				m.deps[i] = append(m.deps[i], j)
+			}
+		}
+	}
+
+	if cycle := m.dependencyCycle(); len(cycle) > 0 {
+		fmt.Println(lipgloss.NewStyle().Foreground(errColor).Render(fmt.Sprintf("Error: dependency cycle between %s", strings.Join(cycle, " -> "))))
+		os.Exit(1)
+	}
+
+	return m
+}
+
+// dependencyCycle returns the project names forming a cycle, if any.
+func (m *model) dependencyCycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(m.projects))
+	stack := []int{}
+
+	var visit func(i int) []string
+	visit = func(i int) []string {
+		state[i] = visiting
+		stack = append(stack, i)
+		for _, j := range m.deps[i] {
+			if state[j] == visiting {
+				names := []string{}
+				for _, k := range stack[slices.Index(stack, j):] {
+					names = append(names, m.projects[k].Name)
+				}
+				return append(names, m.projects[j].Name)
+			}
+			if state[j] == unvisited {
+				if cycle := visit(j); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[i] = visited
+		return nil
+	}
+
+	for i := range m.projects {
+		if state[i] == unvisited {
+			if cycle := visit(i); cycle != nil {
+				return cycle
+			}
+		}
+	}
+
+	return nil
+}
+
+// upstreamState reports whether every dependency of a project, and every
+// install it waits for, has finished, and whether any of them failed.
+func (m *model) upstreamState(index int) (ready bool, failed bool) {
+	upstream := slices.Clone(m.installs[index])
+	for _, j := range m.deps[index] {
+		upstream = append(upstream, m.projects[j].Scripts...)
+	}
+
+	ready = true
+	for _, script := range upstream {
+		switch script.Status {
+		case "finished":
+		case "failed", "skipped", "exited":
+			failed = true
+		default:
+			ready = false
+		}
+	}
+
+	return ready, failed
+}
+
+// stageState reports whether the project's commands of earlier stages than
+// script have all finished, and whether any of them failed.
+func (m *model) stageState(index int, script *types.Command) (ready bool, failed bool) {
+	ready = true
+	for _, earlier := range m.projects[index].Scripts {
+		if earlier.Stage >= script.Stage {
+			continue
+		}
+
+		switch earlier.Status {
+		case "finished":
+		case "failed", "skipped", "exited":
+			failed = true
+		default:
+			ready = false
+		}
+	}
+
+	return ready, failed
+}
+
+// startReady dispatches the waiting projects whose dependencies have all
+// finished, and skips the ones downstream of a failure.
+func (m *model) startReady() []tea.Cmd {
+	cmds := []tea.Cmd{}
+	if m.terminating {
+		return cmds
+	}
+
+	// skipping a project can unblock its own dependents, so repeat until
+	// nothing changes
+	for changed := true; changed; {
+		changed = false
+		for i, proj := range m.projects {
+			if !utils.Some(proj.Scripts, isWaiting) {
+				continue
+			}
+
+			upstreamReady, upstreamFailed := m.upstreamState(i)
+			for j, script := range proj.Scripts {
+				if !isWaiting(script) {
+					continue
+				}
+
+				stageReady, stageFailed := m.stageState(i, script)
+				failed := upstreamFailed || stageFailed
+				if !failed && !(upstreamReady && stageReady) {
+					continue
+				}
+
+				changed = true
+				if failed {
+					if upstreamFailed {
+						slog.Info("skipping command, a dependency failed", "project", proj.Name, "command", script.Script)
+					} else {
+						slog.Info("skipping command, an earlier command failed", "project", proj.Name, "command", script.Script)
+					}
+					script.Status = "skipped"
+					m.events.commandFinished(proj, script)
+					continue
+				}
+
+				script.Status = "running"
+				m.cmdWg.Add(1)
+				cmds = append(cmds, runCommand(script.Ctx, &m.cmdWg, m.program, m.events, i, proj, j, script))
+			}
+		}
+	}
+
+	return cmds
+}
+
+func isWaiting(script *types.Command) bool {
+	return script.Status == "waiting"
+}
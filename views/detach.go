@@ -0,0 +1,110 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+// Detach starts every project's commands in the background instead of
+// showing the TUI, ordered by its launchScript. Each project leads its own
+// session so it outlives qk, its output goes to a log file under ~/.qk/run.
+// Projects already running detached are left alone, projects waiting for
+// them are skipped.
+func (m *model) Detach() {
+	errStyle := lipgloss.NewStyle().Foreground(errColor)
+	markers, err := os.MkdirTemp("", "qk-detach-")
+	if err != nil {
+		fmt.Println(errStyle.Render(fmt.Sprintf("Error: %s", err)))
+		os.Exit(1)
+	}
+
+	running := map[string]bool{}
+	if processes, err := utils.Processes(); err == nil {
+		for _, p := range processes {
+			running[p.Dir] = running[p.Dir] || (p.Detached() && p.Running())
+		}
+	}
+
+	started := 0
+	for i, proj := range m.projects {
+		if len(proj.Scripts) == 0 {
+			continue
+		}
+
+		if running[proj.Dir] {
+			fmt.Printf("%s is already running, qk stop %s first\n", renderProjectName(proj.Name, i), proj.Name)
+			for j := range proj.Scripts {
+				_ = os.WriteFile(path.Join(markers, fmt.Sprintf("%d-%d.failed", i, j)), nil, 0o644)
+			}
+			continue
+		}
+
+		p, err := startDetached(proj.Name, proj.Dir, m.root, m.launchScript(i, markers), projectCommands(proj))
+		if err != nil {
+			fmt.Println(errStyle.Render(fmt.Sprintf("Error: could not start %s: %s", proj.Name, err)))
+			continue
+		}
+
+		started++
+		fmt.Printf("%s started, pid %d\n", renderProjectName(proj.Name, i), p.Pid)
+	}
+
+	if started > 0 {
+		fmt.Println(lipgloss.NewStyle().Foreground(subtle).Render("qk ps lists them, qk logs <project> shows their output and qk stop stops them"))
+	}
+}
+
+// projectCommands lists the project's commands for qk ps.
+func projectCommands(proj types.Project) string {
+	commands := []string{}
+	for _, script := range proj.Scripts {
+		commands = append(commands, commandLine(script))
+	}
+
+	return strings.Join(commands, ", ")
+}
+
+// startDetached runs script with sh in a session of its own, recording it
+// as running command.
+func startDetached(project string, dir string, root string, script string, command string) (utils.Process, error) {
+	logFile, err := utils.DetachedLogFile(project, dir)
+	if err != nil {
+		return utils.Process{}, err
+	}
+	if err := os.MkdirAll(path.Dir(logFile), 0o755); err != nil {
+		return utils.Process{}, err
+	}
+
+	log, err := os.OpenFile(logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return utils.Process{}, err
+	}
+	defer log.Close()
+
+	c := exec.Command("sh", "-c", script)
+	c.Dir = dir
+	c.Stdout = log
+	c.Stderr = log
+	c.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := c.Start(); err != nil {
+		return utils.Process{}, err
+	}
+	pid := c.Process.Pid
+	// nobody waits for it, once qk exits init adopts it
+	_ = c.Process.Release()
+
+	p := utils.Process{Project: project, Dir: dir, Root: root, Command: command, Pid: pid, Started: time.Now(), LogFile: logFile}
+	return p, utils.SaveProcess(p)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+// errorLimit caps the errors extracted from the output of a failed command.
+const errorLimit = 3
+
+// commandErrors extracts the errors a failed command printed.
+func commandErrors(script *types.Command) []string {
+	if script.Status != "failed" {
+		return nil
+	}
+
+	return utils.ExtractErrors(script.Output.String(), errorLimit)
+}
+
+// renderErrors renders the errors of a project's failed commands, shown
+// under it once the run is done so a failure can be read without
+// switching to debug mode.
+func renderErrors(proj types.Project) (s string) {
+	first := lipgloss.NewStyle().Foreground(errColor)
+	context := lipgloss.NewStyle().Foreground(subtle)
+
+	for _, script := range proj.Scripts {
+		for _, snippet := range commandErrors(script) {
+			for k, line := range strings.Split(snippet, "\n") {
+				style := context
+				if k == 0 {
+					style = first
+				}
+				s += "     " + style.Render(line) + "\n"
+			}
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,148 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+// Event types written by --events, one JSON object per line.
+const (
+	EventCommandStarted  = "command_started"
+	EventOutputLine      = "output_line"
+	EventCommandFinished = "command_finished"
+	EventRunFinished     = "run_finished"
+)
+
+type event struct {
+	Type    string    `json:"type"`
+	Time    time.Time `json:"time"`
+	Project string    `json:"project,omitempty"`
+	Dir     string    `json:"dir,omitempty"`
+	Command string    `json:"command,omitempty"`
+	// Stream and Line are set for output lines.
+	Stream string `json:"stream,omitempty"`
+	Line   string `json:"line,omitempty"`
+	// Status, ExitCode and Duration are set once a command or the run
+	// finished.
+	Status   string   `json:"status,omitempty"`
+	ExitCode *int     `json:"exitCode,omitempty"`
+	Duration *float64 `json:"duration,omitempty"`
+	// Timing breaks the run down into phases once it finished.
+	Timing *timingReport `json:"timing,omitempty"`
+}
+
+// eventStream writes lifecycle events as NDJSON. A nil stream writes
+// nothing, so callers don't have to check whether --events was given.
+type eventStream struct {
+	sync.Mutex
+	w io.WriteCloser
+}
+
+// openEvents opens the --events target: fd:N for an inherited file
+// descriptor, - for stdout, or a file which is truncated.
+func openEvents(target string) (*eventStream, error) {
+	switch {
+	case target == "":
+		return nil, nil
+	case target == "-":
+		return &eventStream{w: os.Stdout}, nil
+	case strings.HasPrefix(target, "fd:"):
+		fd, err := strconv.Atoi(strings.TrimPrefix(target, "fd:"))
+		if err != nil || fd < 0 {
+			return nil, fmt.Errorf("invalid file descriptor %q", target)
+		}
+		return &eventStream{w: os.NewFile(uintptr(fd), target)}, nil
+	}
+
+	file, err := os.Create(target)
+	if err != nil {
+		return nil, err
+	}
+
+	return &eventStream{w: file}, nil
+}
+
+func (s *eventStream) emit(e event) {
+	if s == nil {
+		return
+	}
+
+	e.Time = time.Now()
+	data := &bytes.Buffer{}
+	encoder := json.NewEncoder(data)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(e); err != nil {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	_, _ = s.w.Write(data.Bytes())
+}
+
+func (s *eventStream) commandStarted(project types.Project, command *types.Command) {
+	s.emit(event{Type: EventCommandStarted, Project: project.Name, Dir: project.Dir, Command: commandLine(command)})
+}
+
+func (s *eventStream) outputLine(project types.Project, command *types.Command, line string, stderr bool) {
+	stream := "stdout"
+	if stderr {
+		stream = "stderr"
+	}
+
+	s.emit(event{Type: EventOutputLine, Project: project.Name, Dir: project.Dir, Command: commandLine(command), Stream: stream, Line: line})
+}
+
+func (s *eventStream) commandFinished(project types.Project, command *types.Command) {
+	_, duration := phases(command)
+	seconds := duration.Seconds()
+	exitCode := command.ExitCode
+
+	s.emit(event{Type: EventCommandFinished, Project: project.Name, Dir: project.Dir, Command: commandLine(command), Status: command.Status, ExitCode: &exitCode, Duration: &seconds})
+}
+
+// runFinished writes the last event, with the run's timing, and closes the
+// stream.
+func (s *eventStream) runFinished(success bool, timing runTiming) {
+	if s == nil {
+		return
+	}
+
+	status := "finished"
+	if !success {
+		status = "failed"
+	}
+	seconds := timing.total.Seconds()
+	s.emit(event{Type: EventRunFinished, Status: status, Duration: &seconds, Timing: newTimingReport(timing)})
+
+	if s.w != os.Stdout {
+		_ = s.w.Close()
+	}
+}
+
+// anyFailed reports whether a command failed or was skipped because of a
+// failure.
+func (m *model) anyFailed() bool {
+	return utils.Some(m.projects, func(project types.Project) bool {
+		return utils.Some(project.Scripts, func(script *types.Command) bool {
+			return script.Status == "failed" || script.Status == "skipped"
+		})
+	})
+}
+
+func commandLine(command *types.Command) string {
+	return strings.Join(append([]string{command.Script}, command.Args...), " ")
+}
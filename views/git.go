@@ -0,0 +1,29 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"jrmd.dev/qk/utils"
+)
+
+type gitStateMessage struct {
+	index int
+	state string
+}
+
+// readGitState looks up a project's branch and whether it is dirty without
+// holding up the runner, outside of a repository it reports nothing.
+func (m *model) readGitState(index int) tea.Cmd {
+	dir := m.projects[index].Dir
+
+	return func() tea.Msg {
+		state, ok := utils.ProjectGitState(dir)
+		if !ok {
+			return nil
+		}
+
+		return gitStateMessage{index, state.String()}
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+// headless reports whether the run is printed once it's done rather than
+// drawn by the TUI, for output meant for tools instead of people.
+func headless(output string) bool {
+	return output == OutputJSON || output == OutputGitHub
+}
+
+// errorLocations find the file, line and column of an extracted error, for
+// the tools which print them.
+var errorLocations = []*regexp.Regexp{
+	// typescript: src/app.ts(3,5): error TS2322
+	regexp.MustCompile(`^(?P<file>[^\s(]+)\((?P<line>\d+),(?P<col>\d+)\): error`),
+	// php: in /app/x.php:3 or in /app/x.php on line 3
+	regexp.MustCompile(`in (?P<file>\S+\.php)(?::| on line )(?P<line>\d+)`),
+	// esbuild, vite, webpack and node: src/app.ts:3:5
+	regexp.MustCompile(`(?:^|\s|\()(?P<file>[\w./@-]+\.\w+):(?P<line>\d+)(?::(?P<col>\d+))?`),
+}
+
+type errorLocation struct {
+	file      string
+	line, col int
+}
+
+// locateError looks for where an error points to in its lines, resolving
+// relative files against the project directory.
+func locateError(snippet string, dir string) (errorLocation, bool) {
+	for _, line := range strings.Split(snippet, "\n") {
+		for _, pattern := range errorLocations {
+			match := pattern.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+
+			loc := errorLocation{file: match[pattern.SubexpIndex("file")]}
+			loc.line, _ = strconv.Atoi(match[pattern.SubexpIndex("line")])
+			if i := pattern.SubexpIndex("col"); i != -1 {
+				loc.col, _ = strconv.Atoi(match[i])
+			}
+			if !filepath.IsAbs(loc.file) {
+				loc.file = filepath.Join(dir, loc.file)
+			}
+
+			return loc, true
+		}
+	}
+
+	return errorLocation{}, false
+}
+
+// escapeData escapes the message of a workflow command.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a property value of a workflow command.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// workspaceFile makes file relative to the checkout, which GitHub needs to
+// place an annotation on the diff. Files outside it are left absolute.
+func (m *model) workspaceFile(file string) string {
+	workspace := os.Getenv("GITHUB_WORKSPACE")
+	if workspace == "" {
+		workspace = m.root
+	}
+
+	rel, err := filepath.Rel(workspace, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return file
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// annotations are the error annotations of a failed command, one per
+// extracted error, or a single one with its exit code when none were found.
+func (m *model) annotations(proj types.Project, script *types.Command) []string {
+	title := "title=" + escapeProperty(fmt.Sprintf("%s: %s failed", proj.Name, commandLine(script)))
+
+	errors := commandErrors(script)
+	if len(errors) == 0 {
+		return []string{fmt.Sprintf("::error %s::%s", title, escapeData(fmt.Sprintf("exited with code %d", script.ExitCode)))}
+	}
+
+	lines := []string{}
+	for _, snippet := range errors {
+		properties := title
+		if loc, ok := locateError(snippet, proj.Dir); ok {
+			properties = fmt.Sprintf("file=%s,line=%d,%s", escapeProperty(m.workspaceFile(loc.file)), loc.line, title)
+			if loc.col > 0 {
+				properties = fmt.Sprintf("file=%s,line=%d,col=%d,%s", escapeProperty(m.workspaceFile(loc.file)), loc.line, loc.col, title)
+			}
+		}
+		lines = append(lines, fmt.Sprintf("::error %s::%s", properties, escapeData(snippet)))
+	}
+
+	return lines
+}
+
+// githubOutput streams the run as GitHub Actions workflow commands while it
+// runs, as it would otherwise only show up in the job's log once it's done.
+type githubOutput struct {
+	// token stops output containing workflow commands from being run as ones
+	token       string
+	printed     map[int]bool
+	annotations []string
+}
+
+// settled reports whether none of the project's commands will run again.
+func settled(proj types.Project) bool {
+	return !utils.Some(proj.Scripts, func(script *types.Command) bool {
+		return script.Status == "waiting" || script.Status == "running" || script.Status == "terminating" || script.Status == "restarting" || script.Status == "remediating"
+	})
+}
+
+// streamGitHub prints a collapsed group holding the output of every project
+// whose commands settled since it last ran.
+func (m *model) streamGitHub() {
+	if m.output == OutputGitHub {
+		fmt.Print(m.githubGroups(false))
+	}
+}
+
+// githubGroups renders the projects not printed yet, only the settled ones
+// unless all is set, and keeps the error annotations of their failed
+// commands for the end of the run.
+func (m *model) githubGroups(all bool) string {
+	if m.github == nil {
+		m.github = &githubOutput{token: "qk-" + randomID(8), printed: map[int]bool{}}
+	}
+
+	var s strings.Builder
+	for i, proj := range m.projects {
+		if len(proj.Scripts) == 0 || m.github.printed[i] || (!all && !settled(proj)) {
+			continue
+		}
+
+		m.github.printed[i] = true
+		s.WriteString(m.githubGroup(proj))
+		for _, script := range proj.Scripts {
+			if script.Status == "failed" {
+				m.github.annotations = append(m.github.annotations, m.annotations(proj, script)...)
+			}
+		}
+	}
+
+	return s.String()
+}
+
+// githubGroup renders a project's output as a collapsed group.
+func (m *model) githubGroup(proj types.Project) string {
+	var s strings.Builder
+
+	status := "finished"
+	for _, script := range proj.Scripts {
+		if script.Status != "finished" {
+			status = script.Status
+		}
+		if script.Status == "failed" {
+			break
+		}
+	}
+
+	fmt.Fprintf(&s, "::group::%s (%s)\n", proj.Name, status)
+	fmt.Fprintf(&s, "::stop-commands::%s\n", m.github.token)
+	for _, script := range proj.Scripts {
+		_, duration := phases(script)
+		fmt.Fprintf(&s, "$ %s (%s after %s)\n", commandLine(script), script.Status, short(duration))
+
+		if output := strings.TrimRight(ansi.Strip(script.Output.String()), "\n"); output != "" {
+			s.WriteString(output + "\n")
+		}
+	}
+	fmt.Fprintf(&s, "::%s::\n", m.github.token)
+	s.WriteString("::endgroup::\n")
+
+	return s.String()
+}
+
+// GitHub renders what streamGitHub didn't print yet, such as projects
+// stopped on quit, followed by an error annotation for every failed command
+// so the failures show up on the pull request.
+func (m *model) GitHub() string {
+	var s strings.Builder
+	s.WriteString(m.githubGroups(true))
+	for _, annotation := range m.github.annotations {
+		s.WriteString(annotation + "\n")
+	}
+
+	return s.String()
+}
@@ -0,0 +1,59 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import "testing"
+
+func TestLocateError(t *testing.T) {
+	tests := []struct {
+		name    string
+		snippet string
+		want    errorLocation
+		ok      bool
+	}{
+		{
+			name:    "typescript",
+			snippet: "src/app.ts(3,5): error TS2322: Type 'string' is not assignable to type 'number'.",
+			want:    errorLocation{file: "/ws/app/src/app.ts", line: 3, col: 5},
+			ok:      true,
+		},
+		{
+			name:    "php",
+			snippet: "PHP Fatal error:  Uncaught Error: Call to undefined function foo() in /ws/app/src/x.php:12",
+			want:    errorLocation{file: "/ws/app/src/x.php", line: 12},
+			ok:      true,
+		},
+		{
+			name:    "php on line",
+			snippet: "PHP Parse error:  syntax error, unexpected '}' in src/x.php on line 7",
+			want:    errorLocation{file: "/ws/app/src/x.php", line: 7},
+			ok:      true,
+		},
+		{
+			name:    "esbuild",
+			snippet: "✘ [ERROR] Expected \";\" but found \"}\"\n\n    src/index.ts:10:2:",
+			want:    errorLocation{file: "/ws/app/src/index.ts", line: 10, col: 2},
+			ok:      true,
+		},
+		{
+			name:    "webpack without column",
+			snippet: "ERROR in ./src/main.js:4",
+			want:    errorLocation{file: "/ws/app/src/main.js", line: 4},
+			ok:      true,
+		},
+		{
+			name:    "no location",
+			snippet: "npm error Missing script: \"build\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := locateError(tt.snippet, "/ws/app")
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("locateError = %+v, %v, want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
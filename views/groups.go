@@ -0,0 +1,120 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// row is a line of the project list, either a group header (project is -1)
+// or a project.
+type row struct {
+	group   string
+	project int
+}
+
+// rows lays out the projects in display order, listing each group under a
+// header where it first appears. Members of folded groups are hidden until
+// the run is done.
+func (m *model) rows() []row {
+	rows := []row{}
+	seen := map[string]bool{}
+
+	for _, i := range m.order {
+		group := m.projects[i].Group
+		if group == "" {
+			rows = append(rows, row{project: i})
+			continue
+		}
+
+		if seen[group] {
+			continue
+		}
+		seen[group] = true
+
+		rows = append(rows, row{group: group, project: -1})
+		if m.folded[group] && !m.done {
+			continue
+		}
+
+		for _, j := range m.order {
+			if m.projects[j].Group == group {
+				rows = append(rows, row{group: group, project: j})
+			}
+		}
+	}
+
+	return rows
+}
+
+// selected returns the index into projects of the project under the cursor,
+// or -1 when the cursor is on a group header.
+func (m *model) selected() int {
+	rows := m.rows()
+	if m.cursor >= len(rows) {
+		return -1
+	}
+
+	return rows[m.cursor].project
+}
+
+// toggleFold folds or unfolds the group under the cursor, leaving the cursor
+// on its header.
+func (m *model) toggleFold() {
+	rows := m.rows()
+	if m.cursor >= len(rows) || rows[m.cursor].group == "" {
+		return
+	}
+
+	group := rows[m.cursor].group
+	m.folded[group] = !m.folded[group]
+
+	for pos, r := range m.rows() {
+		if r.group == group && r.project == -1 {
+			m.cursor = pos
+		}
+	}
+}
+
+// renderGroup renders a group header with a rollup of its members' status.
+func (m *model) renderGroup(group string, selected bool) string {
+	members, running, failed := 0, 0, 0
+	for _, proj := range m.projects {
+		if proj.Group != group {
+			continue
+		}
+
+		members++
+		for _, script := range proj.Scripts {
+			switch script.Status {
+			case "failed", "skipped":
+				failed++
+			case "running", "waiting", "terminating", "restarting", "remediating":
+				running++
+			}
+		}
+	}
+
+	marker := "▾"
+	if m.folded[group] && !m.done {
+		marker = "▸"
+	}
+
+	name := title.Render(group)
+	if selected {
+		name = projectSelected(group)
+	}
+
+	rollup := lipgloss.NewStyle().Foreground(special).Render("all green")
+	switch {
+	case failed > 0:
+		rollup = lipgloss.NewStyle().Foreground(errColor).Render(fmt.Sprintf("%d failed", failed))
+	case running > 0:
+		rollup = lipgloss.NewStyle().Foreground(accent).Render(fmt.Sprintf("%d running", running))
+	}
+
+	return fmt.Sprintf("%s %s %d projects%s%s\n", marker, name, members, divider, rollup)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"log/slog"
+	"time"
+
+	"jrmd.dev/qk/utils"
+)
+
+// recordHistory adds the run to the history qk history lists and returns
+// the recorded entry.
+func (m *model) recordHistory() utils.HistoryEntry {
+	entry := utils.HistoryEntry{
+		Time:     m.start,
+		Command:  runName(),
+		Dir:      m.root,
+		Status:   "finished",
+		Duration: time.Since(m.start).Seconds(),
+		LogDir:   m.logDir,
+		Projects: []utils.HistoryProject{},
+	}
+
+	for _, proj := range m.projects {
+		if len(proj.Scripts) == 0 {
+			continue
+		}
+
+		project := utils.HistoryProject{Project: proj.Name, Dir: proj.Dir, Commands: []utils.HistoryCommand{}}
+		for _, script := range proj.Scripts {
+			_, duration := phases(script)
+			project.Commands = append(project.Commands, utils.HistoryCommand{
+				Command:  commandLine(script),
+				Status:   script.Status,
+				Duration: duration.Seconds(),
+				ExitCode: script.ExitCode,
+				LogFile:  script.LogFile,
+				Cached:   script.Cached,
+			})
+		}
+		entry.Projects = append(entry.Projects, project)
+
+		if status := project.Status(); status == "failed" {
+			entry.Status = "failed"
+		} else if status != "finished" && entry.Status == "finished" {
+			// quit before everything finished
+			entry.Status = "stopped"
+		}
+	}
+
+	entry, err := utils.RecordHistory(entry)
+	if err != nil {
+		slog.Warn("could not record run history", "err", err)
+	}
+	if err := utils.PruneLogs(m.conf); err != nil {
+		slog.Warn("could not remove old run logs", "err", err)
+	}
+
+	return entry
+}
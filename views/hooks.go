@@ -0,0 +1,66 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+const (
+	// stageInstall installs out of date dependencies before anything else.
+	stageInstall = -2
+	stagePre     = -1
+	stagePost    = 1
+)
+
+// AddHooks adds the configured pre and post hooks of a command, such as
+// preBuild and postBuild for "build", to every project which has commands.
+// Pre hooks run before the project's commands and post hooks after they
+// all finished, a failure skips what follows.
+func (m *model) AddHooks(name string, renderer func(hook string) types.CommandRenderer) *model {
+	title := strings.ToUpper(name[:1]) + name[1:]
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "sh"
+	}
+
+	for _, hook := range []struct {
+		name  string
+		stage int
+	}{{"pre" + title, stagePre}, {"post" + title, stagePost}} {
+		snippet, ok := m.conf.Hooks[hook.name]
+		if !ok || snippet == "" {
+			continue
+		}
+
+		for i, proj := range m.projects {
+			if len(proj.Scripts) == 0 {
+				continue
+			}
+
+			expanded, err := utils.ExpandTemplate(snippet, utils.ProjectVars(m.root, proj))
+			if err != nil {
+				fmt.Println(lipgloss.NewStyle().Foreground(errColor).Render(fmt.Sprintf("Error: %s hook: %s", hook.name, err)))
+				os.Exit(1)
+			}
+
+			cmd := m.newCommand(i, renderer(hook.name), shell, []string{"-c", expanded})
+			cmd.Stage = hook.stage
+			if hook.stage == stagePre {
+				m.projects[i].Scripts = slices.Insert(m.projects[i].Scripts, 0, cmd)
+			} else {
+				m.projects[i].Scripts = append(m.projects[i].Scripts, cmd)
+			}
+		}
+	}
+
+	return m
+}
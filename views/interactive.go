@@ -0,0 +1,105 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"slices"
+	"time"
+
+	"jrmd.dev/qk/types"
+)
+
+// runAttached runs the projects one at a time with their commands attached
+// to the terminal, so commands prompting for input can be answered. It
+// replaces the TUI for --interactive runs, dependencies are still honoured.
+func (m *model) runAttached() {
+	for _, proj := range m.projects {
+		for _, script := range proj.Scripts {
+			if script.Cached {
+				continue
+			}
+			script.Status = "waiting"
+			script.Queued = time.Now()
+		}
+	}
+
+	pending := slices.Clone(m.order)
+	for len(pending) > 0 {
+		next := -1
+		for pos, i := range pending {
+			ready, failed := m.upstreamState(i)
+			if failed {
+				slog.Info("skipping project, a dependency failed", "project", m.projects[i].Name)
+				for _, script := range m.projects[i].Scripts {
+					script.Status = "skipped"
+					m.events.commandFinished(m.projects[i], script)
+				}
+			}
+			if ready || failed {
+				next = pos
+				break
+			}
+		}
+
+		// only a dependency cycle leaves nothing to run
+		if next == -1 {
+			break
+		}
+
+		i := pending[next]
+		pending = slices.Delete(pending, next, next+1)
+		for _, script := range m.projects[i].Scripts {
+			if _, failed := m.stageState(i, script); failed && script.Status == "waiting" {
+				slog.Info("skipping command, an earlier command failed", "project", m.projects[i].Name, "command", script.Script)
+				script.Status = "skipped"
+				m.events.commandFinished(m.projects[i], script)
+			}
+			if script.Status == "waiting" {
+				m.runAttachedCommand(i, script)
+			}
+		}
+	}
+
+	m.checkLockfiles()
+	m.done = true
+	m.finish = time.Now()
+}
+
+func (m *model) runAttachedCommand(index int, script *types.Command) {
+	proj := m.projects[index]
+	fmt.Printf("\n%s %s\n", renderProjectName(proj.Name, index), script.Renderer.Render(script, types.RenderOptions{}))
+
+	// output goes straight to the terminal, there is no log to point at
+	script.LogFile = ""
+
+	c := exec.Command(script.Script, script.Args...)
+	c.Dir = proj.Dir
+	c.Env = append(os.Environ(), script.Env...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+
+	tracked := trackProcess(proj, script, true)
+	defer tracked.forget()
+
+	script.Start = time.Now()
+	err := c.Start()
+	if err == nil {
+		m.events.commandStarted(proj, script)
+		script.OnStart(c.Process.Pid)
+		err = c.Wait()
+	}
+	script.Finish = time.Now()
+	script.ExitCode = exitCode(err)
+
+	script.Status = "finished"
+	if err != nil {
+		script.Status = "failed"
+	}
+	m.events.commandFinished(proj, script)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"jrmd.dev/qk/version"
+)
+
+// maxJoinedLines bounds the interleaved output kept for the joined view.
+const maxJoinedLines = 1000
+
+var joinedSeparator string
+
+// recordJoined adds a line to the interleaved stream of every project's
+// output.
+func (m *model) recordJoined(index int, content string) {
+	m.joinedOutput = append(m.joinedOutput, outputLine{tag: m.projectTag(index), content: content})
+	if len(m.joinedOutput) > maxJoinedLines {
+		m.joinedOutput = m.joinedOutput[len(m.joinedOutput)-maxJoinedLines:]
+	}
+}
+
+// projectTag renders a project name padded to the longest one, in the
+// project's colour.
+func (m *model) projectTag(index int) string {
+	width := 0
+	for _, proj := range m.projects {
+		width = max(width, lipgloss.Width(proj.Name))
+	}
+
+	name := m.projects[index].Name
+	return renderProjectName(name+strings.Repeat(" ", width-lipgloss.Width(name)), index)
+}
+
+// joinedView renders the latest output of every project as one stream, each
+// line tagged with its project like docker compose logs.
+func (m *model) joinedView() (s string) {
+	s += fmt.Sprintf("%s  %s\n\n", title.Render("QK Command Runner"), subtitle.Render(version.Get().Version))
+	s += m.progressBar()
+
+	footer := m.footer()
+
+	lines := m.joinedOutput
+	if m.height > 0 {
+		room := max(m.height-lipgloss.Height(s)-lipgloss.Height(footer), 1)
+		if len(lines) > room {
+			lines = lines[len(lines)-room:]
+		}
+	}
+
+	for _, line := range lines {
+		s += fmt.Sprintf("%s %s %s\n", line.tag, joinedSeparator, withColors(line.content))
+	}
+
+	return s + footer
+}
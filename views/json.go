@@ -0,0 +1,114 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"jrmd.dev/qk/types"
+)
+
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+	// OutputGitHub prints GitHub Actions groups and error annotations.
+	OutputGitHub = "github"
+)
+
+type commandReport struct {
+	Command string `json:"command"`
+	Status  string `json:"status"`
+	// Queued is the time spent waiting for dependencies and locks,
+	// Duration the time spent running.
+	Queued   float64 `json:"queued"`
+	Duration float64 `json:"duration"`
+	ExitCode int     `json:"exitCode"`
+	// OutputBytes counts all output, Stdout and Stderr may be truncated.
+	OutputBytes int64  `json:"outputBytes"`
+	Stdout      string `json:"stdout"`
+	Stderr      string `json:"stderr"`
+	LogFile     string `json:"logFile,omitempty"`
+	// Remediation describes an automatic fix applied before rerunning.
+	Remediation string `json:"remediation,omitempty"`
+	// Truncated is set when output was dropped, the log file has it all.
+	Truncated bool `json:"truncated,omitempty"`
+	// Cached is set when the build cache skipped the command.
+	Cached bool `json:"cached,omitempty"`
+	// Errors are the errors extracted from the output of a failed command.
+	Errors []string `json:"errors,omitempty"`
+}
+
+type projectReport struct {
+	Project  string          `json:"project"`
+	Dir      string          `json:"dir"`
+	Commands []commandReport `json:"commands"`
+	// ChangedLockfiles is only reported by commands which track lockfiles.
+	ChangedLockfiles []string `json:"changedLockfiles,omitempty"`
+}
+
+// timingReport breaks the time of a run down into phases for the
+// run_finished event. Queued and execution are summed across commands.
+type timingReport struct {
+	Discovery float64 `json:"discovery"`
+	Queued    float64 `json:"queued"`
+	Execution float64 `json:"execution"`
+	Total     float64 `json:"total"`
+}
+
+func newTimingReport(timing runTiming) *timingReport {
+	return &timingReport{
+		Discovery: timing.discovery.Seconds(),
+		Queued:    timing.queued.Seconds(),
+		Execution: timing.execution.Seconds(),
+		Total:     timing.total.Seconds(),
+	}
+}
+
+func newCommandReport(c *types.Command) commandReport {
+	queued, duration := phases(c)
+
+	return commandReport{
+		Command:     strings.Join(append([]string{c.Script}, c.Args...), " "),
+		Status:      c.Status,
+		Queued:      queued.Seconds(),
+		Duration:    duration.Seconds(),
+		ExitCode:    c.ExitCode,
+		OutputBytes: c.OutputBytes.Load(),
+		Stdout:      c.Stdout.String(),
+		Stderr:      c.Stderr.String(),
+		LogFile:     c.LogFile,
+		Remediation: c.Remediation,
+		Truncated:   c.Truncated.Load(),
+		Cached:      c.Cached,
+		Errors:      commandErrors(c),
+	}
+}
+
+// JSON renders one JSON document per project, each on its own line, so the
+// result can be consumed as a stream by dashboards and CI tooling. The run's
+// timing is in the run_finished event of --events.
+func (m *model) JSON() string {
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+
+	for _, proj := range m.projects {
+		report := projectReport{
+			Project:          proj.Name,
+			Dir:              proj.Dir,
+			Commands:         []commandReport{},
+			ChangedLockfiles: proj.ChangedLockfiles,
+		}
+
+		for _, script := range proj.Scripts {
+			report.Commands = append(report.Commands, newCommandReport(script))
+		}
+
+		_ = enc.Encode(report)
+	}
+
+	return out.String()
+}
@@ -0,0 +1,99 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+// launchScript returns a sh script running a project's commands outside the
+// runner the way it would, for watch --tmux and --detach. Once the commands
+// of the project's dependencies and its workspace root install finished,
+// the commands of each stage start together after the earlier stages
+// finished, and a failure skips what follows.
+//
+// Every command leaves a marker in markers when it exits, the scripts of
+// the projects wait for each other's markers.
+func (m *model) launchScript(index int, markers string) string {
+	marker := map[*types.Command]string{}
+	for i, proj := range m.projects {
+		for j, script := range proj.Scripts {
+			marker[script] = utils.ShellQuote(path.Join(markers, fmt.Sprintf("%d-%d", i, j)))
+		}
+	}
+
+	proj := m.projects[index]
+	own := []string{}
+	stages := []int{}
+	for _, script := range proj.Scripts {
+		own = append(own, marker[script])
+		if !slices.Contains(stages, script.Stage) {
+			stages = append(stages, script.Stage)
+		}
+	}
+	slices.Sort(stages)
+
+	// skipping marks every command of the project failed, so projects
+	// waiting for them are skipped too
+	skip := func(reason string) string {
+		return fmt.Sprintf(`{ echo %s; for m in %s; do [ -e "$m.done" ] || touch "$m.failed"; done; exit 1; }`, utils.ShellQuote("qk: "+reason+", skipping"), strings.Join(own, " "))
+	}
+
+	lines := []string{
+		// background commands ignore SIGINT, stop them with the script
+		"trap 'trap - INT TERM; kill 0' INT TERM",
+		`await() { while [ ! -e "$1.done" ]; do [ -e "$1.failed" ] && return 1; sleep 1; done; }`,
+	}
+
+	upstream := slices.Clone(m.installs[index])
+	for _, j := range m.deps[index] {
+		upstream = append(upstream, m.projects[j].Scripts...)
+	}
+	for _, script := range upstream {
+		lines = append(lines, fmt.Sprintf("await %s || %s", marker[script], skip("a dependency failed")))
+	}
+
+	for k, stage := range stages {
+		started := []string{}
+		for _, script := range proj.Scripts {
+			if script.Stage != stage {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf(`{ %s && touch %s.done || touch %s.failed; } &`, shellCommand(script), marker[script], marker[script]))
+			started = append(started, marker[script])
+		}
+		lines = append(lines, "wait")
+
+		failed := skip("an earlier command failed")
+		if k == len(stages)-1 {
+			failed = "exit 1"
+		}
+		lines = append(lines, fmt.Sprintf(`for m in %s; do [ -e "$m.done" ] || %s; done`, strings.Join(started, " "), failed))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// shellCommand quotes a command and its environment for sh.
+func shellCommand(script *types.Command) string {
+	words := []string{}
+	if len(script.Env) > 0 {
+		words = append(words, "env")
+		for _, pair := range script.Env {
+			words = append(words, utils.ShellQuote(pair))
+		}
+	}
+	words = append(words, utils.ShellQuote(script.Script))
+	for _, arg := range script.Args {
+		words = append(words, utils.ShellQuote(arg))
+	}
+
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,85 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+
+	"jrmd.dev/qk/types"
+)
+
+// runLaunchScripts runs the launch scripts of every project together and
+// returns what each printed.
+func runLaunchScripts(t *testing.T, m *model) []string {
+	markers := t.TempDir()
+	outputs := make([]string, len(m.projects))
+
+	var wg sync.WaitGroup
+	for i := range m.projects {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out, _ := exec.Command("sh", "-c", m.launchScript(i, markers)).CombinedOutput()
+			outputs[i] = string(out)
+		}()
+	}
+	wg.Wait()
+
+	return outputs
+}
+
+func TestLaunchScript(t *testing.T) {
+	sh := func(stage int, script string) *types.Command {
+		return &types.Command{Script: "sh", Args: []string{"-c", script}, Stage: stage}
+	}
+
+	tests := []struct {
+		name     string
+		projects [][]*types.Command
+		deps     map[int][]int
+		want     []string
+	}{
+		{
+			name:     "stages run in order",
+			projects: [][]*types.Command{{sh(1, "echo post"), sh(-1, "echo pre"), sh(0, "echo main")}},
+			want:     []string{"pre\nmain\npost\n"},
+		},
+		{
+			name:     "a failed stage skips the next",
+			projects: [][]*types.Command{{sh(-1, "exit 3"), sh(0, "echo main")}},
+			want:     []string{"qk: an earlier command failed, skipping\n"},
+		},
+		{
+			name:     "dependencies finish first",
+			projects: [][]*types.Command{{sh(0, "echo app")}, {sh(0, "sleep 0.2; echo lib")}},
+			deps:     map[int][]int{0: {1}},
+			want:     []string{"app\n", "lib\n"},
+		},
+		{
+			name:     "a failed dependency skips the project",
+			projects: [][]*types.Command{{sh(0, "echo app")}, {sh(0, "exit 1")}},
+			deps:     map[int][]int{0: {1}},
+			want:     []string{"qk: a dependency failed, skipping\n", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &model{deps: tt.deps, installs: map[int][]*types.Command{}}
+			for i, scripts := range tt.projects {
+				m.projects = append(m.projects, types.Project{Name: fmt.Sprintf("p%d", i), Scripts: scripts})
+			}
+
+			for i, got := range runLaunchScripts(t, m) {
+				if strings.TrimSpace(got) != strings.TrimSpace(tt.want[i]) {
+					t.Errorf("project %d printed %q, want %q", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
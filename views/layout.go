@@ -0,0 +1,114 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+)
+
+// terminalWidth returns the width of the terminal attached to stdout, or 0
+// when stdout isn't a terminal.
+func terminalWidth() int {
+	width, _, err := term.GetSize(os.Stdout.Fd())
+	if err != nil {
+		return 0
+	}
+
+	return width
+}
+
+// fitWidth wraps rendered output to the known terminal width so long lines
+// don't break up unpredictably.
+func (m *model) fitWidth(s string) string {
+	if m.width <= 0 {
+		return s
+	}
+
+	return ansi.Wrap(s, m.width, "")
+}
+
+// fitScreen cuts the lines of the live view at the terminal width, wrapped
+// lines would make the view taller than measured and jump around.
+func (m *model) fitScreen(s string) string {
+	if m.width <= 0 {
+		return s
+	}
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, m.width, "…")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// visibleRows pages through the rendered rows to fit room lines, scrolling
+// so the row under the cursor stays visible and noting how many rows are
+// hidden above and below it.
+func (m *model) visibleRows(rows []string, room int) []string {
+	total := 0
+	for _, row := range rows {
+		total += lineCount(row)
+	}
+	if total <= room || len(rows) == 0 {
+		m.scroll = 0
+		return rows
+	}
+
+	// the notes about hidden rows take a line each
+	room = max(room-2, 1)
+	cursor := min(m.cursor, len(rows)-1)
+	m.scroll = min(m.scroll, cursor)
+
+	// the rows fitting from the first shown one, up to the returned index
+	fit := func(from int) int {
+		end, used := from, 0
+		for end < len(rows) && used+lineCount(rows[end]) <= room {
+			used += lineCount(rows[end])
+			end++
+		}
+		return end
+	}
+	for m.scroll < cursor && fit(m.scroll) <= cursor {
+		m.scroll++
+	}
+	end := fit(m.scroll)
+
+	visible := []string{}
+	if end == m.scroll {
+		// the row alone is taller than the room, such as expanded output
+		lines := strings.SplitAfter(rows[m.scroll], "\n")
+		visible = append(visible, strings.Join(lines[:room], ""))
+		end++
+	} else {
+		visible = append(visible, rows[m.scroll:end]...)
+	}
+
+	note := lipgloss.NewStyle().Foreground(subtle)
+	if m.scroll > 0 {
+		visible = append([]string{note.Render(fmt.Sprintf("↑ %d more", m.scroll)) + "\n"}, visible...)
+	}
+	if end < len(rows) {
+		visible = append(visible, note.Render(fmt.Sprintf("↓ %d more", len(rows)-end))+"\n")
+	}
+
+	return visible
+}
+
+// lineCount counts the lines of rendered output, a last line without a
+// newline included.
+func lineCount(s string) int {
+	count := strings.Count(s, "\n")
+	if s != "" && !strings.HasSuffix(s, "\n") {
+		count++
+	}
+
+	return count
+}
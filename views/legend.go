@@ -0,0 +1,57 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// legend is the key map shown in the runner's footer. Its short help only
+// lists the keys that do something in the current state, the full help
+// lists every key.
+type legend struct {
+	short []key.Binding
+	keys  keyMap
+}
+
+func (l legend) ShortHelp() []key.Binding {
+	return l.short
+}
+
+func (l legend) FullHelp() [][]key.Binding {
+	return l.keys.FullHelp()
+}
+
+// legend picks the keys worth showing for the current selection and run
+// state.
+func (m *model) legend() legend {
+	// moving the selection always applies
+	short := []key.Binding{m.keys.Up, m.keys.Down}
+
+	if m.terminating {
+		quit := m.keys.Quit
+		quit.SetHelp("q", "kill now")
+		return legend{short: []key.Binding{quit}, keys: m.keys}
+	}
+
+	if m.selected() == -1 {
+		short = append(short, m.keys.Fold)
+	} else {
+		short = append(short, m.keys.Open, m.keys.Expand, m.keys.Rebuild)
+		if _, ok := m.primaryAction(); ok {
+			short = append(short, m.keys.Primary)
+		}
+	}
+
+	if len(m.failures()) > 0 {
+		short = append(short, m.keys.Retry)
+	}
+
+	if m.showJoined {
+		short = append(short, m.keys.Joined)
+	}
+
+	short = append(short, m.keys.Help, m.keys.Quit)
+	return legend{short: short, keys: m.keys}
+}
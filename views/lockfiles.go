@@ -0,0 +1,48 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"jrmd.dev/qk/utils"
+)
+
+var lockfileWarning lipgloss.Style
+
+// TrackLockfiles hashes every project's lockfiles before running so changes
+// made by the commands can be reported once the run is done.
+func (m *model) TrackLockfiles() *model {
+	for i, proj := range m.projects {
+		m.projects[i].Lockfiles = utils.HashLockfiles(proj.FS, proj.Dir)
+	}
+	return m
+}
+
+func (m *model) checkLockfiles() {
+	for i, proj := range m.projects {
+		if proj.Lockfiles == nil {
+			continue
+		}
+		m.projects[i].ChangedLockfiles = utils.ChangedLockfiles(proj.Lockfiles, utils.HashLockfiles(proj.FS, proj.Dir))
+	}
+}
+
+func (m *model) changedLockfiles() (s string) {
+	for i, proj := range m.projects {
+		if len(proj.ChangedLockfiles) == 0 {
+			continue
+		}
+
+		s += fmt.Sprintf("%s: %s\n", renderProjectName(proj.Name, i), strings.Join(proj.ChangedLockfiles, ", "))
+	}
+
+	if s != "" {
+		s = lockfileWarning.Render("\nLockfiles changed, remember to commit them:") + "\n" + s
+	}
+
+	return s
+}
@@ -0,0 +1,224 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"jrmd.dev/qk/types"
+)
+
+var (
+	matchStyle   lipgloss.Style
+	viewerFooter lipgloss.Style
+)
+
+type viewerKeyMap struct {
+	Back   key.Binding
+	Search key.Binding
+	Next   key.Binding
+	Prev   key.Binding
+	Quit   key.Binding
+}
+
+func (k viewerKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Search, k.Next, k.Prev, k.Back, k.Quit}
+}
+
+func (k viewerKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+var viewerKeys = viewerKeyMap{
+	Back: key.NewBinding(
+		key.WithKeys("q", "esc"),
+		key.WithHelp("q", "back"),
+	),
+	Search: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "search"),
+	),
+	Next: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "next match"),
+	),
+	Prev: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "previous match"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("ctrl+c"),
+		key.WithHelp("ctrl+c", "quit"),
+	),
+}
+
+// logViewer shows the full captured output of a single project in a
+// scrollable, searchable viewport.
+type logViewer struct {
+	open       bool
+	project    int
+	viewport   viewport.Model
+	search     textinput.Model
+	searching  bool
+	term       string
+	matches    []int
+	matchIndex int
+}
+
+func newLogViewer() logViewer {
+	search := textinput.New()
+	search.Prompt = "/"
+
+	return logViewer{
+		viewport: viewport.New(80, 20),
+		search:   search,
+	}
+}
+
+// projectLog joins the captured output of every script in a project.
+func (m *model) projectLog(index int) string {
+	s := ""
+	for _, script := range m.projects[index].Scripts {
+		s += fmt.Sprintf("%s\n", script.Renderer.Render(script, types.RenderOptions{ShowStatus: true}))
+		s += script.OutputString()
+		s += "\n"
+	}
+
+	return s
+}
+
+func (m *model) openViewer(index int) tea.Cmd {
+	m.viewer.open = true
+	m.viewer.project = index
+	m.viewer.term = ""
+	m.viewer.matches = nil
+	m.resizeViewer()
+	m.refreshViewer()
+	m.viewer.viewport.GotoBottom()
+
+	return tea.EnterAltScreen
+}
+
+func (m *model) closeViewer() tea.Cmd {
+	m.viewer.open = false
+	m.viewer.searching = false
+	m.viewer.search.Blur()
+
+	return tea.ExitAltScreen
+}
+
+func (m *model) resizeViewer() {
+	if m.width == 0 || m.height == 0 {
+		return
+	}
+
+	// leave room for the header and footer lines
+	m.viewer.viewport.Width = m.width
+	m.viewer.viewport.Height = max(m.height-3, 1)
+}
+
+// refreshViewer reloads the viewport content, keeping it pinned to the
+// bottom when the user hasn't scrolled away.
+func (m *model) refreshViewer() {
+	atBottom := m.viewer.viewport.AtBottom()
+	lines := strings.Split(m.projectLog(m.viewer.project), "\n")
+
+	m.viewer.matches = []int{}
+	if m.viewer.term != "" {
+		term := strings.ToLower(m.viewer.term)
+		for i, line := range lines {
+			// match and highlight the text without its colours
+			if plain := ansi.Strip(line); strings.Contains(strings.ToLower(plain), term) {
+				m.viewer.matches = append(m.viewer.matches, i)
+				lines[i] = matchStyle.Render(plain)
+			}
+		}
+	}
+
+	for i, line := range lines {
+		lines[i] = withColors(line)
+	}
+
+	m.viewer.viewport.SetContent(strings.Join(lines, "\n"))
+	if atBottom && m.viewer.term == "" {
+		m.viewer.viewport.GotoBottom()
+	}
+}
+
+func (m *model) jumpToMatch(offset int) {
+	if len(m.viewer.matches) == 0 {
+		return
+	}
+
+	count := len(m.viewer.matches)
+	m.viewer.matchIndex = ((m.viewer.matchIndex+offset)%count + count) % count
+	m.viewer.viewport.SetYOffset(m.viewer.matches[m.viewer.matchIndex])
+}
+
+func (m *model) updateViewer(msg tea.KeyMsg) tea.Cmd {
+	if key.Matches(msg, viewerKeys.Quit) {
+		return tea.Sequence(m.closeViewer(), m.terminate())
+	}
+
+	if m.viewer.searching {
+		switch msg.Type {
+		case tea.KeyEnter:
+			m.viewer.searching = false
+			m.viewer.search.Blur()
+			m.viewer.term = m.viewer.search.Value()
+			m.viewer.matchIndex = 0
+			m.refreshViewer()
+			m.jumpToMatch(0)
+			return nil
+		case tea.KeyEsc:
+			m.viewer.searching = false
+			m.viewer.search.Blur()
+			return nil
+		}
+
+		var cmd tea.Cmd
+		m.viewer.search, cmd = m.viewer.search.Update(msg)
+		return cmd
+	}
+
+	switch {
+	case key.Matches(msg, viewerKeys.Back):
+		return m.closeViewer()
+	case key.Matches(msg, viewerKeys.Search):
+		m.viewer.searching = true
+		m.viewer.search.SetValue("")
+		return m.viewer.search.Focus()
+	case key.Matches(msg, viewerKeys.Next):
+		m.jumpToMatch(1)
+		return nil
+	case key.Matches(msg, viewerKeys.Prev):
+		m.jumpToMatch(-1)
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.viewer.viewport, cmd = m.viewer.viewport.Update(msg)
+	return cmd
+}
+
+func (m *model) viewerView() string {
+	proj := m.projects[m.viewer.project]
+	header := fmt.Sprintf("%s  %s", title.Render(proj.Name), subtitle.Render(proj.Dir))
+
+	footer := m.help.View(viewerKeys)
+	if m.viewer.searching {
+		footer = m.viewer.search.View()
+	} else if m.viewer.term != "" {
+		footer = viewerFooter.Render(fmt.Sprintf("%d matches for %q", len(m.viewer.matches), m.viewer.term)) + "  " + footer
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s", header, m.viewer.viewport.View(), footer)
+}
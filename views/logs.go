@@ -0,0 +1,132 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// runLogDir returns the directory this run's log files are written to.
+func runLogDir(conf utils.Config, start time.Time) string {
+	base := utils.LogBaseDir(conf)
+	if base == "" {
+		return ""
+	}
+
+	return path.Join(base, start.Format(utils.RunDirFormat))
+}
+
+// logFileName builds a file name for a script which is safe to use on disk
+// and unique within its project.
+func logFileName(index int, command *types.Command) string {
+	name := strings.Join(append([]string{command.Script}, command.Args...), " ")
+	name = unsafeFileChars.ReplaceAllString(name, "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+
+	return fmt.Sprintf("%d-%s.log", index, name)
+}
+
+// openLog creates the log file for a command, returning nil when logging is
+// disabled or the file cannot be created.
+func openLog(command *types.Command) *os.File {
+	if command.LogFile == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(path.Dir(command.LogFile), 0o755); err != nil {
+		return nil
+	}
+
+	file, err := os.Create(command.LogFile)
+	if err != nil {
+		return nil
+	}
+
+	return file
+}
+
+// defaultFailureLines is how many lines of a failed command's output are
+// printed once done, unless configured otherwise.
+const defaultFailureLines = 20
+
+// failureLines resolves how many lines of failed output to print from the
+// flag, the config and the default, in that order. Negative values print
+// none.
+func failureLines(conf utils.Config, flag int) int {
+	switch {
+	case flag != 0:
+		return flag
+	case conf.FailureLines != 0:
+		return conf.FailureLines
+	}
+
+	return defaultFailureLines
+}
+
+// failureTails prints the last lines of every failed command's output, so a
+// failure can be understood after qk exits without having toggled debug
+// mode. Debug mode and interactive runs already showed the output.
+func (m *model) failureTails() (s string) {
+	if m.failureLines <= 0 || m.showStdout || m.interactive {
+		return s
+	}
+
+	for i, proj := range m.projects {
+		for _, script := range proj.Scripts {
+			output := strings.TrimRight(script.Output.String(), "\n")
+			if script.Status != "failed" || strings.TrimSpace(output) == "" {
+				continue
+			}
+
+			lines := strings.Split(output, "\n")
+			if len(lines) > m.failureLines {
+				lines = lines[len(lines)-m.failureLines:]
+			}
+
+			s += fmt.Sprintf("\n%s (%s), last %d lines:\n", renderProjectName(proj.Name, i), script.Renderer.Render(script, types.RenderOptions{}), len(lines))
+			for _, line := range lines {
+				s += "  " + renderOutputLine(line) + "\n"
+			}
+		}
+	}
+
+	return s
+}
+
+// failedLogs lists the log file of every failed command so it can be
+// inspected after qk exits.
+func (m *model) failedLogs() (s string) {
+	for i, proj := range m.projects {
+		for _, script := range proj.Scripts {
+			if script.Status != "failed" || script.LogFile == "" {
+				continue
+			}
+
+			s += fmt.Sprintf(
+				"%s (%s): %s\n",
+				renderProjectName(proj.Name, i),
+				script.Renderer.Render(script, types.RenderOptions{}),
+				script.LogFile,
+			)
+		}
+	}
+
+	if s != "" {
+		s = "\nLogs for failed commands:\n" + s
+	}
+
+	return s
+}
@@ -0,0 +1,67 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"jrmd.dev/qk/types"
+)
+
+// AddMatrixCommand adds the command to every project once per combination
+// of environment variables. The combinations of a project share its
+// directory, so they hold the project's lock and run one at a time, each
+// running whether the one before failed or not. The runner then ends with a
+// grid of the results instead of the usual summary.
+func (m *model) AddMatrixCommand(combinations [][]string, renderer func(label string) types.CommandRenderer, script string, args ...string) *model {
+	locks := map[int]*sync.Mutex{}
+	for i := range m.projects {
+		locks[i] = &sync.Mutex{}
+	}
+
+	for _, combination := range combinations {
+		label := strings.Join(combination, " ")
+		m.matrix = append(m.matrix, label)
+
+		for i := range m.projects {
+			cmd := m.newCommand(i, renderer(label), script, args)
+			cmd.Env = append(cmd.Env, combination...)
+			cmd.Lock = locks[i]
+			m.projects[i].Scripts = append(m.projects[i].Scripts, cmd)
+		}
+	}
+
+	return m
+}
+
+// matrixGrid renders a row per project and a column per combination.
+func (m *model) matrixGrid() string {
+	cells := [][]string{}
+	for i, proj := range m.projects {
+		row := []string{renderProjectName(proj.Name, i)}
+		for _, script := range proj.Scripts {
+			_, execution := phases(script)
+			row = append(row, fmt.Sprintf("%s %s", statusText(script.Status), timingStyle.Render(short(execution).String())))
+		}
+		cells = append(cells, row)
+	}
+
+	t := table.New().
+		Border(lipgloss.RoundedBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(subtle)).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return summaryHeader
+			}
+			return summaryCell
+		}).
+		Headers(append([]string{"Project"}, m.matrix...)...).
+		Rows(cells...)
+
+	return t.String() + "\n"
+}
@@ -0,0 +1,30 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// notifyFailure rings the terminal bell or starts the configured command,
+// such as one playing a sound, when a command fails.
+func (m *model) notifyFailure() {
+	switch m.conf.NotifyOnFailure {
+	case "":
+		return
+	case "bell":
+		fmt.Fprint(os.Stderr, "\a")
+	default:
+		shell := os.Getenv("SHELL")
+		if shell == "" {
+			shell = "sh"
+		}
+		c := exec.Command(shell, "-c", m.conf.NotifyOnFailure)
+		if err := c.Start(); err == nil {
+			go func() { _ = c.Wait() }()
+		}
+	}
+}
@@ -0,0 +1,36 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"slices"
+
+	"jrmd.dev/qk/utils"
+)
+
+// moveSelected moves the project under the cursor past its neighbour within
+// the same group, keeping it selected, and persists the new order for the
+// working directory.
+func (m *model) moveSelected(offset int) {
+	rows := m.rows()
+	target := m.cursor + offset
+	if m.cursor >= len(rows) || target < 0 || target >= len(rows) {
+		return
+	}
+
+	from, to := rows[m.cursor], rows[target]
+	if from.project == -1 || to.project == -1 || from.group != to.group {
+		return
+	}
+
+	a, b := slices.Index(m.order, from.project), slices.Index(m.order, to.project)
+	m.order[a], m.order[b] = m.order[b], m.order[a]
+	m.cursor = target
+
+	names := []string{}
+	for _, i := range m.order {
+		names = append(names, m.projects[i].Name)
+	}
+	_ = utils.SaveOrder(m.root, names)
+}
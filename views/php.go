@@ -0,0 +1,50 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+// CheckPhp warns about projects about to run composer install with a php
+// that doesn't fit the require.php or config.platform.php of their
+// composer.json, before composer fails on it or installs dependencies for
+// another version. Call it once the commands have been added.
+func (m *model) CheckPhp() *model {
+	warn := lipgloss.NewStyle().Foreground(errColor)
+
+	for i, proj := range m.projects {
+		if !slices.ContainsFunc(proj.Scripts, isComposerInstall) {
+			continue
+		}
+
+		req, ok := utils.ReadPhpRequirement(proj.FS, proj.Dir)
+		if !ok {
+			continue
+		}
+
+		binary := utils.PhpBinary(m.conf, proj.Name)
+		version, err := utils.PhpVersion(binary)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %s\n", renderProjectName(proj.Name, i), warn.Render(err.Error()))
+			continue
+		}
+
+		if reason := req.Mismatch(version); reason != "" {
+			fmt.Fprintf(os.Stderr, "%s %s\n", renderProjectName(proj.Name, i), warn.Render(fmt.Sprintf("%s but %s is %s", reason, binary, version)))
+		}
+	}
+
+	return m
+}
+
+func isComposerInstall(cmd *types.Command) bool {
+	return cmd.Script == "composer" && len(cmd.Args) > 0 && cmd.Args[0] == "install"
+}
@@ -0,0 +1,155 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"jrmd.dev/qk/utils"
+)
+
+var errPickAborted = errors.New("project selection aborted")
+
+type pickItem struct {
+	file     utils.File
+	selected bool
+}
+
+func (i pickItem) FilterValue() string { return i.file.Name }
+
+type pickDelegate struct{}
+
+func (d pickDelegate) Height() int                             { return 1 }
+func (d pickDelegate) Spacing() int                            { return 0 }
+func (d pickDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d pickDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	item, ok := listItem.(pickItem)
+	if !ok {
+		return
+	}
+
+	box := "[ ]"
+	if item.selected {
+		box = lipgloss.NewStyle().Foreground(special).Render("[x]")
+	}
+
+	name := item.file.Name
+	if index == m.Index() {
+		name = projectSelected(name)
+	}
+
+	fmt.Fprintf(w, "%s %s", box, name)
+}
+
+type pickerKeyMap struct {
+	Toggle    key.Binding
+	ToggleAll key.Binding
+	Confirm   key.Binding
+}
+
+var pickerKeys = pickerKeyMap{
+	Toggle: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "toggle"),
+	),
+	ToggleAll: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "toggle all"),
+	),
+	Confirm: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "run"),
+	),
+}
+
+type picker struct {
+	list      list.Model
+	confirmed bool
+}
+
+func (p *picker) Init() tea.Cmd {
+	return nil
+}
+
+func (p *picker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.list.SetSize(msg.Width, msg.Height)
+		return p, nil
+	case tea.KeyMsg:
+		if p.list.FilterState() == list.Filtering {
+			break
+		}
+
+		switch {
+		case key.Matches(msg, pickerKeys.Toggle):
+			if item, ok := p.list.SelectedItem().(pickItem); ok {
+				item.selected = !item.selected
+				return p, p.list.SetItem(p.list.Index(), item)
+			}
+		case key.Matches(msg, pickerKeys.ToggleAll):
+			items := p.list.Items()
+			all := utils.All(items, func(i list.Item) bool {
+				return i.(pickItem).selected
+			})
+			for i, listItem := range items {
+				item := listItem.(pickItem)
+				item.selected = !all
+				p.list.SetItem(i, item)
+			}
+			return p, nil
+		case key.Matches(msg, pickerKeys.Confirm):
+			p.confirmed = true
+			return p, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	p.list, cmd = p.list.Update(msg)
+	return p, cmd
+}
+
+func (p *picker) View() string {
+	return p.list.View()
+}
+
+// PickProjects shows an interactive multi-select list of the discovered
+// projects and returns the ones that were checked.
+func PickProjects(projects []utils.File) ([]utils.File, error) {
+	items := []list.Item{}
+	for _, project := range projects {
+		items = append(items, pickItem{file: project})
+	}
+
+	l := list.New(items, pickDelegate{}, 40, min(len(projects)+6, 20))
+	l.Title = "Select projects"
+	l.Styles.Title = title
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{pickerKeys.Toggle, pickerKeys.ToggleAll, pickerKeys.Confirm}
+	}
+
+	p := &picker{list: l}
+	if _, err := tea.NewProgram(p, tea.WithAltScreen()).Run(); err != nil {
+		return nil, err
+	}
+
+	if !p.confirmed {
+		return nil, errPickAborted
+	}
+
+	picked := []utils.File{}
+	for _, listItem := range p.list.Items() {
+		if item := listItem.(pickItem); item.selected {
+			picked = append(picked, item.file)
+		}
+	}
+
+	return picked, nil
+}
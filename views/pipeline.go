@@ -0,0 +1,40 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"jrmd.dev/qk/pipeline"
+	"jrmd.dev/qk/types"
+)
+
+// AddPipeline adds the commands the pipeline name of script returns for
+// each project, leaving out the projects it returns none for. Each command
+// is a stage of its own, so they run one after the other and the rest are
+// skipped once one fails.
+func (m *model) AddPipeline(script *pipeline.Script, name string, renderer types.CommandRenderer, args ...string) *model {
+	for i, proj := range m.projects {
+		commands, err := script.Commands(name, proj, args)
+		if err != nil {
+			fmt.Println(lipgloss.NewStyle().Foreground(errColor).Render(fmt.Sprintf("Error: %s: %s", proj.Name, err)))
+			os.Exit(1)
+		}
+		if len(commands) == 0 {
+			slog.Info("pipeline has no commands for project", "project", proj.Name, "pipeline", name)
+			continue
+		}
+
+		for k, command := range commands {
+			cmd := m.newCommand(i, renderer, command[0], command[1:])
+			cmd.Stage = k
+			m.projects[i].Scripts = append(m.projects[i].Scripts, cmd)
+		}
+	}
+
+	return m
+}
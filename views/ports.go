@@ -0,0 +1,81 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/charmbracelet/lipgloss"
+	"jrmd.dev/qk/utils"
+)
+
+// AssignPorts sets a distinct PORT on the commands of every project added
+// afterwards, so dev servers defaulting to the same port don't collide.
+func (m *model) AssignPorts() *model {
+	names := []string{}
+	for _, proj := range m.projects {
+		names = append(names, proj.Name)
+	}
+
+	ports, err := utils.AssignPorts(m.conf, names)
+	if err != nil {
+		fmt.Println(lipgloss.NewStyle().Foreground(errColor).Render(fmt.Sprintf("Error: %s", err)))
+		os.Exit(1)
+	}
+
+	byDir := map[string]int{}
+	for i := range m.projects {
+		m.projects[i].Port = ports[i]
+		byDir[m.projects[i].Dir] = ports[i]
+	}
+	if err := utils.SavePorts(byDir); err != nil {
+		slog.Warn("could not record the assigned ports", "err", err)
+	}
+
+	return m
+}
+
+// FreeStalePorts looks for processes still listening on each project's
+// configured port, or the one it was last assigned, and asks whether to
+// kill them before anything is started.
+func (m *model) FreeStalePorts() *model {
+	last := utils.LastPorts()
+	reader := bufio.NewReader(os.Stdin)
+
+	for i, proj := range m.projects {
+		port, ok := m.conf.Ports[proj.Name]
+		if !ok {
+			port, ok = last[proj.Dir]
+		}
+		if !ok {
+			continue
+		}
+
+		for _, pid := range utils.ListeningPids(port) {
+			fmt.Printf(
+				"%s port %d is in use by %s (pid %d). Kill it? [y/N] ",
+				renderProjectName(proj.Name, i),
+				port,
+				utils.ProcessName(pid),
+				pid,
+			)
+
+			answer, _ := reader.ReadString('\n')
+			if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+				continue
+			}
+
+			if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
+				fmt.Println(lipgloss.NewStyle().Foreground(errColor).Render(fmt.Sprintf("Error: could not kill %d: %s", pid, err)))
+			}
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,65 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"jrmd.dev/qk/utils"
+)
+
+type primaryDoneMessage struct {
+	action string
+	err    error
+}
+
+// primaryAction returns the primary URL or command of the selected project,
+// falling back to the URL its dev server announced.
+func (m *model) primaryAction() (string, bool) {
+	index := m.selected()
+	if index == -1 {
+		return "", false
+	}
+
+	if action, ok := utils.PrimaryAction(m.conf, m.root, m.projects[index]); ok {
+		return action, true
+	}
+
+	return m.projects[index].URL, m.projects[index].URL != ""
+}
+
+// openPrimary opens the selected project's primary URL in the browser, or
+// hands the terminal to its primary command until it exits.
+func (m *model) openPrimary() tea.Cmd {
+	action, ok := m.primaryAction()
+	if !ok {
+		return nil
+	}
+
+	if utils.IsURL(action) {
+		return func() tea.Msg {
+			return primaryDoneMessage{action, utils.OpenURL(action)}
+		}
+	}
+
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "sh"
+	}
+	c := exec.Command(shell, "-c", action)
+	c.Dir = m.projects[m.selected()].Dir
+	return tea.ExecProcess(c, func(err error) tea.Msg { return primaryDoneMessage{action, err} })
+}
+
+// primaryNotice describes a primary action which failed.
+func primaryNotice(msg primaryDoneMessage) string {
+	if msg.err == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("Error: %s: %s", msg.action, msg.err)
+}
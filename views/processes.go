@@ -0,0 +1,49 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+// trackedProcess is the record of a running command under ~/.qk/run, which
+// lets qk ps and qk kill find it should qk be killed before cleaning up.
+type trackedProcess struct {
+	sync.Mutex
+	process *utils.Process
+}
+
+// trackProcess records the command's process once it started. Attached
+// commands, run by qk -i, stay in qk's process group.
+func trackProcess(project types.Project, command *types.Command, attached bool) *trackedProcess {
+	tracked := &trackedProcess{}
+	root, _ := os.Getwd()
+
+	command.OnStart = func(pid int) {
+		p := utils.Process{Project: project.Name, Dir: project.Dir, Root: root, Command: commandLine(command), Pid: pid, Started: time.Now(), Owner: os.Getpid(), LogFile: command.LogFile, Attached: attached}
+		if err := utils.SaveProcess(p); err != nil {
+			return
+		}
+
+		tracked.Lock()
+		defer tracked.Unlock()
+		tracked.process = &p
+	}
+
+	return tracked
+}
+
+// forget removes the record once the command exited.
+func (t *trackedProcess) forget() {
+	t.Lock()
+	defer t.Unlock()
+	if t.process != nil {
+		_ = t.process.Forget()
+	}
+}
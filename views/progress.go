@@ -0,0 +1,61 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func newProgressBar() progress.Model {
+	start, end := theme.ProgressStart.Dark, theme.ProgressEnd.Dark
+	if !lipgloss.HasDarkBackground() {
+		start, end = theme.ProgressStart.Light, theme.ProgressEnd.Light
+	}
+
+	return progress.New(progress.WithGradient(start, end), progress.WithoutPercentage())
+}
+
+// progressCounts returns how many commands have completed, however they
+// ended, how many failed and how many there are.
+func (m *model) progressCounts() (completed int, failed int, total int) {
+	for _, proj := range m.projects {
+		for _, script := range proj.Scripts {
+			total++
+			switch script.Status {
+			case "finished", "exited":
+				completed++
+			case "failed", "skipped":
+				completed++
+				failed++
+			}
+		}
+	}
+
+	return completed, failed, total
+}
+
+// progressBar renders the share of completed commands followed by the
+// counts and elapsed time.
+func (m *model) progressBar() string {
+	completed, failed, total := m.progressCounts()
+	if total == 0 {
+		return ""
+	}
+
+	status := fmt.Sprintf(" %d/%d done", completed, total)
+	if failed > 0 {
+		status += divider + lipgloss.NewStyle().Foreground(errColor).Render(fmt.Sprintf("%d failed", failed))
+	}
+	status += divider + m.elapsed().String()
+
+	m.progress.Width = 40
+	if m.width > 0 {
+		m.progress.Width = max(min(m.width-lipgloss.Width(status), 60), 10)
+	}
+
+	return m.progress.ViewAs(float64(completed)/float64(total)) + status + "\n\n"
+}
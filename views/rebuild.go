@@ -0,0 +1,66 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+type rebuildOptions struct {
+	script   string
+	renderer func(manager string) types.CommandRenderer
+}
+
+// RebuildOnDemand lets the selected project's script, such as build, be run
+// with a key press while everything else keeps running. Its result is shown
+// as another command of the project.
+func (m *model) RebuildOnDemand(script string, renderer func(manager string) types.CommandRenderer) *model {
+	m.rebuild = &rebuildOptions{script: script, renderer: renderer}
+	m.keys.Rebuild.SetEnabled(true)
+	m.keys.Rebuild.SetHelp("b", script+" selected")
+	return m
+}
+
+// rebuildSelected runs the on demand script of the selected project, reusing
+// the command of a previous run unless it is still going.
+func (m *model) rebuildSelected() tea.Cmd {
+	index := m.selected()
+	if m.rebuild == nil || index == -1 || m.terminating {
+		return nil
+	}
+
+	proj := m.projects[index]
+	if !utils.HasScript(m.rebuild.script)(proj) {
+		return nil
+	}
+
+	m.holding = false
+	m.watching = false
+
+	for j, script := range proj.Scripts {
+		if !script.OnDemand {
+			continue
+		}
+
+		switch script.Status {
+		case "running", "terminating", "restarting", "remediating":
+			return nil
+		}
+		return m.rerun(index, j)
+	}
+
+	manager := utils.ProjectManager(proj)
+	cmd := m.newCommand(index, m.rebuild.renderer(manager), manager, utils.RunArgs(manager, m.rebuild.script))
+	cmd.OnDemand = true
+	cmd.Queued = time.Now()
+	m.projects[index].Scripts = append(m.projects[index].Scripts, cmd)
+
+	j := len(m.projects[index].Scripts) - 1
+	m.cmdWg.Add(1)
+	return runCommand(cmd.Ctx, &m.cmdWg, m.program, m.events, index, m.projects[index], j, cmd)
+}
@@ -0,0 +1,41 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"time"
+)
+
+const minRefresh = 100 * time.Millisecond
+
+// refreshInterval slows the stopwatch and spinners down the longer nothing
+// has happened, up to maxRefresh, so idle sessions don't re-render
+// constantly.
+func refreshInterval(idle time.Duration, maxRefresh time.Duration) time.Duration {
+	if idle < time.Second {
+		return minRefresh
+	}
+
+	interval := minRefresh * time.Duration(1+idle/time.Second)
+	return min(interval, maxRefresh)
+}
+
+// markActivity resets the refresh rate after something visible changed.
+func (m *model) markActivity() {
+	m.lastActivity = time.Now()
+	m.adjustRefresh()
+}
+
+func (m *model) adjustRefresh() {
+	interval := refreshInterval(time.Since(m.lastActivity), m.maxRefresh)
+	m.stopwatch.Interval = interval
+	for i := range m.projects {
+		m.projects[i].Spinner.Spinner.FPS = interval
+	}
+}
+
+// elapsed is the wall-clock run time, rounded to the refresh rate.
+func (m *model) elapsed() time.Duration {
+	return time.Since(m.start).Round(minRefresh)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+type plainRenderer struct{}
+
+func (plainRenderer) Render(c *types.Command, opts types.RenderOptions) string {
+	return fmt.Sprintf("%s %s\n", c.Script, c.Status)
+}
+
+func (plainRenderer) Theme() types.Theme {
+	return types.Theme{}
+}
+
+// idleModel is a runner of dev servers which printed their banner and went
+// quiet, the case adaptive refresh is for.
+func idleModel(projects int) *model {
+	m := &model{
+		start:      time.Now(),
+		maxRefresh: time.Second,
+		folded:     map[string]bool{},
+		expanded:   map[int]bool{},
+		width:      120,
+		height:     50,
+	}
+	for i := range projects {
+		m.order = append(m.order, i)
+		m.projects = append(m.projects, types.Project{
+			Spinner: projectSpinner(utils.Config{}),
+			Name:    fmt.Sprintf("app-%d", i),
+			Scripts: []*types.Command{{Script: "dev", Status: "running", Renderer: plainRenderer{}}},
+		})
+	}
+
+	return m
+}
+
+// benchmarkIdle renders a minute of an idle session at the rate interval
+// picks, reporting the frames rendered.
+func benchmarkIdle(b *testing.B, interval func(idle time.Duration) time.Duration) {
+	m := idleModel(20)
+	frames := 0
+	for range b.N {
+		frames = 0
+		for idle := time.Duration(0); idle < time.Minute; idle += interval(idle) {
+			_ = m.View()
+			frames++
+		}
+	}
+	b.ReportMetric(float64(frames), "frames/min")
+}
+
+func BenchmarkRefreshFixed(b *testing.B) {
+	benchmarkIdle(b, func(time.Duration) time.Duration { return minRefresh })
+}
+
+func BenchmarkRefreshAdaptive(b *testing.B) {
+	benchmarkIdle(b, func(idle time.Duration) time.Duration { return refreshInterval(idle, time.Second) })
+}
@@ -0,0 +1,74 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+type remediatedMessage struct {
+	index       int
+	scriptIndex int
+}
+
+// remediate clears the cache of a command which failed on a known cache
+// corruption error and reruns it, once per command.
+func (m *model) remediate(index int, j int) (tea.Cmd, bool) {
+	script := m.projects[index].Scripts[j]
+	if script.Remediation != "" || m.terminating {
+		return nil, false
+	}
+
+	signature, ok := utils.MatchCorruption(script.Script, ansi.Strip(script.Output.String()))
+	if !ok {
+		return nil, false
+	}
+
+	script.Status = "remediating"
+	script.Remediation = fmt.Sprintf("cleared the %s cache after a corrupted download (%s) and ran it again", signature.Tool, strings.Join(signature.Clear, " "))
+
+	dir := m.projects[index].Dir
+	env := script.Env
+	// with --shared-cache other projects' installs may be using the cache
+	lock := m.cacheLocks[script.Script]
+	m.cmdWg.Add(1)
+	return func() tea.Msg {
+		defer m.cmdWg.Done()
+		if lock != nil {
+			lock.Lock()
+			defer lock.Unlock()
+		}
+
+		c := exec.Command(signature.Clear[0], signature.Clear[1:]...)
+		c.Dir = dir
+		c.Env = append(os.Environ(), env...)
+		_ = c.Run()
+
+		return remediatedMessage{index, j}
+	}, true
+}
+
+// remediations lists the automatic fixes applied during the run.
+func (m *model) remediations() (s string) {
+	for i, proj := range m.projects {
+		for _, script := range proj.Scripts {
+			if script.Remediation == "" {
+				continue
+			}
+
+			s += fmt.Sprintf("%s: %s %s\n", renderProjectName(proj.Name, i), script.Renderer.Render(script, types.RenderOptions{}), lipgloss.NewStyle().Foreground(accent).Render(script.Remediation))
+		}
+	}
+
+	return s
+}
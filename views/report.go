@@ -0,0 +1,82 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// REPORT_FORMATS are the formats accepted by --report. Reports are written
+// to a file, except for otlp which is sent to a collector's URL.
+var REPORT_FORMATS = []string{"csv", "trace", "otlp"}
+
+var csvHeader = []string{"started", "project", "dir", "command", "status", "exit_code", "queued_seconds", "duration_seconds", "output_bytes"}
+
+// writeReports writes the requested reports, telling the user about the ones
+// which couldn't be written rather than failing the run.
+func (m *model) writeReports() {
+	for format, file := range m.reports {
+		var err error
+		switch format {
+		case "csv":
+			err = m.writeCSV(file)
+		case "trace":
+			err = m.writeTrace(file)
+		case "otlp":
+			err = m.exportTrace(file)
+		default:
+			err = fmt.Errorf("unknown report format %q", format)
+		}
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, lipgloss.NewStyle().Foreground(errColor).Render(fmt.Sprintf("Error: could not write %s report: %s", format, err)))
+		}
+	}
+}
+
+// writeCSV appends one row per project and command to file, writing the
+// header first when the file is new, so runs accumulate in one sheet.
+func (m *model) writeCSV(file string) error {
+	info, err := os.Stat(file)
+	isNew := err != nil || info.Size() == 0
+
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if isNew {
+		_ = w.Write(csvHeader)
+	}
+
+	started := m.start.Format(time.RFC3339)
+	for _, proj := range m.projects {
+		for _, script := range proj.Scripts {
+			queued, duration := phases(script)
+			_ = w.Write([]string{
+				started,
+				proj.Name,
+				proj.Dir,
+				strings.Join(append([]string{script.Script}, script.Args...), " "),
+				script.Status,
+				strconv.Itoa(script.ExitCode),
+				strconv.FormatFloat(queued.Seconds(), 'f', 3, 64),
+				strconv.FormatFloat(duration.Seconds(), 'f', 3, 64),
+				strconv.FormatInt(script.OutputBytes.Load(), 10),
+			})
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
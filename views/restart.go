@@ -0,0 +1,158 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+	"jrmd.dev/qk/utils"
+)
+
+// restartOptions configures restarting projects when their files change.
+type restartOptions struct {
+	debounce time.Duration
+	ignore   []string
+}
+
+type filesChangedMessage struct {
+	index int
+}
+
+// RestartOnChange watches every project's source tree and restarts the
+// project's commands once its files stop changing for debounce. Paths
+// matching ignore, WatchIgnore or WATCH_IGNORE are not watched.
+func (m *model) RestartOnChange(debounce time.Duration, ignore []string) *model {
+	patterns := append(append(append([]string{}, utils.WATCH_IGNORE...), m.conf.WatchIgnore...), ignore...)
+	m.restart = &restartOptions{debounce: debounce, ignore: patterns}
+	return m
+}
+
+// projectFor returns the index of the project directory in dirs containing
+// file, preferring the deepest one.
+func projectFor(dirs []string, file string) (int, string) {
+	index, rel := -1, ""
+	for i, dir := range dirs {
+		r, err := filepath.Rel(dir, file)
+		if err != nil || r == ".." || strings.HasPrefix(r, "../") {
+			continue
+		}
+		if index == -1 || len(dir) > len(dirs[index]) {
+			index, rel = i, filepath.ToSlash(r)
+		}
+	}
+
+	return index, rel
+}
+
+// watchDir adds dir and its subdirectories to the watcher, skipping ignored
+// directories.
+func (m *model) watchDir(watcher *fsnotify.Watcher, dirs []string, dir string) {
+	_ = filepath.WalkDir(dir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return nil
+		}
+
+		if _, rel := projectFor(dirs, p); rel != "." && utils.IgnoredPath(rel, m.restart.ignore) {
+			return filepath.SkipDir
+		}
+
+		_ = watcher.Add(p)
+		return nil
+	})
+}
+
+// watchFiles starts watching the projects in the background and sends a
+// filesChangedMessage per project once changes settle.
+func (m *model) watchFiles() tea.Cmd {
+	// the watcher only reads the directories, Update keeps writing to the
+	// projects while it runs
+	dirs := []string{}
+	for _, proj := range m.projects {
+		dirs = append(dirs, proj.Dir)
+	}
+
+	return func() tea.Msg {
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			return nil
+		}
+
+		for _, dir := range dirs {
+			m.watchDir(watcher, dirs, dir)
+		}
+
+		var mu sync.Mutex
+		timers := map[int]*time.Timer{}
+
+		go func() {
+			defer watcher.Close()
+			for {
+				select {
+				case <-m.ctx.Done():
+					return
+				case <-watcher.Errors:
+				case event, ok := <-watcher.Events:
+					if !ok {
+						return
+					}
+
+					index, rel := projectFor(dirs, event.Name)
+					if index == -1 || utils.IgnoredPath(rel, m.restart.ignore) || event.Has(fsnotify.Chmod) {
+						continue
+					}
+
+					if event.Has(fsnotify.Create) {
+						m.watchDir(watcher, dirs, event.Name)
+					}
+
+					mu.Lock()
+					if timer, ok := timers[index]; ok {
+						timer.Stop()
+					}
+					timers[index] = time.AfterFunc(m.restart.debounce, func() {
+						m.program.Send(filesChangedMessage{index})
+					})
+					mu.Unlock()
+				}
+			}
+		}()
+
+		return nil
+	}
+}
+
+// restartProject stops the project's running commands, which are started
+// again once they exit, and reruns the ones which already finished. Skipped
+// commands wait again, startReady skips them while their dependencies fail.
+func (m *model) restartProject(index int) []tea.Cmd {
+	cmds := []tea.Cmd{}
+	if m.terminating {
+		return cmds
+	}
+
+	for j, script := range m.projects[index].Scripts {
+		switch script.Status {
+		case "running":
+			script.Status = "restarting"
+			script.Cancel()
+		case "finished", "failed", "exited":
+			cmds = append(cmds, m.rerun(index, j))
+		case "skipped":
+			script.Status = "waiting"
+		}
+	}
+	cmds = append(cmds, m.startReady()...)
+
+	if len(cmds) > 0 {
+		m.watching = false
+	}
+
+	return cmds
+}
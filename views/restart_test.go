@@ -0,0 +1,31 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import "testing"
+
+func TestProjectFor(t *testing.T) {
+	dirs := []string{"/work/app", "/work/app/packages/ui", "/work/api"}
+
+	tests := []struct {
+		file  string
+		index int
+		rel   string
+	}{
+		{"/work/app/src/main.ts", 0, "src/main.ts"},
+		{"/work/app/packages/ui/button.tsx", 1, "button.tsx"},
+		{"/work/api", 2, "."},
+		{"/work/apiary/index.js", -1, ""},
+		{"/elsewhere/file", -1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			index, rel := projectFor(dirs, tt.file)
+			if index != tt.index || rel != tt.rel {
+				t.Errorf("projectFor(%q) = %d, %q, want %d, %q", tt.file, index, rel, tt.index, tt.rel)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// retryProject re-dispatches every failed command of a project with a fresh
+// context and output buffers.
+func (m *model) retryProject(index int) []tea.Cmd {
+	cmds := []tea.Cmd{}
+
+	for j, script := range m.projects[index].Scripts {
+		if script.Status != "failed" {
+			continue
+		}
+
+		// a retry starts over, remediate may fix the cache again
+		script.Remediation = ""
+		cmds = append(cmds, m.rerun(index, j))
+	}
+
+	return cmds
+}
+
+// rerun dispatches a command again with a fresh context, stopped with the
+// run, and output buffers.
+func (m *model) rerun(index int, j int) tea.Cmd {
+	script := m.projects[index].Scripts[j]
+	script.Ctx, script.Cancel = context.WithCancel(m.ctx)
+	script.Status = "running"
+	script.Queued = time.Now()
+	script.Finish = time.Time{}
+	script.ExitCode = 0
+	script.Output = bytes.NewBuffer([]byte{})
+	script.Stdout = bytes.NewBuffer([]byte{})
+	script.Stderr = bytes.NewBuffer([]byte{})
+	script.Truncated.Store(false)
+	script.OutputBytes.Store(0)
+	delete(m.liveOutput, outputKey(index, j))
+
+	m.cmdWg.Add(1)
+	return runCommand(script.Ctx, &m.cmdWg, m.program, m.events, index, m.projects[index], j, script)
+}
+
+// retry re-runs the failed commands of the selected project, or of every
+// project when the selected one has nothing to retry or a group header is
+// selected.
+func (m *model) retry() tea.Cmd {
+	// nothing new starts while shutting down
+	if m.terminating {
+		return nil
+	}
+
+	cmds := []tea.Cmd{}
+	if selected := m.selected(); selected != -1 {
+		cmds = m.retryProject(selected)
+	}
+
+	if len(cmds) == 0 {
+		for i := range m.projects {
+			cmds = append(cmds, m.retryProject(i)...)
+		}
+	}
+
+	// skipped commands wait again, startReady skips the ones whose
+	// dependencies are still failing
+	for _, proj := range m.projects {
+		for _, script := range proj.Scripts {
+			if script.Status == "skipped" {
+				script.Status = "waiting"
+			}
+		}
+	}
+	cmds = append(cmds, m.startReady()...)
+
+	if len(cmds) > 0 {
+		m.holding = false
+		m.watching = false
+	}
+
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+// CheckDependencies looks for projects whose dependencies look out of date,
+// because their lockfile changed since the last install or they were never
+// installed. It warns about them, or with install runs the install first.
+// Call it once the commands have been added, projects without commands
+// aren't checked. Workspace members share the dependencies installed by
+// their root, so the root is checked in their place and with install they
+// wait for its install.
+func (m *model) CheckDependencies(install bool, renderer func(tool string) types.CommandRenderer) *model {
+	warn := lipgloss.NewStyle().Foreground(errColor)
+
+	// the roots of the workspaces whose members have commands, with the
+	// members waiting on them
+	members := map[string][]int{}
+	for i, proj := range m.projects {
+		if len(proj.Scripts) > 0 && utils.IsWorkspaceMember(proj) {
+			members[proj.Workspace] = append(members[proj.Workspace], i)
+		}
+	}
+
+	for i, proj := range m.projects {
+		if utils.IsWorkspaceMember(proj) || (len(proj.Scripts) == 0 && len(members[proj.Dir]) == 0) {
+			continue
+		}
+
+		stale := utils.StaleDependencies(proj.FS, proj.Dir)
+		if len(stale) == 0 {
+			continue
+		}
+
+		if !install {
+			for _, lockfile := range utils.LOCKFILES {
+				if reason, ok := stale[lockfile]; ok {
+					fmt.Fprintf(os.Stderr, "%s %s\n", renderProjectName(proj.Name, i), warn.Render(reason+", run qk install or pass --auto-install"))
+				}
+			}
+			continue
+		}
+
+		tools := [][]string{}
+		if _, ok := stale["composer.lock"]; ok {
+			tools = append(tools, []string{"composer", "install"})
+		}
+		if len(stale) > len(tools) {
+			if manager := utils.PackageManager(proj.FS, proj.Dir); manager != "" {
+				tools = append(tools, append([]string{manager}, utils.InstallArgs(manager)...))
+			}
+		}
+
+		for _, tool := range tools {
+			cmd := m.newCommand(i, renderer(tool[0]), tool[0], tool[1:])
+			cmd.Stage = stageInstall
+			m.projects[i].Scripts = slices.Insert(m.projects[i].Scripts, 0, cmd)
+
+			for _, member := range members[proj.Dir] {
+				m.installs[member] = append(m.installs[member], cmd)
+			}
+		}
+	}
+
+	// members whose root isn't part of the run can't wait for its install
+	for root, indices := range members {
+		if slices.ContainsFunc(m.projects, func(proj types.Project) bool { return proj.Dir == root }) {
+			continue
+		}
+
+		stale := utils.StaleDependencies(m.projects[indices[0]].FS, root)
+		for _, lockfile := range utils.LOCKFILES {
+			if reason, ok := stale[lockfile]; ok {
+				fmt.Fprintf(os.Stderr, "%s %s\n", root, warn.Render(reason+", run qk install in the workspace root"))
+			}
+		}
+	}
+
+	if install {
+		m.CheckPhp()
+	}
+
+	return m
+}
@@ -0,0 +1,67 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+// AddTemplateCommand adds a command to every project after substituting the
+// project's variables, such as {{.Name}} or {{.Dir}}, into the script and
+// each argument.
+func (m *model) AddTemplateCommand(renderer types.CommandRenderer, script string, args ...string) *model {
+	for i, proj := range m.projects {
+		vars := utils.ProjectVars(m.root, proj)
+
+		expanded := []string{}
+		for _, text := range append([]string{script}, args...) {
+			value, err := utils.ExpandTemplate(text, vars)
+			if err != nil {
+				fmt.Println(lipgloss.NewStyle().Foreground(errColor).Render(fmt.Sprintf("Error: %s", err)))
+				os.Exit(1)
+			}
+			expanded = append(expanded, value)
+		}
+
+		cmd := m.newCommand(i, renderer, expanded[0], expanded[1:])
+		m.projects[i].Scripts = append(m.projects[i].Scripts, cmd)
+	}
+
+	return m
+}
+
+// AddTemplateLine adds line to every project, substituting the project's
+// variables quoted so each stays a single word. The expanded line is run by
+// shell with -c, or split into words like sh would when shell is empty.
+func (m *model) AddTemplateLine(renderer types.CommandRenderer, line string, shell string) *model {
+	for i, proj := range m.projects {
+		expanded, err := utils.ExpandTemplate(line, utils.ProjectVars(m.root, proj).Quoted())
+		if err != nil {
+			fmt.Println(lipgloss.NewStyle().Foreground(errColor).Render(fmt.Sprintf("Error: %s", err)))
+			os.Exit(1)
+		}
+
+		words := []string{shell, "-c", expanded}
+		if shell == "" {
+			words, err = utils.SplitWords(expanded)
+			if err == nil && len(words) == 0 {
+				err = fmt.Errorf("%q is empty once expanded", line)
+			}
+			if err != nil {
+				fmt.Println(lipgloss.NewStyle().Foreground(errColor).Render(fmt.Sprintf("Error: %s", err)))
+				os.Exit(1)
+			}
+		}
+
+		cmd := m.newCommand(i, renderer, words[0], words[1:])
+		m.projects[i].Scripts = append(m.projects[i].Scripts, cmd)
+	}
+
+	return m
+}
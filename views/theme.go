@@ -0,0 +1,102 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/lipgloss"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+var (
+	// theme is what the runner renders with, see SetTheme.
+	theme types.Theme
+
+	// checkGlyph and crossGlyph mark projects whose commands all finished
+	// and ones with a failed command, see setGlyphs.
+	checkGlyph = "✓"
+	crossGlyph = "x"
+)
+
+func init() {
+	SetTheme(types.DefaultTheme())
+}
+
+// SetTheme makes the runner and the views around it render with t. Call it
+// before creating the runner, the styles built from the colours are
+// rebuilt here rather than when rendering.
+func SetTheme(t types.Theme) {
+	theme = t
+
+	normal = t.Text
+	output = t.Output
+	subtle = t.Faint
+	muted = t.Subtle
+	highlight = t.Primary
+	special = t.Success
+	errColor = t.Error
+	warning = t.Warning
+	accent = t.Accent
+	projectListColours = t.Projects
+
+	title = lipgloss.NewStyle().
+		Padding(0, 1).
+		Bold(true).
+		Foreground(normal).
+		Background(highlight)
+
+	subtitle = lipgloss.NewStyle().
+		Padding(0, 1).
+		Foreground(accent)
+
+	divider = lipgloss.NewStyle().
+		SetString("•").
+		Padding(0, 1).
+		Foreground(subtle).
+		String()
+
+	setGlyphs(checkGlyph, crossGlyph)
+
+	timingStyle = lipgloss.NewStyle().Foreground(muted)
+	summaryHeader = lipgloss.NewStyle().Bold(true).Foreground(highlight).Padding(0, 1)
+	lockfileWarning = lipgloss.NewStyle().Foreground(warning)
+	matchStyle = lipgloss.NewStyle().Foreground(normal).Background(highlight)
+	viewerFooter = lipgloss.NewStyle().Foreground(muted)
+	truncatedNotice = lipgloss.NewStyle().Foreground(muted).Render(" (output truncated, see log file)")
+	joinedSeparator = lipgloss.NewStyle().Foreground(subtle).Render("│")
+}
+
+// setGlyphs replaces the marks of finished and failed projects, an empty
+// glyph keeps the current one.
+func setGlyphs(check string, failed string) {
+	if check != "" {
+		checkGlyph = check
+	}
+	if failed != "" {
+		crossGlyph = failed
+	}
+
+	checkMark = lipgloss.NewStyle().SetString(checkGlyph).
+		Foreground(special).
+		PaddingRight(1).
+		String()
+	cross = lipgloss.NewStyle().SetString(crossGlyph).
+		Foreground(errColor).
+		PaddingRight(1).
+		String()
+}
+
+// projectSpinner is the spinner a project shows while its commands run,
+// the one the Spinner config names or else dots.
+func projectSpinner(conf utils.Config) spinner.Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	if configured, ok := types.SPINNERS[conf.Spinner]; ok {
+		s.Spinner = configured
+	}
+	s.Style = lipgloss.NewStyle().Foreground(theme.Spinner)
+
+	return s
+}
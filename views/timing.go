@@ -0,0 +1,137 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/charmbracelet/x/ansi"
+	"jrmd.dev/qk/types"
+)
+
+var (
+	timingStyle   lipgloss.Style
+	summaryHeader lipgloss.Style
+	summaryCell   = lipgloss.NewStyle().Padding(0, 1)
+)
+
+// runTiming breaks a run down into phases. Queued and execution are summed
+// across commands, so they can exceed the total when commands overlap.
+type runTiming struct {
+	discovery time.Duration
+	queued    time.Duration
+	execution time.Duration
+	total     time.Duration
+}
+
+// phases returns how long a command waited to start and how long it ran.
+func phases(c *types.Command) (queued time.Duration, execution time.Duration) {
+	if !c.Queued.IsZero() && !c.Start.IsZero() {
+		queued = c.Start.Sub(c.Queued)
+	}
+	if !c.Start.IsZero() && !c.Finish.IsZero() {
+		execution = c.Finish.Sub(c.Start)
+	}
+
+	return queued, execution
+}
+
+// short rounds a duration for display, keeping sub-millisecond precision
+// for the quick phases.
+func short(d time.Duration) time.Duration {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond)
+	}
+
+	return d.Round(time.Millisecond)
+}
+
+func (m *model) timing() runTiming {
+	timing := runTiming{discovery: m.discovery, total: time.Since(m.start)}
+	for _, proj := range m.projects {
+		for _, script := range proj.Scripts {
+			queued, execution := phases(script)
+			timing.queued += queued
+			timing.execution += execution
+		}
+	}
+
+	return timing
+}
+
+// timingSummary renders the run's phases followed by a table of every
+// command, slowest first.
+func (m *model) timingSummary() string {
+	timing := m.timing()
+	s := timingStyle.Render(fmt.Sprintf("discovery %s • queued %s • execution %s",
+		short(timing.discovery),
+		short(timing.queued),
+		short(timing.execution),
+	)) + "\n"
+
+	type row struct {
+		project   int
+		script    *types.Command
+		queued    time.Duration
+		execution time.Duration
+	}
+
+	rows := []row{}
+	for i, proj := range m.projects {
+		for _, script := range proj.Scripts {
+			queued, execution := phases(script)
+			rows = append(rows, row{i, script, queued, execution})
+		}
+	}
+	if len(rows) == 0 {
+		return s
+	}
+
+	slices.SortStableFunc(rows, func(a row, b row) int {
+		return cmp.Compare(b.execution, a.execution)
+	})
+
+	cells := [][]string{}
+	for _, r := range rows {
+		cells = append(cells, []string{
+			renderProjectName(m.projects[r.project].Name, r.project),
+			ansi.Truncate(strings.Join(append([]string{r.script.Script}, r.script.Args...), " "), 40, "…"),
+			statusText(r.script.Status),
+			short(r.queued).String(),
+			short(r.execution).String(),
+		})
+	}
+
+	t := table.New().
+		Border(lipgloss.RoundedBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(subtle)).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return summaryHeader
+			}
+			return summaryCell
+		}).
+		Headers("Project", "Command", "Status", "Queued", "Duration").
+		Rows(cells...)
+
+	return s + t.String() + "\n"
+}
+
+// statusText colours a command status the way the default renderer does.
+func statusText(status string) string {
+	switch status {
+	case "finished":
+		return lipgloss.NewStyle().Foreground(special).Render(status)
+	case "failed":
+		return lipgloss.NewStyle().Foreground(errColor).Render(status)
+	}
+
+	return timingStyle.Render(status)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"jrmd.dev/qk/utils"
+)
+
+// RunInTmux opens a tmux window with one pane per project running its
+// commands, named after the project, instead of showing the TUI. Inside
+// tmux the window is added to the current session, otherwise a new session
+// is created and attached, or the one a previous run created is attached.
+func (m *model) RunInTmux() {
+	errStyle := lipgloss.NewStyle().Foreground(errColor)
+	if _, err := exec.LookPath("tmux"); err != nil {
+		fmt.Println(errStyle.Render("Error: tmux is not installed"))
+		os.Exit(1)
+	}
+
+	attach := os.Getenv("TMUX") == ""
+	session := "qk-" + path.Base(m.root)
+	if _, err := tmux("has-session", "-t", "="+session); attach && err == nil {
+		fmt.Printf("tmux session %s is already running, attaching\n", session)
+		attachTmux(session)
+		return
+	}
+
+	panes := []int{}
+	for i, proj := range m.projects {
+		if len(proj.Scripts) > 0 {
+			panes = append(panes, i)
+		}
+	}
+	if len(panes) == 0 {
+		fmt.Println(errStyle.Render("Error: no project has a command to run"))
+		os.Exit(1)
+	}
+
+	markers, err := os.MkdirTemp("", "qk-tmux-")
+	if err != nil {
+		fmt.Println(errStyle.Render(fmt.Sprintf("Error: %s", err)))
+		os.Exit(1)
+	}
+
+	first := []string{"new-window", "-n", "qk"}
+	if attach {
+		first = []string{"new-session", "-d", "-s", session, "-n", "qk"}
+	}
+
+	// panes stay open once their commands exit so failures stay readable,
+	// set in the same tmux invocation so even instant failures are kept
+	first = append(first, "-P", "-F", "#{window_id} #{pane_id}", "-c", m.projects[panes[0]].Dir, m.paneCommand(panes[0], markers))
+	ids, err := tmux(append(first, ";", "set-option", "-w", "remain-on-exit", "on")...)
+	window, pane, _ := strings.Cut(ids, " ")
+	if err == nil {
+		err = m.fillTmuxWindow(window, pane, panes, markers)
+	}
+	if err != nil {
+		fmt.Println(errStyle.Render(fmt.Sprintf("Error: %s", err)))
+		os.Exit(1)
+	}
+
+	if !attach {
+		fmt.Printf("Opened %d panes in tmux window %s\n", len(panes), window)
+		return
+	}
+
+	attachTmux(window)
+}
+
+func attachTmux(target string) {
+	c := exec.Command("tmux", "attach-session", "-t", target)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	_ = c.Run()
+}
+
+// fillTmuxWindow splits window, holding the first project's pane, into a
+// pane for every other project and titles them.
+func (m *model) fillTmuxWindow(window string, first string, panes []int, markers string) error {
+	if _, err := tmux("select-pane", "-t", first, "-T", m.projects[panes[0]].Name); err != nil {
+		return err
+	}
+
+	for _, index := range panes[1:] {
+		proj := m.projects[index]
+		pane, err := tmux("split-window", "-t", window, "-P", "-F", "#{pane_id}", "-c", proj.Dir, m.paneCommand(index, markers))
+		if err != nil {
+			return err
+		}
+		if _, err := tmux("select-pane", "-t", pane, "-T", proj.Name); err != nil {
+			return err
+		}
+		// tiling after every split leaves room for the next one
+		if _, err := tmux("select-layout", "-t", window, "tiled"); err != nil {
+			return err
+		}
+	}
+
+	for _, option := range [][]string{{"pane-border-status", "top"}, {"pane-border-format", " #{pane_title} "}} {
+		if _, err := tmux("set-option", "-w", "-t", window, option[0], option[1]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// paneCommand runs the project's launchScript with sh, whatever shell
+// tmux starts panes with.
+func (m *model) paneCommand(index int, markers string) string {
+	return "sh -c " + utils.ShellQuote(m.launchScript(index, markers))
+}
+
+func tmux(args ...string) (string, error) {
+	out, err := exec.Command("tmux", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("tmux %s: %s", args[0], strings.TrimSpace(string(out)))
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,43 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"log/slog"
+
+	"jrmd.dev/qk/tooling"
+	"jrmd.dev/qk/types"
+)
+
+// AddPhase adds the commands every registered tool runs for phase to the
+// projects it detects, args being the extra arguments passed on.
+func (m *model) AddPhase(phase tooling.Phase, renderer func(tool string) types.CommandRenderer, args ...string) *model {
+	return m.AddOptionalPhase(func(types.Project) bool { return true }, phase, renderer, args...)
+}
+
+// AddOptionalPhase is AddPhase for the projects matching shouldAdd.
+func (m *model) AddOptionalPhase(shouldAdd func(types.Project) bool, phase tooling.Phase, renderer func(tool string) types.CommandRenderer, args ...string) *model {
+	added := make([]bool, len(m.projects))
+	for _, tool := range tooling.Tools() {
+		for i, proj := range m.projects {
+			if !shouldAdd(proj) || !tool.Detect(proj) {
+				continue
+			}
+
+			for _, c := range tooling.Commands(tool, phase, proj, args) {
+				cmd := m.newCommand(i, renderer(tool.Name()), c[0], c[1:])
+				m.projects[i].Scripts = append(m.projects[i].Scripts, cmd)
+				added[i] = true
+			}
+		}
+	}
+
+	for i, proj := range m.projects {
+		if !added[i] {
+			slog.Info("no tool has commands for project", "project", proj.Name, "phase", phase.String())
+		}
+	}
+
+	return m
+}
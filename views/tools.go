@@ -0,0 +1,36 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"path"
+
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+// AddTools runs each of the linters or formatters in the projects which
+// configure it, checking them or with fix fixing them.
+func (m *model) AddTools(tools []utils.Tool, fix bool, renderer func(tool string) types.CommandRenderer) *model {
+	for _, tool := range tools {
+		args := tool.Check
+		if fix {
+			args = tool.Fix
+		}
+		configured := utils.ConfiguresTool(tool)
+
+		if tool.Php {
+			vendored := utils.HasVendorBin(args[0])
+			m.AddOptionalCommand(utils.And(configured, vendored), renderer(tool.Name), path.Join("vendor", "bin", args[0]), args[1:]...)
+			m.AddOptionalCommand(utils.And(configured, utils.Not(vendored)), renderer(tool.Name), args[0], args[1:]...)
+			continue
+		}
+
+		for _, manager := range utils.NODE_MANAGERS {
+			m.AddOptionalCommand(utils.And(configured, utils.UsesManager(manager)), renderer(tool.Name), manager, append(utils.ExecArgs(manager), args...)...)
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,225 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"jrmd.dev/qk/types"
+)
+
+// traceEvent is an event of the Chrome trace format, which Perfetto and
+// chrome://tracing open.
+type traceEvent struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat,omitempty"`
+	Ph   string         `json:"ph"`
+	Ts   int64          `json:"ts"`
+	Dur  int64          `json:"dur,omitempty"`
+	Pid  int            `json:"pid"`
+	Tid  int            `json:"tid"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// ran reports whether a command got to start, commands which never did
+// have no span to show.
+func ran(script *types.Command) bool {
+	return !script.Start.IsZero()
+}
+
+// commandEnd is when a command finished, or now for one still running
+// when qk quit.
+func commandEnd(script *types.Command) time.Time {
+	if script.Finish.IsZero() {
+		return time.Now()
+	}
+
+	return script.Finish
+}
+
+// writeTrace writes the run to file as a Chrome trace, one row per project
+// and one slice per command.
+func (m *model) writeTrace(file string) error {
+	events := []traceEvent{
+		{Name: "process_name", Ph: "M", Pid: 1, Args: map[string]any{"name": runName()}},
+	}
+
+	for i, proj := range m.projects {
+		if len(proj.Scripts) == 0 {
+			continue
+		}
+
+		events = append(events, traceEvent{Name: "thread_name", Ph: "M", Pid: 1, Tid: i + 1, Args: map[string]any{"name": proj.Name}})
+		for _, script := range proj.Scripts {
+			if !ran(script) {
+				continue
+			}
+
+			queued, _ := phases(script)
+			events = append(events, traceEvent{
+				Name: commandLine(script),
+				Cat:  "command",
+				Ph:   "X",
+				Ts:   script.Start.Sub(m.start).Microseconds(),
+				Dur:  commandEnd(script).Sub(script.Start).Microseconds(),
+				Pid:  1,
+				Tid:  i + 1,
+				Args: map[string]any{"project": proj.Name, "dir": proj.Dir, "status": script.Status, "exitCode": script.ExitCode, "queuedSeconds": queued.Seconds()},
+			})
+		}
+	}
+
+	data, err := json.Marshal(map[string]any{"traceEvents": events, "displayTimeUnit": "ms"})
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, data, 0o644)
+}
+
+type otlpAttribute struct {
+	Key   string         `json:"key"`
+	Value map[string]any `json:"value"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes"`
+	Status            map[string]any  `json:"status"`
+}
+
+func otlpString(key string, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: map[string]any{"stringValue": value}}
+}
+
+func otlpInt(key string, value int) otlpAttribute {
+	// OTLP/JSON encodes 64 bit integers as strings
+	return otlpAttribute{Key: key, Value: map[string]any{"intValue": strconv.Itoa(value)}}
+}
+
+func otlpTime(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// otlpStatus maps a command status to a span status: unset, ok or error.
+func otlpStatus(status string) map[string]any {
+	switch status {
+	case "finished":
+		return map[string]any{"code": 1}
+	case "failed", "skipped":
+		return map[string]any{"code": 2, "message": status}
+	}
+
+	return map[string]any{}
+}
+
+func randomID(n int) string {
+	id := make([]byte, n)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// exportTrace sends the run to an OTLP/HTTP collector as one span for the
+// run with a child span per project command. OTEL_EXPORTER_OTLP_HEADERS
+// adds headers, such as the API key of a hosted collector.
+func (m *model) exportTrace(endpoint string) error {
+	traceID, rootID := randomID(16), randomID(8)
+	status := "finished"
+	if m.anyFailed() {
+		status = "failed"
+	}
+
+	spans := []otlpSpan{{
+		TraceID:           traceID,
+		SpanID:            rootID,
+		Name:              runName(),
+		Kind:              1,
+		StartTimeUnixNano: otlpTime(m.start),
+		EndTimeUnixNano:   otlpTime(time.Now()),
+		Attributes:        []otlpAttribute{otlpString("qk.dir", m.root)},
+		Status:            otlpStatus(status),
+	}}
+	for _, proj := range m.projects {
+		for _, script := range proj.Scripts {
+			if !ran(script) {
+				continue
+			}
+
+			spans = append(spans, otlpSpan{
+				TraceID:           traceID,
+				SpanID:            randomID(8),
+				ParentSpanID:      rootID,
+				Name:              fmt.Sprintf("%s: %s", proj.Name, commandLine(script)),
+				Kind:              1,
+				StartTimeUnixNano: otlpTime(script.Start),
+				EndTimeUnixNano:   otlpTime(commandEnd(script)),
+				Attributes: []otlpAttribute{
+					otlpString("qk.project", proj.Name),
+					otlpString("qk.dir", proj.Dir),
+					otlpString("qk.status", script.Status),
+					otlpString("process.command_line", commandLine(script)),
+					otlpInt("process.exit_code", script.ExitCode),
+				},
+				Status: otlpStatus(script.Status),
+			})
+		}
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"resourceSpans": []any{map[string]any{
+			"resource":   map[string]any{"attributes": []otlpAttribute{otlpString("service.name", "qk")}},
+			"scopeSpans": []any{map[string]any{"scope": map[string]any{"name": "jrmd.dev/qk"}, "spans": spans}},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	url := strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(url, "/v1/traces") {
+		url += "/v1/traces"
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for _, header := range strings.Split(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"), ",") {
+		if key, value, ok := strings.Cut(header, "="); ok {
+			req.Header.Set(strings.TrimSpace(key), strings.TrimSpace(value))
+		}
+	}
+
+	client := http.Client{Timeout: 10 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("%s answered %s", url, res.Status)
+	}
+
+	return nil
+}
+
+// runName names the run after the qk command line, such as "qk build".
+func runName() string {
+	return strings.Join(append([]string{"qk"}, os.Args[1:]...), " ")
+}
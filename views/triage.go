@@ -0,0 +1,224 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"jrmd.dev/qk/types"
+)
+
+type triageKeyMap struct {
+	Retry  key.Binding
+	Shell  key.Binding
+	Editor key.Binding
+	Next   key.Binding
+	Prev   key.Binding
+	Back   key.Binding
+	Quit   key.Binding
+}
+
+func (k triageKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Retry, k.Shell, k.Editor, k.Next, k.Prev, k.Back, k.Quit}
+}
+
+func (k triageKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+var triageKeys = triageKeyMap{
+	Retry: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "retry"),
+	),
+	Shell: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "shell"),
+	),
+	Editor: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "editor"),
+	),
+	Next: key.NewBinding(
+		key.WithKeys("n", "tab", "right"),
+		key.WithHelp("n", "skip"),
+	),
+	Prev: key.NewBinding(
+		key.WithKeys("p", "shift+tab", "left"),
+		key.WithHelp("p", "previous"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+}
+
+// failure identifies a failed command by project and script index.
+type failure struct {
+	project int
+	script  int
+}
+
+// triageView walks through the failed commands of a finished run one at a
+// time.
+type triageView struct {
+	open     bool
+	index    int
+	viewport viewport.Model
+}
+
+type triageExecMessage struct {
+	err error
+}
+
+func (m *model) failures() []failure {
+	failures := []failure{}
+	for _, i := range m.order {
+		for j, script := range m.projects[i].Scripts {
+			if script.Status == "failed" {
+				failures = append(failures, failure{i, j})
+			}
+		}
+	}
+
+	return failures
+}
+
+func (m *model) openTriage() tea.Cmd {
+	wasOpen := m.triage.open
+	m.triage.open = true
+	m.resizeTriage()
+	m.refreshTriage()
+
+	if wasOpen {
+		return nil
+	}
+
+	return tea.EnterAltScreen
+}
+
+func (m *model) closeTriage() tea.Cmd {
+	m.triage.open = false
+	return tea.ExitAltScreen
+}
+
+func (m *model) resizeTriage() {
+	if m.width == 0 || m.height == 0 {
+		m.triage.viewport = viewport.New(80, 20)
+		return
+	}
+
+	// leave room for the header and footer lines
+	m.triage.viewport.Width = m.width
+	m.triage.viewport.Height = max(m.height-3, 1)
+}
+
+// refreshTriage shows the output of the current failure, clamping the index
+// as failures are retried away.
+func (m *model) refreshTriage() {
+	failures := m.failures()
+	if len(failures) == 0 {
+		m.triage.viewport.SetContent("")
+		return
+	}
+
+	m.triage.index = min(max(m.triage.index, 0), len(failures)-1)
+	f := failures[m.triage.index]
+	m.triage.viewport.SetContent(m.projects[f.project].Scripts[f.script].OutputString())
+	m.triage.viewport.GotoBottom()
+}
+
+func (m *model) currentFailure() (failure, bool) {
+	failures := m.failures()
+	if len(failures) == 0 {
+		return failure{}, false
+	}
+
+	return failures[min(m.triage.index, len(failures)-1)], true
+}
+
+func (m *model) updateTriage(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, triageKeys.Quit):
+		return tea.Sequence(m.closeTriage(), m.terminate())
+	case key.Matches(msg, triageKeys.Back):
+		return m.closeTriage()
+	case key.Matches(msg, triageKeys.Next):
+		m.triage.index++
+		m.refreshTriage()
+		return nil
+	case key.Matches(msg, triageKeys.Prev):
+		m.triage.index--
+		m.refreshTriage()
+		return nil
+	}
+
+	f, ok := m.currentFailure()
+	if !ok {
+		return nil
+	}
+	dir := m.projects[f.project].Dir
+
+	switch {
+	case key.Matches(msg, triageKeys.Retry):
+		m.holding = false
+		cmd := m.rerun(f.project, f.script)
+		m.refreshTriage()
+		return cmd
+	case key.Matches(msg, triageKeys.Shell):
+		shell := os.Getenv("SHELL")
+		if shell == "" {
+			shell = "sh"
+		}
+		c := exec.Command(shell)
+		c.Dir = dir
+		return tea.ExecProcess(c, func(err error) tea.Msg { return triageExecMessage{err} })
+	case key.Matches(msg, triageKeys.Editor):
+		editor := os.Getenv("VISUAL")
+		if editor == "" {
+			editor = os.Getenv("EDITOR")
+		}
+		if editor == "" {
+			editor = "vi"
+		}
+		c := exec.Command(editor, dir)
+		c.Dir = dir
+		return tea.ExecProcess(c, func(err error) tea.Msg { return triageExecMessage{err} })
+	}
+
+	var cmd tea.Cmd
+	m.triage.viewport, cmd = m.triage.viewport.Update(msg)
+	return cmd
+}
+
+func (m *model) triageView() string {
+	footer := m.help.View(triageKeys)
+
+	f, ok := m.currentFailure()
+	if !ok {
+		return fmt.Sprintf("%s\n\n%s\n", title.Render("Triage"), lipgloss.NewStyle().Foreground(accent).Render("Retrying…")) + footer
+	}
+
+	proj := m.projects[f.project]
+	script := proj.Scripts[f.script]
+	header := fmt.Sprintf("%s  %d/%d  %s %s",
+		title.Render("Triage"),
+		min(m.triage.index, len(m.failures())-1)+1,
+		len(m.failures()),
+		projectStyle(proj.Name),
+		script.Renderer.Render(script, types.RenderOptions{ShowStatus: true}),
+	)
+
+	return fmt.Sprintf("%s\n%s\n%s", header, m.triage.viewport.View(), footer)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+// probeInterval is how often listening sockets are inspected with
+// --probe-ports.
+const probeInterval = 2 * time.Second
+
+type probePortsMessage struct{}
+
+type portsProbedMessage struct {
+	urls map[int]string
+}
+
+// detectURL remembers the first URL a project's output announces.
+func (m *model) detectURL(index int, line string) {
+	if m.projects[index].URL != "" {
+		return
+	}
+
+	if url, ok := utils.DetectURL(line); ok {
+		m.projects[index].URL = url
+	}
+}
+
+// forgetURL drops the URL of a project once nothing runs in it any more.
+func (m *model) forgetURL(index int) {
+	if !utils.Some(m.projects[index].Scripts, func(script *types.Command) bool { return script.Status == "running" }) {
+		m.projects[index].URL = ""
+	}
+}
+
+func probePortsTick() tea.Cmd {
+	return tea.Tick(probeInterval, func(time.Time) tea.Msg { return probePortsMessage{} })
+}
+
+// probePorts looks up the ports the running commands of projects without a
+// URL listen on, for servers which don't print where they listen.
+func (m *model) probePorts() tea.Cmd {
+	groups := map[int][]int{}
+	for i, proj := range m.projects {
+		if proj.URL != "" {
+			continue
+		}
+		for _, script := range proj.Scripts {
+			if script.Status == "running" && script.Pid > 0 {
+				groups[i] = append(groups[i], script.Pid)
+			}
+		}
+	}
+
+	return func() tea.Msg {
+		urls := map[int]string{}
+		for i, pgids := range groups {
+			for _, pgid := range pgids {
+				if ports := utils.ListeningPorts(pgid); len(ports) > 0 {
+					urls[i] = fmt.Sprintf("http://localhost:%d", ports[0])
+					break
+				}
+			}
+		}
+
+		return portsProbedMessage{urls}
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright © 2025 Jerome Duncan <jerome@jrmd.dev>
+*/
+package views
+
+import (
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"jrmd.dev/qk/types"
+	"jrmd.dev/qk/utils"
+)
+
+// webhookTailLines is how many lines of a failed command's output are sent
+// to the webhook when FailureLines prints none.
+const webhookTailLines = defaultFailureLines
+
+// outputTail is the last lines of a command's output without colours.
+func outputTail(script *types.Command, lines int) string {
+	output := strings.Split(strings.TrimRight(ansi.Strip(script.Output.String()), "\n"), "\n")
+	if len(output) > lines {
+		output = output[len(output)-lines:]
+	}
+
+	return strings.Join(output, "\n")
+}
+
+// sendWebhook sends the run's history entry to the configured webhook, with
+// the tail of each failed command's output.
+func (m *model) sendWebhook(entry utils.HistoryEntry) {
+	if m.conf.Notify.Webhook == "" {
+		return
+	}
+
+	lines := m.failureLines
+	if lines <= 0 {
+		lines = webhookTailLines
+	}
+
+	// the entry lists the projects with commands in order, the tails are
+	// added to a copy so the recorded entry stays as it is
+	projects := []utils.HistoryProject{}
+	k := 0
+	for _, proj := range m.projects {
+		if len(proj.Scripts) == 0 || k >= len(entry.Projects) {
+			continue
+		}
+
+		project := entry.Projects[k]
+		project.Commands = slices.Clone(project.Commands)
+		for j, script := range proj.Scripts {
+			if script.Status == "failed" && j < len(project.Commands) {
+				project.Commands[j].Tail = outputTail(script, lines)
+			}
+		}
+		projects = append(projects, project)
+		k++
+	}
+	entry.Projects = projects
+
+	host, _ := os.Hostname()
+	if err := utils.SendWebhook(m.conf.Notify, utils.WebhookSummary{HistoryEntry: entry, Host: host}); err != nil {
+		slog.Warn("could not send the run to the webhook", "err", err)
+	}
+}